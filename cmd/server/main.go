@@ -1,17 +1,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"movie_backend_go/db"
 	"movie_backend_go/db/sqlc"
 	_ "movie_backend_go/docs"
+	"movie_backend_go/internal/commentfilter"
 	"movie_backend_go/internal/handlers"
+	"movie_backend_go/internal/ingest"
+	"movie_backend_go/internal/mediagc"
 	"movie_backend_go/internal/scheduler"
+	"movie_backend_go/internal/transcode"
 	"movie_backend_go/pkg/auth"
+	"movie_backend_go/pkg/throttle"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -59,7 +66,49 @@ func main() {
 	go scheduler.UpdateDBScheduler(dbPool, defaultLogger)
 
 	queries := sqlc.New(dbPool)
-	handlerObj := handlers.HandlerObj{QuerierDB: queries, Logger: backendLogger}
+	go scheduler.CleanStreamLeasesScheduler(queries, defaultLogger)
+	go scheduler.CleanDownloadGrantsScheduler(queries, defaultLogger)
+	mediaGC := mediagc.NewCollector(queries,
+		time.Duration(getEnvInt("MEDIA_GC_GRACE_HOURS", int(mediagc.DefaultGracePeriod.Hours())))*time.Hour,
+		getEnvBool("MEDIA_GC_DELETE", false))
+	go scheduler.MediaGCScheduler(mediaGC, defaultLogger)
+	// Watch folder ingestion is enabled by setting its directory
+	if ingestDir := os.Getenv("INGEST_DIR"); ingestDir != "" {
+		watcher := ingest.NewWatcher(queries, backendLogger, ingestDir,
+			time.Duration(getEnvInt("INGEST_SCAN_SECONDS", int(ingest.DefaultScanInterval.Seconds())))*time.Second,
+			getEnvBool("INGEST_DRY_RUN", false))
+		go watcher.Run(context.Background())
+	}
+	transcode.RunWorkers(queries, backendLogger, getEnvInt("TRANSCODE_WORKERS", transcode.DefaultWorkers))
+
+	filterConfig := commentfilter.DefaultConfig()
+	filterConfig.BannedWordsDir = os.Getenv("COMMENT_BANNED_WORDS_DIR")
+	if defaultLanguage := os.Getenv("COMMENT_DEFAULT_LANGUAGE"); defaultLanguage != "" {
+		filterConfig.DefaultLanguage = defaultLanguage
+	}
+	filterConfig.MinLength = getEnvInt("COMMENT_MIN_LENGTH", filterConfig.MinLength)
+	filterConfig.MaxLength = getEnvInt("COMMENT_MAX_LENGTH", filterConfig.MaxLength)
+	filterConfig.MaxLinks = getEnvInt("COMMENT_MAX_LINKS", filterConfig.MaxLinks)
+	filterConfig.RepeatWindow = time.Duration(getEnvInt("COMMENT_REPEAT_WINDOW_SECONDS", int(filterConfig.RepeatWindow.Seconds()))) * time.Second
+	filterConfig.MaxPerWindow = getEnvInt("COMMENT_MAX_PER_WINDOW", filterConfig.MaxPerWindow)
+	commentFilter, err := commentfilter.New(queries, filterConfig)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	handlerObj := handlers.HandlerObj{
+		QuerierDB:              queries,
+		DBPool:                 dbPool,
+		Logger:                 backendLogger,
+		MaxConcurrentStreams:   getEnvInt("MAX_CONCURRENT_STREAMS", handlers.DefaultMaxConcurrentStreams),
+		StreamLimiter:          throttle.NewLimiter(int64(getEnvInt("GLOBAL_STREAM_RATE", handlers.DefaultGlobalStreamRate))),
+		StreamRate:             int64(getEnvInt("STREAM_RATE", handlers.DefaultStreamRate)),
+		AdminStreamRate:        int64(getEnvInt("ADMIN_STREAM_RATE", handlers.DefaultAdminStreamRate)),
+		MediaGC:                mediaGC,
+		CommentReportThreshold: getEnvInt("COMMENT_REPORT_THRESHOLD", handlers.DefaultCommentReportThreshold),
+		CommentFilter:          commentFilter,
+		RatingMinVotes:         getEnvInt("RATING_MIN_VOTES", handlers.DefaultRatingMinVotes),
+	}
 
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
@@ -79,10 +128,21 @@ func main() {
 
 	r.With(auth.TokenExtractionMiddleware).Get("/user/my/rating", handlerObj.GetMyUserRatingListHandler)
 	r.With(auth.TokenExtractionMiddleware).Get("/user/my/rating", handlerObj.GetMyUserRatingListHandler)
+	r.With(auth.TokenExtractionMiddleware).Get("/user/my/rating/history", handlerObj.GetMyRatingHistoryHandler)
 	r.With(auth.TokenExtractionMiddleware).Get("/user/my/favorite", handlerObj.GetMyUserFavoriteListHandler)
+	r.With(auth.TokenExtractionMiddleware).Get("/user/my/continue-watching", handlerObj.GetMyContinueWatchingHandler)
+	r.With(auth.TokenExtractionMiddleware).Get("/user/my/history", handlerObj.GetMyWatchHistoryHandler)
+	r.With(auth.TokenExtractionMiddleware).Delete("/user/my/history", handlerObj.ClearMyWatchHistoryHandler)
+	r.With(auth.TokenExtractionMiddleware).Delete("/user/my/history/{session_id}", handlerObj.DeleteMyWatchHistoryEntryHandler)
+	r.With(auth.TokenExtractionMiddleware).Get("/user/my/streams", handlerObj.GetMyActiveStreamListHandler)
+	r.With(auth.TokenExtractionMiddleware).Delete("/user/my/streams/{lease_id}", handlerObj.DeleteMyStreamLeaseHandler)
+	r.With(auth.TokenExtractionMiddleware).Get("/user/my/warning", handlerObj.GetMyUserWarningListHandler)
+	r.With(auth.TokenExtractionMiddleware).Get("/user/my/preference", handlerObj.GetMyUserPreferenceHandler)
+	r.With(auth.TokenExtractionMiddleware).Patch("/user/my/preference", handlerObj.UpdateMyUserPreferenceHandler)
 
-	r.Get("/user/{user_id}/comment", handlerObj.GetUserCommentListHandler)
+	r.With(auth.OptionalTokenExtractionMiddleware).Get("/user/{user_id}/comment", handlerObj.GetUserCommentListHandler)
 	r.Get("/user/{user_id}/rating", handlerObj.GetUserRatingListHandler)
+	r.Get("/user/{user_id}/rating/history", handlerObj.GetUserRatingHistoryHandler)
 	r.Get("/user/{user_id}/favorite", handlerObj.GetUserFavoriteListHandler)
 
 	// Movie
@@ -92,15 +152,36 @@ func main() {
 	r.With(auth.TokenExtractionMiddleware).Patch("/movie/{movie_id}", handlerObj.UpdateMovieHandler)
 	r.With(auth.TokenExtractionMiddleware).Delete("/movie/{movie_id}", handlerObj.DeleteMovieHandler)
 
-	r.Get("/movie/{movie_id}/comment", handlerObj.GetMovieCommentListHandler)
+	r.With(auth.OptionalTokenExtractionMiddleware).Get("/movie/{movie_id}/comment", handlerObj.GetMovieCommentListHandler)
+	r.Get("/movie/{movie_id}/reviews", handlerObj.GetMovieReviewListHandler)
 	r.Get("/movie/{movie_id}/rating", handlerObj.GetMovieRatingListHandler)
+	r.Get("/movie/{movie_id}/rating/stats", handlerObj.GetMovieRatingStatsHandler)
+	r.Get("/movie/{movie_id}/rating/trend", handlerObj.GetMovieRatingTrendHandler)
 	r.Get("/movie/{movie_id}/favorite", handlerObj.GetMovieFavoriteListHandler)
 
 	// Comment
-	r.Get("/comment", handlerObj.GetCommentHandler)
+	r.With(auth.OptionalTokenExtractionMiddleware).Get("/comment/{comment_id}", handlerObj.GetCommentHandler)
 	r.With(auth.TokenExtractionMiddleware).Post("/comment", handlerObj.CreateCommentHandler)
-	r.With(auth.TokenExtractionMiddleware).Patch("/comment", handlerObj.UpdateCommentHandler)
-	r.With(auth.TokenExtractionMiddleware).Delete("/comment", handlerObj.DeleteRatingHandler)
+	r.With(auth.TokenExtractionMiddleware).Patch("/comment/{comment_id}", handlerObj.UpdateCommentHandler)
+	r.With(auth.TokenExtractionMiddleware).Delete("/comment/{comment_id}", handlerObj.DeleteCommentHandler)
+	r.With(auth.TokenExtractionMiddleware).Put("/comment/{comment_id}/reaction", handlerObj.SetCommentReactionHandler)
+	r.With(auth.TokenExtractionMiddleware).Delete("/comment/{comment_id}/reaction", handlerObj.DeleteCommentReactionHandler)
+	r.With(auth.TokenExtractionMiddleware).Post("/comment/{comment_id}/report", handlerObj.ReportCommentHandler)
+	r.With(auth.TokenExtractionMiddleware).Get("/comment/{comment_id}/history", handlerObj.GetCommentHistoryHandler)
+
+	// Review
+	r.Get("/review/{review_id}", handlerObj.GetReviewHandler)
+	r.With(auth.TokenExtractionMiddleware).Post("/review", handlerObj.CreateReviewHandler)
+	r.With(auth.TokenExtractionMiddleware).Patch("/review/{review_id}", handlerObj.UpdateReviewHandler)
+	r.With(auth.TokenExtractionMiddleware).Delete("/review/{review_id}", handlerObj.DeleteReviewHandler)
+	r.With(auth.TokenExtractionMiddleware).Put("/review/{review_id}/vote", handlerObj.SetReviewVoteHandler)
+	r.With(auth.TokenExtractionMiddleware).Delete("/review/{review_id}/vote", handlerObj.DeleteReviewVoteHandler)
+
+	// Moderation
+	r.With(auth.TokenExtractionMiddleware).Get("/moderation/comment", handlerObj.GetModerationQueueHandler)
+	r.With(auth.TokenExtractionMiddleware).Post("/moderation/comment/{comment_id}", handlerObj.ModerateCommentHandler)
+	r.With(auth.TokenExtractionMiddleware).Get("/moderation/comment/{comment_id}/report", handlerObj.GetCommentReportListHandler)
+	r.With(auth.TokenExtractionMiddleware).Get("/moderation/user/{user_id}/warning", handlerObj.GetUserWarningListHandler)
 
 	// Rating
 	r.Get("/rating", handlerObj.GetRatingHandler)
@@ -117,7 +198,49 @@ func main() {
 
 	// Video Handler
 	r.With(auth.TokenExtractionMiddleware).Post("/upload/movie/{movie_id}", handlerObj.UploadMovie)
-	r.Get("/stream/movie/{movie_id}", handlerObj.StreamMovie)
+	r.With(auth.TokenExtractionMiddleware).Post("/stream/movie/{movie_id}/sign", handlerObj.SignStreamURLHandler)
+	r.With(auth.StreamSignatureMiddleware).Get("/stream/movie/{movie_id}", handlerObj.StreamMovie)
+	r.With(auth.StreamSignatureMiddleware).Head("/stream/movie/{movie_id}", handlerObj.StreamMovie)
+	r.With(auth.StreamSignatureMiddleware).Post("/stream/movie/{movie_id}/heartbeat", handlerObj.StreamHeartbeatHandler)
+	r.With(auth.StreamSignatureMiddleware).Delete("/stream/movie/{movie_id}/lease", handlerObj.ReleaseStreamLeaseHandler)
+	r.With(auth.TokenExtractionMiddleware).Get("/stream/stats", handlerObj.GetStreamThrottleStatsHandler)
+	r.With(auth.TokenExtractionMiddleware).Post("/movie/{movie_id}/dash", handlerObj.PackageMovieDashHandler)
+	r.With(auth.TokenExtractionMiddleware).Get("/movie/{movie_id}/jobs", handlerObj.GetMovieTranscodeJobListHandler)
+	r.With(auth.StreamSignatureMiddleware).Get("/stream/movie/{movie_id}/dash/manifest.mpd", handlerObj.StreamMovieDashManifest)
+	r.With(auth.StreamSignatureMiddleware).Get("/stream/movie/{movie_id}/dash/{segment}", handlerObj.StreamMovieDashSegment)
+	r.With(auth.TokenExtractionMiddleware).Post("/movie/{movie_id}/thumbnails", handlerObj.GenerateMovieThumbnailsHandler)
+	r.With(auth.StreamSignatureMiddleware).Get("/movie/{movie_id}/thumbnails/thumbnails.vtt", handlerObj.GetMovieThumbnailsTrack)
+	r.With(auth.StreamSignatureMiddleware).Get("/movie/{movie_id}/thumbnails/{sprite}", handlerObj.GetMovieThumbnailsSprite)
+
+	// Watch progress
+	r.With(auth.TokenExtractionMiddleware).Get("/movie/{movie_id}/progress", handlerObj.GetMyWatchProgressHandler)
+	r.With(auth.TokenExtractionMiddleware).Put("/movie/{movie_id}/progress", handlerObj.SaveWatchProgressHandler)
+	r.With(auth.TokenExtractionMiddleware).Delete("/movie/{movie_id}/progress", handlerObj.DeleteMyWatchProgressHandler)
+
+	// Movie assets
+	r.Get("/movie/{movie_id}/asset", handlerObj.GetMovieAssetListHandler)
+	r.With(auth.TokenExtractionMiddleware).Post("/movie/{movie_id}/asset", handlerObj.CreateMovieAssetHandler)
+	r.With(auth.TokenExtractionMiddleware).Delete("/asset/{asset_id}", handlerObj.DeleteMovieAssetHandler)
+	r.With(auth.TokenExtractionMiddleware).Post("/upload/asset/{asset_id}", handlerObj.UploadMovieAssetHandler)
+	r.With(auth.StreamSignatureMiddleware).Get("/stream/movie/{movie_id}/asset/{asset_id}", handlerObj.StreamMovieAsset)
+	r.With(auth.StreamSignatureMiddleware).Head("/stream/movie/{movie_id}/asset/{asset_id}", handlerObj.StreamMovieAsset)
+
+	// Downloads
+	r.With(auth.TokenExtractionMiddleware).Post("/movie/{movie_id}/download", handlerObj.CreateDownloadGrantHandler)
+	r.With(auth.TokenExtractionMiddleware).Get("/download/report", handlerObj.GetDownloadReportHandler)
+	r.Get("/download/{grant_id}", handlerObj.DownloadMovieHandler)
+	r.Head("/download/{grant_id}", handlerObj.DownloadMovieHandler)
+
+	// Media storage
+	r.With(auth.TokenExtractionMiddleware).Get("/media/gc", handlerObj.GetMediaGCReportHandler)
+	r.With(auth.TokenExtractionMiddleware).Post("/media/gc", handlerObj.RunMediaGCHandler)
+	r.With(auth.TokenExtractionMiddleware).Get("/ingest/log", handlerObj.GetIngestLogListHandler)
+
+	// Subtitles
+	r.Get("/movie/{movie_id}/subtitle", handlerObj.GetMovieSubtitleListHandler)
+	r.With(auth.TokenExtractionMiddleware).Post("/movie/{movie_id}/subtitle", handlerObj.UploadSubtitleHandler)
+	r.With(auth.TokenExtractionMiddleware).Delete("/movie/{movie_id}/subtitle/{subtitle_id}", handlerObj.DeleteSubtitleHandler)
+	r.With(auth.StreamSignatureMiddleware).Get("/stream/movie/{movie_id}/subtitle/{subtitle_id}", handlerObj.StreamSubtitleHandler)
 
 	// healthcheck
 	r.Get("/healthcheck", handlers.CheckHealthHandlerCreate(dbPool))
@@ -126,3 +249,29 @@ func main() {
 
 	log.Fatal(http.ListenAndServe(":8080", r))
 }
+
+// getEnvInt read optional integer setting, fallback is used for unset variable
+func getEnvInt(key string, fallback int) int {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return fallback
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		log.Fatalln(fmt.Errorf("parsing %s value: %w", key, err))
+	}
+	return value
+}
+
+// getEnvBool read optional boolean setting, fallback is used for unset variable
+func getEnvBool(key string, fallback bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return fallback
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		log.Fatalln(fmt.Errorf("parsing %s value: %w", key, err))
+	}
+	return value
+}
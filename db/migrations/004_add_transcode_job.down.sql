@@ -0,0 +1,3 @@
+DROP INDEX transcode_job_movie_index;
+DROP INDEX transcode_job_queue_index;
+DROP TABLE transcode_job;
@@ -0,0 +1,16 @@
+CREATE TABLE transcode_job(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  movie_id UUID NOT NULL REFERENCES movie ON DELETE CASCADE,
+  status VARCHAR NOT NULL DEFAULT 'queued' CHECK(status IN ('queued', 'running', 'done', 'failed')),
+  stage VARCHAR NOT NULL DEFAULT 'probe',
+  progress SMALLINT NOT NULL DEFAULT 0 CHECK(progress BETWEEN 0 AND 100),
+  attempts INT NOT NULL DEFAULT 0,
+  max_attempts INT NOT NULL DEFAULT 3,
+  last_error VARCHAR,
+  run_after TIMESTAMP NOT NULL DEFAULT NOW(),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX transcode_job_queue_index ON transcode_job(run_after) WHERE status = 'queued';
+CREATE INDEX transcode_job_movie_index ON transcode_job(movie_id);
@@ -0,0 +1,8 @@
+ALTER TABLE movie
+DROP COLUMN duration_ms,
+DROP COLUMN width,
+DROP COLUMN height,
+DROP COLUMN video_codec,
+DROP COLUMN audio_codec,
+DROP COLUMN bitrate,
+DROP COLUMN faststart;
@@ -0,0 +1,8 @@
+ALTER TABLE movie
+ADD COLUMN duration_ms BIGINT,
+ADD COLUMN width INT,
+ADD COLUMN height INT,
+ADD COLUMN video_codec VARCHAR,
+ADD COLUMN audio_codec VARCHAR,
+ADD COLUMN bitrate BIGINT,
+ADD COLUMN faststart BOOL;
@@ -0,0 +1,12 @@
+CREATE TABLE subtitle(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  movie_id UUID NOT NULL REFERENCES movie ON DELETE CASCADE,
+  language VARCHAR NOT NULL,
+  label VARCHAR,
+  kind VARCHAR NOT NULL DEFAULT 'subtitles' CHECK(kind IN ('subtitles', 'captions')),
+  source_format VARCHAR NOT NULL CHECK(source_format IN ('srt', 'vtt')),
+  -- Track is always stored converted to WebVTT
+  content VARCHAR NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE(movie_id, language, kind)
+);
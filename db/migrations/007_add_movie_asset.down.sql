@@ -0,0 +1,2 @@
+DROP INDEX movie_asset_movie_index;
+DROP TABLE movie_asset;
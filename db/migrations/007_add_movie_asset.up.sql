@@ -0,0 +1,24 @@
+CREATE TABLE movie_asset(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  movie_id UUID NOT NULL REFERENCES movie ON DELETE CASCADE,
+  kind VARCHAR NOT NULL CHECK(kind IN ('main', 'trailer', 'extra', 'dub', 'directors_cut')),
+  language VARCHAR,
+  quality VARCHAR,
+  title VARCHAR,
+  asset_path VARCHAR,
+  duration_ms BIGINT,
+  width INT,
+  height INT,
+  video_codec VARCHAR,
+  audio_codec VARCHAR,
+  bitrate BIGINT,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX movie_asset_movie_index ON movie_asset(movie_id);
+
+-- Already uploaded movies become main assets
+INSERT INTO movie_asset(movie_id, kind, asset_path, duration_ms, width, height, video_codec, audio_codec, bitrate)
+SELECT id, 'main', movie_path, duration_ms, width, height, video_codec, audio_codec, bitrate
+FROM movie
+WHERE movie_path IS NOT NULL;
@@ -0,0 +1,4 @@
+DELETE FROM transcode_job WHERE kind <> 'transcode';
+
+ALTER TABLE transcode_job
+DROP COLUMN kind;
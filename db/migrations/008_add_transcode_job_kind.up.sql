@@ -0,0 +1,2 @@
+ALTER TABLE transcode_job
+ADD COLUMN kind VARCHAR NOT NULL DEFAULT 'transcode' CHECK(kind IN ('transcode', 'thumbnails'));
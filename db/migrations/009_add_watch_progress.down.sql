@@ -0,0 +1,2 @@
+DROP INDEX watch_progress_recent_index;
+DROP TABLE watch_progress;
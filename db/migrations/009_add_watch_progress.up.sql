@@ -0,0 +1,11 @@
+CREATE TABLE watch_progress(
+  user_id UUID REFERENCES user_data ON DELETE CASCADE,
+  movie_id UUID REFERENCES movie ON DELETE CASCADE,
+  position_ms BIGINT NOT NULL CHECK(position_ms >= 0),
+  duration_ms BIGINT,
+  watched BOOL NOT NULL DEFAULT FALSE,
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY(user_id, movie_id)
+);
+
+CREATE INDEX watch_progress_recent_index ON watch_progress(user_id, updated_at DESC) WHERE NOT watched;
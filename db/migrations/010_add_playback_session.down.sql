@@ -0,0 +1,12 @@
+CREATE OR REPLACE PROCEDURE refresh_mview()
+LANGUAGE SQL
+AS $$
+    REFRESH MATERIALIZED VIEW CONCURRENTLY total_rating_mview;
+$$;
+
+DROP INDEX movie_view_count_mview_index;
+DROP MATERIALIZED VIEW movie_view_count_mview;
+
+DROP INDEX playback_session_dedup_index;
+DROP INDEX playback_session_user_index;
+DROP TABLE playback_session;
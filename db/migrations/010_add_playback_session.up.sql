@@ -0,0 +1,26 @@
+CREATE TABLE playback_session(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  -- NULL once user removed session from history, view is still counted
+  user_id UUID REFERENCES user_data ON DELETE SET NULL,
+  movie_id UUID NOT NULL REFERENCES movie ON DELETE CASCADE,
+  session_key VARCHAR NOT NULL,
+  started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  last_seen_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX playback_session_user_index ON playback_session(user_id, started_at DESC);
+CREATE INDEX playback_session_dedup_index ON playback_session(user_id, movie_id, session_key, last_seen_at);
+
+CREATE MATERIALIZED VIEW movie_view_count_mview AS
+SELECT movie_id, COUNT(*) AS view_count
+FROM playback_session
+GROUP BY movie_id;
+
+CREATE UNIQUE INDEX movie_view_count_mview_index ON movie_view_count_mview(movie_id);
+
+CREATE OR REPLACE PROCEDURE refresh_mview()
+LANGUAGE SQL
+AS $$
+    REFRESH MATERIALIZED VIEW CONCURRENTLY total_rating_mview;
+    REFRESH MATERIALIZED VIEW CONCURRENTLY movie_view_count_mview;
+$$;
@@ -0,0 +1,2 @@
+DROP INDEX stream_lease_active_index;
+DROP TABLE stream_lease;
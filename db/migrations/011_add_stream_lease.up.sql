@@ -0,0 +1,12 @@
+CREATE TABLE stream_lease(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES user_data ON DELETE CASCADE,
+  movie_id UUID NOT NULL REFERENCES movie ON DELETE CASCADE,
+  session_key VARCHAR NOT NULL,
+  device VARCHAR NOT NULL,
+  started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  expires_at TIMESTAMP NOT NULL,
+  UNIQUE(user_id, session_key)
+);
+
+CREATE INDEX stream_lease_active_index ON stream_lease(user_id, expires_at);
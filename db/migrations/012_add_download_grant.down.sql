@@ -0,0 +1,3 @@
+DROP INDEX download_log_downloaded_at_index;
+DROP TABLE download_log;
+DROP TABLE download_grant;
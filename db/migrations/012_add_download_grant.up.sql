@@ -0,0 +1,20 @@
+CREATE TABLE download_grant(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES user_data ON DELETE CASCADE,
+  movie_id UUID NOT NULL REFERENCES movie ON DELETE CASCADE,
+  max_downloads INT NOT NULL CHECK (max_downloads > 0),
+  download_count INT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  expires_at TIMESTAMP NOT NULL
+);
+
+-- Log outlives grants and users, licensing reports count every download
+CREATE TABLE download_log(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  grant_id UUID REFERENCES download_grant ON DELETE SET NULL,
+  user_id UUID REFERENCES user_data ON DELETE SET NULL,
+  movie_id UUID NOT NULL REFERENCES movie ON DELETE CASCADE,
+  downloaded_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX download_log_downloaded_at_index ON download_log(downloaded_at);
@@ -0,0 +1,2 @@
+ALTER TABLE movie_asset DROP COLUMN file_missing;
+ALTER TABLE movie DROP COLUMN file_missing;
@@ -0,0 +1,3 @@
+-- Set by media garbage collector when referenced file is gone from storage
+ALTER TABLE movie ADD COLUMN file_missing BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE movie_asset ADD COLUMN file_missing BOOLEAN NOT NULL DEFAULT FALSE;
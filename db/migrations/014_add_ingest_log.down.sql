@@ -0,0 +1,2 @@
+DROP INDEX ingest_log_created_at_index;
+DROP TABLE ingest_log;
@@ -0,0 +1,13 @@
+CREATE TABLE ingest_log(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  file_name VARCHAR NOT NULL,
+  file_size BIGINT NOT NULL,
+  movie_id UUID REFERENCES movie ON DELETE SET NULL,
+  title VARCHAR NOT NULL,
+  status VARCHAR NOT NULL CHECK(status IN ('ingested', 'failed', 'dry_run')),
+  action VARCHAR CHECK(action IN ('created', 'matched')),
+  message VARCHAR,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ingest_log_created_at_index ON ingest_log(created_at);
@@ -0,0 +1,5 @@
+DROP INDEX comment_parent_index;
+DROP INDEX comment_movie_root_index;
+ALTER TABLE comment DROP COLUMN is_deleted;
+ALTER TABLE comment DROP COLUMN depth;
+ALTER TABLE comment DROP COLUMN parent_id;
@@ -0,0 +1,7 @@
+-- Comments with replies are never deleted, they become tombstones keeping the thread together
+ALTER TABLE comment ADD COLUMN parent_id UUID REFERENCES comment;
+ALTER TABLE comment ADD COLUMN depth SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE comment ADD COLUMN is_deleted BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX comment_movie_root_index ON comment(movie_id, created_at) WHERE parent_id IS NULL;
+CREATE INDEX comment_parent_index ON comment(parent_id);
@@ -0,0 +1,10 @@
+-- One reaction per user and comment, changing reaction replaces the previous one
+CREATE TABLE comment_reaction(
+  user_id UUID NOT NULL REFERENCES user_data ON DELETE CASCADE,
+  comment_id UUID NOT NULL REFERENCES comment ON DELETE CASCADE,
+  reaction VARCHAR(16) NOT NULL CHECK (reaction IN ('like', 'dislike', 'love', 'laugh', 'wow', 'sad')),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (user_id, comment_id)
+);
+
+CREATE INDEX comment_reaction_comment_index ON comment_reaction(comment_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_warning;
+DROP TABLE IF EXISTS comment_report;
+ALTER TABLE comment DROP COLUMN IF EXISTS state;
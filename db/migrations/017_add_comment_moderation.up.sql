@@ -0,0 +1,29 @@
+-- Only visible comments are listed, pending ones wait for moderator after being reported
+ALTER TABLE comment ADD COLUMN state VARCHAR(16) NOT NULL DEFAULT 'visible'
+  CHECK (state IN ('visible', 'hidden', 'pending'));
+
+CREATE TABLE comment_report(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  comment_id UUID NOT NULL REFERENCES comment ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES user_data ON DELETE CASCADE,
+  reason VARCHAR(16) NOT NULL CHECK (reason IN ('spam', 'abuse', 'spoiler', 'off_topic', 'other')),
+  message TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  resolved_at TIMESTAMP,
+  resolution VARCHAR(16),
+  UNIQUE (comment_id, user_id)
+);
+
+CREATE INDEX comment_report_open_index ON comment_report(comment_id) WHERE resolved_at IS NULL;
+
+-- Warnings outlive comments they were issued for
+CREATE TABLE user_warning(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES user_data ON DELETE CASCADE,
+  comment_id UUID REFERENCES comment ON DELETE SET NULL,
+  moderator_id UUID REFERENCES user_data ON DELETE SET NULL,
+  reason TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX user_warning_user_index ON user_warning(user_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS comment_user_created_at_index;
+ALTER TABLE comment DROP COLUMN IF EXISTS filter_reason;
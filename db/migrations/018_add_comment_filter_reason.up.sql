@@ -0,0 +1,4 @@
+-- Why content filter held comment for review, kept after moderator decision
+ALTER TABLE comment ADD COLUMN filter_reason TEXT;
+
+CREATE INDEX comment_user_created_at_index ON comment(user_id, created_at);
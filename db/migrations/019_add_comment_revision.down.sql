@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS comment_revision;
+ALTER TABLE comment DROP COLUMN IF EXISTS edited_at;
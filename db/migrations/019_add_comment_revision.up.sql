@@ -0,0 +1,11 @@
+ALTER TABLE comment ADD COLUMN edited_at TIMESTAMP;
+
+-- Text comment had before every edit, tombstoned comments keep their last text here
+CREATE TABLE comment_revision(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  comment_id UUID NOT NULL REFERENCES comment ON DELETE CASCADE,
+  text TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX comment_revision_comment_index ON comment_revision(comment_id, created_at);
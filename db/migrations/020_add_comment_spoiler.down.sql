@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_preference;
+ALTER TABLE comment DROP COLUMN IF EXISTS is_spoiler;
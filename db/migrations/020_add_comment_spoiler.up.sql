@@ -0,0 +1,9 @@
+-- Whole comment is spoiler, inline spoilers are marked in text itself
+ALTER TABLE comment ADD COLUMN is_spoiler BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Users without row use defaults
+CREATE TABLE user_preference(
+  user_id UUID PRIMARY KEY REFERENCES user_data ON DELETE CASCADE,
+  hide_spoilers BOOLEAN NOT NULL DEFAULT TRUE,
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS review_vote;
+DROP TABLE IF EXISTS review;
@@ -0,0 +1,21 @@
+-- Review is linked to rating of the same user and movie, deleting rating keeps review without it
+CREATE TABLE review(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES user_data ON DELETE CASCADE,
+  movie_id UUID NOT NULL REFERENCES movie ON DELETE CASCADE,
+  title VARCHAR NOT NULL,
+  body TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (user_id, movie_id)
+);
+
+CREATE INDEX review_movie_index ON review(movie_id, created_at);
+
+CREATE TABLE review_vote(
+  review_id UUID NOT NULL REFERENCES review ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES user_data ON DELETE CASCADE,
+  helpful BOOLEAN NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (review_id, user_id)
+);
@@ -0,0 +1,8 @@
+CREATE OR REPLACE PROCEDURE refresh_mview()
+LANGUAGE SQL
+AS $$
+    REFRESH MATERIALIZED VIEW CONCURRENTLY total_rating_mview;
+    REFRESH MATERIALIZED VIEW CONCURRENTLY movie_view_count_mview;
+$$;
+
+DROP MATERIALIZED VIEW IF EXISTS rating_stats_mview;
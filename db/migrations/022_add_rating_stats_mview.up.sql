@@ -0,0 +1,31 @@
+-- Histogram element i is amount of rating i + 1
+CREATE MATERIALIZED VIEW rating_stats_mview AS
+SELECT movie_id,
+  COUNT(*) AS amount_rates,
+  AVG(rating)::FLOAT8 AS rating,
+  ARRAY[
+    COUNT(*) FILTER (WHERE rating = 1),
+    COUNT(*) FILTER (WHERE rating = 2),
+    COUNT(*) FILTER (WHERE rating = 3),
+    COUNT(*) FILTER (WHERE rating = 4),
+    COUNT(*) FILTER (WHERE rating = 5),
+    COUNT(*) FILTER (WHERE rating = 6),
+    COUNT(*) FILTER (WHERE rating = 7),
+    COUNT(*) FILTER (WHERE rating = 8),
+    COUNT(*) FILTER (WHERE rating = 9),
+    COUNT(*) FILTER (WHERE rating = 10)
+  ] AS histogram,
+  PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY rating) AS median,
+  STDDEV_POP(rating)::FLOAT8 AS stddev
+FROM rating
+GROUP BY movie_id;
+
+CREATE UNIQUE INDEX rating_stats_mview_index ON rating_stats_mview(movie_id);
+
+CREATE OR REPLACE PROCEDURE refresh_mview()
+LANGUAGE SQL
+AS $$
+    REFRESH MATERIALIZED VIEW CONCURRENTLY total_rating_mview;
+    REFRESH MATERIALIZED VIEW CONCURRENTLY movie_view_count_mview;
+    REFRESH MATERIALIZED VIEW CONCURRENTLY rating_stats_mview;
+$$;
@@ -0,0 +1,73 @@
+-- Rating aggregates are kept up to date by trigger in the same transaction as rating change.
+-- Histogram element i is amount of rating i
+CREATE TABLE movie_rating_stats(
+  movie_id UUID PRIMARY KEY REFERENCES movie ON DELETE CASCADE,
+  amount_rates BIGINT NOT NULL DEFAULT 0,
+  rating_sum BIGINT NOT NULL DEFAULT 0,
+  rating_square_sum BIGINT NOT NULL DEFAULT 0,
+  histogram BIGINT[] NOT NULL DEFAULT ARRAY_FILL(0::BIGINT, ARRAY[10]),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO movie_rating_stats (movie_id, amount_rates, rating_sum, rating_square_sum, histogram)
+SELECT movie_id, COUNT(*), SUM(rating), SUM(rating * rating),
+  ARRAY[
+    COUNT(*) FILTER (WHERE rating = 1),
+    COUNT(*) FILTER (WHERE rating = 2),
+    COUNT(*) FILTER (WHERE rating = 3),
+    COUNT(*) FILTER (WHERE rating = 4),
+    COUNT(*) FILTER (WHERE rating = 5),
+    COUNT(*) FILTER (WHERE rating = 6),
+    COUNT(*) FILTER (WHERE rating = 7),
+    COUNT(*) FILTER (WHERE rating = 8),
+    COUNT(*) FILTER (WHERE rating = 9),
+    COUNT(*) FILTER (WHERE rating = 10)
+  ]
+FROM rating
+GROUP BY movie_id;
+
+CREATE OR REPLACE FUNCTION update_movie_rating_stats()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+  IF TG_OP IN ('UPDATE', 'DELETE') THEN
+    UPDATE movie_rating_stats SET
+      amount_rates = amount_rates - 1,
+      rating_sum = rating_sum - OLD.rating,
+      rating_square_sum = rating_square_sum - OLD.rating * OLD.rating,
+      histogram[OLD.rating] = histogram[OLD.rating] - 1,
+      updated_at = NOW()
+    WHERE movie_id = OLD.movie_id;
+  END IF;
+
+  IF TG_OP IN ('INSERT', 'UPDATE') THEN
+    INSERT INTO movie_rating_stats (movie_id, amount_rates, rating_sum, rating_square_sum, histogram)
+    VALUES (
+      NEW.movie_id, 1, NEW.rating, NEW.rating * NEW.rating,
+      ARRAY(SELECT (score = NEW.rating)::INT::BIGINT FROM generate_series(1, 10) score)
+    )
+    ON CONFLICT (movie_id) DO UPDATE SET
+      amount_rates = movie_rating_stats.amount_rates + 1,
+      rating_sum = movie_rating_stats.rating_sum + NEW.rating,
+      rating_square_sum = movie_rating_stats.rating_square_sum + NEW.rating * NEW.rating,
+      histogram[NEW.rating] = movie_rating_stats.histogram[NEW.rating] + 1,
+      updated_at = NOW();
+  END IF;
+
+  RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER rating_stats_trigger
+AFTER INSERT OR UPDATE OF rating OR DELETE ON rating
+FOR EACH ROW EXECUTE FUNCTION update_movie_rating_stats();
+
+CREATE OR REPLACE PROCEDURE refresh_mview()
+LANGUAGE SQL
+AS $$
+    REFRESH MATERIALIZED VIEW CONCURRENTLY movie_view_count_mview;
+$$;
+
+DROP MATERIALIZED VIEW IF EXISTS rating_stats_mview;
+DROP MATERIALIZED VIEW IF EXISTS total_rating_mview;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS rating_event_trigger ON rating;
+DROP FUNCTION IF EXISTS log_rating_event;
+DROP TABLE IF EXISTS rating_event;
@@ -0,0 +1,46 @@
+-- Every rating change is logged by trigger. Create has no old rating, delete has no new one
+CREATE TABLE rating_event(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES user_data ON DELETE CASCADE,
+  movie_id UUID NOT NULL REFERENCES movie ON DELETE CASCADE,
+  action VARCHAR NOT NULL CHECK(action IN ('create', 'update', 'delete')),
+  old_rating SMALLINT,
+  new_rating SMALLINT,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX rating_event_movie_index ON rating_event(movie_id, created_at);
+CREATE INDEX rating_event_user_index ON rating_event(user_id, created_at);
+
+-- Existing ratings start the log, so trend totals match rating table
+INSERT INTO rating_event (user_id, movie_id, action, new_rating)
+SELECT user_id, movie_id, 'create', rating
+FROM rating;
+
+CREATE OR REPLACE FUNCTION log_rating_event()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    INSERT INTO rating_event (user_id, movie_id, action, new_rating)
+    VALUES (NEW.user_id, NEW.movie_id, 'create', NEW.rating);
+  ELSIF TG_OP = 'UPDATE' THEN
+    IF OLD.rating IS DISTINCT FROM NEW.rating THEN
+      INSERT INTO rating_event (user_id, movie_id, action, old_rating, new_rating)
+      VALUES (NEW.user_id, NEW.movie_id, 'update', OLD.rating, NEW.rating);
+    END IF;
+  -- Rating removed by user or movie deletion cascade has nobody to log for
+  ELSIF EXISTS (SELECT 1 FROM user_data WHERE id = OLD.user_id)
+    AND EXISTS (SELECT 1 FROM movie WHERE id = OLD.movie_id) THEN
+    INSERT INTO rating_event (user_id, movie_id, action, old_rating)
+    VALUES (OLD.user_id, OLD.movie_id, 'delete', OLD.rating);
+  END IF;
+
+  RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER rating_event_trigger
+AFTER INSERT OR UPDATE OF rating OR DELETE ON rating
+FOR EACH ROW EXECUTE FUNCTION log_rating_event();
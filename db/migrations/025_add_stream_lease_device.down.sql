@@ -0,0 +1 @@
+ALTER TABLE stream_lease DROP COLUMN IF EXISTS device_id;
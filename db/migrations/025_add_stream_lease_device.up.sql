@@ -0,0 +1,2 @@
+-- Active lease belongs to device holding the cookie, session of shared stream URL can't be taken over
+ALTER TABLE stream_lease ADD COLUMN device_id VARCHAR NOT NULL DEFAULT '';
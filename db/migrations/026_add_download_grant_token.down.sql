@@ -0,0 +1 @@
+ALTER TABLE download_grant DROP COLUMN IF EXISTS download_token;
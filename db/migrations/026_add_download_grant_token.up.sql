@@ -0,0 +1,2 @@
+-- Token is issued to client using grant, only it may resume the download
+ALTER TABLE download_grant ADD COLUMN download_token VARCHAR;
@@ -0,0 +1 @@
+ALTER TABLE comment DROP COLUMN IF EXISTS language;
@@ -0,0 +1,2 @@
+-- Language picks banned words list comment is checked with, default list is used when it's NULL
+ALTER TABLE comment ADD COLUMN language VARCHAR;
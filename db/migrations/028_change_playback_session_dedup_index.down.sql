@@ -0,0 +1,2 @@
+DROP INDEX playback_session_dedup_index;
+CREATE INDEX playback_session_dedup_index ON playback_session(user_id, movie_id, session_key, last_seen_at);
@@ -0,0 +1,3 @@
+-- Session is found by movie and key only, so it is touched again after user removed it from history
+DROP INDEX playback_session_dedup_index;
+CREATE INDEX playback_session_dedup_index ON playback_session(movie_id, session_key, last_seen_at);
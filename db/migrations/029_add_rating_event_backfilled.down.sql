@@ -0,0 +1 @@
+ALTER TABLE rating_event DROP COLUMN backfilled;
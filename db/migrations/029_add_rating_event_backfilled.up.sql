@@ -0,0 +1,7 @@
+-- Ratings copied into the log on its creation were not given at that time, trend counts them in totals only.
+-- Trigger was created right after the copy, so every later event is newer than it
+ALTER TABLE rating_event ADD COLUMN backfilled BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE rating_event SET backfilled = TRUE
+WHERE action = 'create'
+  AND created_at = (SELECT MIN(created_at) FROM rating_event);
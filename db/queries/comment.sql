@@ -1,12 +1,52 @@
 -- name: GetMovieCommentList :many
-SELECT id, user_id, text, created_at
-FROM comment
-WHERE movie_id = $1;
+SELECT c.id, c.user_id, c.parent_id, c.depth, c.text, c.is_spoiler, c.is_deleted, c.state, c.created_at, c.edited_at,
+  (SELECT COUNT(*) FROM comment r WHERE r.parent_id = c.id
+     AND ((r.state = 'visible' AND NOT r.is_deleted) OR EXISTS (SELECT 1 FROM comment rr WHERE rr.parent_id = r.id))) reply_count,
+  s.score
+FROM comment c
+CROSS JOIN LATERAL (
+  SELECT COALESCE(SUM(CASE WHEN cr.reaction = 'dislike' THEN -1 ELSE 1 END), 0)::BIGINT score
+  FROM comment_reaction cr
+  WHERE cr.comment_id = c.id
+) s
+WHERE c.movie_id = sqlc.arg(movie_id)
+  AND c.parent_id IS NULL
+  AND ((c.state = 'visible' AND NOT c.is_deleted) OR EXISTS (SELECT 1 FROM comment r WHERE r.parent_id = c.id))
+ORDER BY CASE WHEN sqlc.arg(sort_top)::BOOLEAN THEN s.score END DESC, c.created_at DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountMovieRootComments :one
+SELECT COUNT(*)
+FROM comment c
+WHERE c.movie_id = $1
+  AND c.parent_id IS NULL
+  AND ((c.state = 'visible' AND NOT c.is_deleted) OR EXISTS (SELECT 1 FROM comment r WHERE r.parent_id = c.id));
+
+-- name: GetCommentReplyTree :many
+WITH RECURSIVE thread AS (
+  SELECT id, user_id, parent_id, depth, text, is_spoiler, is_deleted, state, created_at, edited_at
+  FROM comment
+  WHERE parent_id = ANY(sqlc.arg(root_ids)::UUID[])
+  UNION ALL
+  SELECT c.id, c.user_id, c.parent_id, c.depth, c.text, c.is_spoiler, c.is_deleted, c.state, c.created_at, c.edited_at
+  FROM comment c
+  JOIN thread t ON c.parent_id = t.id
+)
+SELECT t.id, t.user_id, t.parent_id, t.depth, t.text, t.is_spoiler, t.is_deleted, t.state, t.created_at, t.edited_at,
+  (SELECT COUNT(*) FROM comment r WHERE r.parent_id = t.id
+     AND ((r.state = 'visible' AND NOT r.is_deleted) OR EXISTS (SELECT 1 FROM comment rr WHERE rr.parent_id = r.id))) reply_count,
+  (SELECT COALESCE(SUM(CASE WHEN cr.reaction = 'dislike' THEN -1 ELSE 1 END), 0)::BIGINT
+   FROM comment_reaction cr WHERE cr.comment_id = t.id) score
+FROM thread t
+WHERE (t.state = 'visible' AND NOT t.is_deleted) OR EXISTS (SELECT 1 FROM comment r WHERE r.parent_id = t.id)
+ORDER BY t.created_at;
 
 -- name: GetUserCommentList :many
-SELECT id, movie_id, text, created_at
+SELECT id, movie_id, text, is_spoiler, created_at, edited_at
 FROM comment
-WHERE user_id = $1;
+WHERE user_id = $1
+  AND is_deleted = FALSE
+  AND state = 'visible';
 
 -- name: GetComment :one
 SELECT *
@@ -14,17 +54,79 @@ FROM comment
 WHERE id = $1;
 
 -- name: CreateComment :one
-INSERT INTO comment (user_id, movie_id, text)
-VALUES ($1, $2, $3)
+INSERT INTO comment (user_id, movie_id, parent_id, depth, text, is_spoiler, state, filter_reason, language)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 RETURNING *;
 
 -- name: UpdateComment :one
-UPDATE comment SET
-  text = $2
-WHERE id = $1 
-RETURNING *;
+WITH previous AS (
+  SELECT id, text
+  FROM comment
+  WHERE id = sqlc.arg(id)
+    AND is_deleted = FALSE
+  FOR UPDATE
+), revision AS (
+  INSERT INTO comment_revision (comment_id, text)
+  SELECT id, text FROM previous
+)
+UPDATE comment c SET
+  text = sqlc.arg(text),
+  is_spoiler = COALESCE(sqlc.narg(is_spoiler), c.is_spoiler),
+  edited_at = NOW(),
+  state = CASE WHEN sqlc.narg(filter_reason)::TEXT IS NULL OR c.state = 'hidden' THEN c.state ELSE 'pending' END,
+  filter_reason = COALESCE(sqlc.narg(filter_reason), c.filter_reason)
+FROM previous
+WHERE c.id = previous.id
+RETURNING c.id, c.user_id, c.movie_id, c.text, c.created_at, c.parent_id, c.depth, c.is_deleted, c.state, c.filter_reason, c.edited_at, c.is_spoiler, c.language;
+
+-- name: LockComment :execrows
+SELECT id
+FROM comment
+WHERE id = $1
+FOR UPDATE;
 
+-- name: TombstoneComment :execrows
+WITH previous AS (
+  SELECT id, text
+  FROM comment
+  WHERE id = $1
+    AND is_deleted = FALSE
+    AND (EXISTS (SELECT 1 FROM comment r WHERE r.parent_id = $1)
+      OR EXISTS (SELECT 1 FROM comment_revision cr WHERE cr.comment_id = $1)
+      OR EXISTS (SELECT 1 FROM comment_report rp WHERE rp.comment_id = $1))
+  FOR UPDATE
+), revision AS (
+  INSERT INTO comment_revision (comment_id, text)
+  SELECT id, text FROM previous
+)
+UPDATE comment c SET
+  is_deleted = TRUE,
+  text = '',
+  state = 'visible'
+FROM previous
+WHERE c.id = previous.id;
 
 -- name: DeleteComment :execrows
 DELETE FROM comment
-WHERE id = $1;
+WHERE id = $1
+  AND is_deleted = FALSE;
+
+-- name: SetCommentState :one
+UPDATE comment SET
+  state = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: GetRecentUserCommentTextList :many
+SELECT text
+FROM comment
+WHERE user_id = sqlc.arg(user_id)
+  AND created_at > sqlc.arg(since)
+  AND id IS DISTINCT FROM sqlc.narg(exclude_id)
+  AND is_deleted = FALSE;
+
+-- name: GetCommentRevisionList :many
+SELECT *
+FROM comment_revision
+WHERE comment_id = $1
+ORDER BY created_at;
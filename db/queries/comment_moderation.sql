@@ -0,0 +1,55 @@
+-- name: CreateCommentReport :one
+INSERT INTO comment_report (comment_id, user_id, reason, message)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (comment_id, user_id) DO NOTHING
+RETURNING *;
+
+-- name: HoldReportedComment :execrows
+UPDATE comment SET
+  state = 'pending'
+WHERE id = sqlc.arg(comment_id)
+  AND state = 'visible'
+  AND (
+    SELECT COUNT(*)
+    FROM comment_report
+    WHERE comment_id = sqlc.arg(comment_id)
+      AND resolved_at IS NULL
+  ) >= sqlc.arg(threshold)::INT;
+
+-- name: GetModerationQueue :many
+SELECT c.id, c.user_id, c.movie_id, c.text, c.state, c.filter_reason, c.created_at,
+  COUNT(r.id) open_reports,
+  COALESCE(ARRAY_AGG(DISTINCT r.reason) FILTER (WHERE r.id IS NOT NULL), '{}')::TEXT[] reasons,
+  MIN(r.created_at)::TIMESTAMP first_reported_at
+FROM comment c
+LEFT JOIN comment_report r ON r.comment_id = c.id AND r.resolved_at IS NULL
+WHERE c.is_deleted = FALSE
+  AND (c.state = 'pending' OR r.id IS NOT NULL)
+GROUP BY c.id
+ORDER BY c.state = 'pending' DESC, open_reports DESC, c.created_at
+LIMIT $1;
+
+-- name: GetOpenCommentReportList :many
+SELECT *
+FROM comment_report
+WHERE comment_id = $1
+  AND resolved_at IS NULL
+ORDER BY created_at;
+
+-- name: ResolveCommentReports :execrows
+UPDATE comment_report SET
+  resolved_at = NOW(),
+  resolution = $2
+WHERE comment_id = $1
+  AND resolved_at IS NULL;
+
+-- name: CreateUserWarning :one
+INSERT INTO user_warning (user_id, comment_id, moderator_id, reason)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetUserWarningList :many
+SELECT *
+FROM user_warning
+WHERE user_id = $1
+ORDER BY created_at DESC;
@@ -0,0 +1,18 @@
+-- name: SetCommentReaction :one
+INSERT INTO comment_reaction (user_id, comment_id, reaction)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, comment_id) DO UPDATE SET
+  reaction = EXCLUDED.reaction,
+  created_at = NOW()
+RETURNING *;
+
+-- name: DeleteCommentReaction :execrows
+DELETE FROM comment_reaction
+WHERE user_id = $1
+  AND comment_id = $2;
+
+-- name: GetCommentReactionCountList :many
+SELECT comment_id, reaction, COUNT(*) amount
+FROM comment_reaction
+WHERE comment_id = ANY(sqlc.arg(comment_ids)::UUID[])
+GROUP BY comment_id, reaction;
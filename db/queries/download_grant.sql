@@ -0,0 +1,38 @@
+-- name: CreateDownloadGrant :one
+INSERT INTO download_grant(user_id, movie_id, max_downloads, expires_at)
+VALUES ($1, $2, $3, NOW() + sqlc.arg(ttl_seconds)::INT * INTERVAL '1 second')
+RETURNING *;
+
+-- name: GetActiveDownloadGrant :one
+SELECT *
+FROM download_grant
+WHERE id = $1
+  AND expires_at > NOW();
+
+-- name: UseDownloadGrant :one
+WITH used_grant AS (
+  UPDATE download_grant SET
+    download_count = download_count + 1,
+    download_token = sqlc.arg(download_token)
+  WHERE id = sqlc.arg(id)
+    AND expires_at > NOW()
+    AND download_count < max_downloads
+  RETURNING id, user_id, movie_id
+)
+INSERT INTO download_log(grant_id, user_id, movie_id)
+SELECT id, user_id, movie_id
+FROM used_grant
+RETURNING id, grant_id, user_id, movie_id, downloaded_at;
+
+-- name: GetDownloadReport :many
+SELECT dl.user_id, dl.movie_id, m.title, COUNT(*) downloads, MAX(dl.downloaded_at)::TIMESTAMP last_downloaded_at
+FROM download_log dl
+JOIN movie m ON m.id = dl.movie_id
+WHERE dl.downloaded_at >= sqlc.arg(from_time)::TIMESTAMP
+  AND dl.downloaded_at < sqlc.arg(to_time)::TIMESTAMP
+GROUP BY dl.user_id, dl.movie_id, m.title
+ORDER BY m.title, dl.user_id;
+
+-- name: DeleteExpiredDownloadGrants :execrows
+DELETE FROM download_grant
+WHERE expires_at <= NOW();
@@ -0,0 +1,10 @@
+-- name: CreateIngestLog :one
+INSERT INTO ingest_log(file_name, file_size, movie_id, title, status, action, message)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetIngestLogList :many
+SELECT *
+FROM ingest_log
+ORDER BY created_at DESC
+LIMIT $1;
@@ -0,0 +1,23 @@
+-- name: GetMovieFileList :many
+SELECT id, movie_path, file_missing
+FROM movie;
+
+-- name: GetMovieAssetFileList :many
+SELECT id, movie_id, asset_path, file_missing
+FROM movie_asset;
+
+-- name: GetPendingUploadMovieIDList :many
+SELECT DISTINCT movie_id
+FROM transcode_job
+WHERE kind = 'transcode'
+  AND status IN ('queued', 'running');
+
+-- name: SetMovieFileMissing :exec
+UPDATE movie SET
+  file_missing = $2
+WHERE id = $1;
+
+-- name: SetMovieAssetFileMissing :exec
+UPDATE movie_asset SET
+  file_missing = $2
+WHERE id = $1;
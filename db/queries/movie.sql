@@ -1,22 +1,50 @@
 -- name: GetMovie :one
-SELECT id, title, movie_path, COALESCE(amount_rates, 0) amount_rates, COALESCE(rating, 0) rating, created_at
+SELECT id, title, movie_path, COALESCE(amount_rates, 0) amount_rates,
+  COALESCE(rating_sum::FLOAT8 / NULLIF(amount_rates, 0), 0)::FLOAT8 rating, created_at,
+  duration_ms, width, height, video_codec, audio_codec, bitrate, faststart, COALESCE(view_count, 0) view_count, file_missing
 FROM (
   select * from movie where id = $1
   ) m
-LEFT JOIN ( 
-  select * from total_rating_mview where movie_id = $1
-) mrv ON m.id = mrv.movie_id;
+LEFT JOIN movie_rating_stats mrs ON m.id = mrs.movie_id
+LEFT JOIN movie_view_count_mview mvc ON m.id = mvc.movie_id;
 
 -- name: GetMovieByTitle :one
-SELECT id, title, movie_path, COALESCE(amount_rates, 0) amount_rates, COALESCE(rating, 0) rating, created_at
+SELECT id, title, movie_path, COALESCE(amount_rates, 0) amount_rates,
+  COALESCE(rating_sum::FLOAT8 / NULLIF(amount_rates, 0), 0)::FLOAT8 rating, created_at,
+  duration_ms, width, height, video_codec, audio_codec, bitrate, faststart
 FROM (
   select * from movie where title = $1
   ) m
-LEFT JOIN total_rating_mview ON m.id = mrv.movie_id;
+LEFT JOIN movie_rating_stats mrs ON m.id = mrs.movie_id;
 
 -- name: GetMovieList :many
-SELECT *
-FROM movie;
+WITH global AS (
+  SELECT COALESCE(SUM(rating_sum)::FLOAT8 / NULLIF(SUM(amount_rates), 0), 0)::FLOAT8 mean
+  FROM movie_rating_stats
+)
+SELECT ml.id, ml.title, ml.created_at, ml.movie_path, ml.duration_ms, ml.width, ml.height, ml.video_codec,
+  ml.audio_codec, ml.bitrate, ml.faststart, ml.file_missing, ml.amount_rates, ml.rating, ml.weighted_rating
+FROM (
+  SELECT m.*,
+    COALESCE(s.amount_rates, 0)::BIGINT amount_rates,
+    COALESCE(s.rating_sum::FLOAT8 / NULLIF(s.amount_rates, 0), 0)::FLOAT8 rating,
+    COALESCE(
+      (COALESCE(s.rating_sum, 0) + sqlc.arg(min_votes)::INT * g.mean)
+        / NULLIF(COALESCE(s.amount_rates, 0) + sqlc.arg(min_votes)::INT, 0),
+      0
+    )::FLOAT8 weighted_rating
+  FROM movie m
+  CROSS JOIN global g
+  LEFT JOIN movie_rating_stats s ON s.movie_id = m.id
+) ml
+ORDER BY
+  CASE sqlc.arg(sort)::TEXT
+    WHEN 'weighted' THEN ml.weighted_rating
+    WHEN 'rating' THEN ml.rating
+    WHEN 'votes' THEN ml.amount_rates
+  END DESC,
+  CASE WHEN sqlc.arg(sort)::TEXT = 'new' THEN ml.created_at END DESC,
+  ml.title;
 
 -- name: CreateMovie :one
 INSERT INTO movie(title)
@@ -28,6 +56,17 @@ UPDATE movie
 SET movie_path = $1
 WHERE id = $2;
 
+-- name: SetMovieMediaInfo :exec
+UPDATE movie SET
+  duration_ms = $2,
+  width = $3,
+  height = $4,
+  video_codec = $5,
+  audio_codec = $6,
+  bitrate = $7,
+  faststart = $8
+WHERE id = $1;
+
 -- name: UpdateMovie :one
 UPDATE movie SET
   title = COALESCE(sqlc.narg(title), title)
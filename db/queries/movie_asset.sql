@@ -0,0 +1,49 @@
+-- name: GetMovieAssetList :many
+SELECT *
+FROM movie_asset
+WHERE movie_id = $1
+ORDER BY kind, language, quality;
+
+-- name: GetMovieAsset :one
+SELECT *
+FROM movie_asset
+WHERE id = $1;
+
+-- name: CreateMovieAsset :one
+INSERT INTO movie_asset(movie_id, kind, language, quality, title)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: SetMovieAssetFile :execrows
+UPDATE movie_asset SET
+  asset_path = $2,
+  duration_ms = $3,
+  width = $4,
+  height = $5,
+  video_codec = $6,
+  audio_codec = $7,
+  bitrate = $8
+WHERE id = $1;
+
+-- name: DeleteMovieAsset :one
+DELETE FROM movie_asset
+WHERE id = $1
+RETURNING asset_path;
+
+-- name: SyncMovieMainAsset :exec
+WITH updated AS (
+  UPDATE movie_asset a SET
+    duration_ms = m.duration_ms,
+    width = m.width,
+    height = m.height,
+    video_codec = m.video_codec,
+    audio_codec = m.audio_codec,
+    bitrate = m.bitrate
+  FROM movie m
+  WHERE m.id = $1 AND a.movie_id = m.id AND a.asset_path = m.movie_path
+  RETURNING a.id
+)
+INSERT INTO movie_asset(movie_id, kind, asset_path, duration_ms, width, height, video_codec, audio_codec, bitrate)
+SELECT id, 'main', movie_path, duration_ms, width, height, video_codec, audio_codec, bitrate
+FROM movie
+WHERE id = $1 AND movie_path IS NOT NULL AND NOT EXISTS (SELECT 1 FROM updated);
@@ -0,0 +1,34 @@
+-- name: LockPlaybackSession :exec
+SELECT pg_advisory_xact_lock(hashtext('playback_session:' || sqlc.arg(session_key)::TEXT));
+
+-- name: RecordPlaybackSession :exec
+WITH touched AS (
+  UPDATE playback_session SET
+    last_seen_at = NOW()
+  WHERE movie_id = $2
+    AND session_key = $3
+    AND last_seen_at > NOW() - sqlc.arg(window_seconds)::INT * INTERVAL '1 second'
+  RETURNING id
+)
+INSERT INTO playback_session(user_id, movie_id, session_key)
+SELECT $1, $2, $3
+WHERE NOT EXISTS (SELECT 1 FROM touched);
+
+-- name: GetUserWatchHistory :many
+SELECT ps.id, ps.movie_id, m.title, ps.started_at, ps.last_seen_at
+FROM playback_session ps
+JOIN movie m ON m.id = ps.movie_id
+WHERE ps.user_id = $1
+ORDER BY ps.started_at DESC
+LIMIT $2;
+
+-- name: RemoveUserWatchHistoryEntry :execrows
+UPDATE playback_session SET
+  user_id = NULL
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: ClearUserWatchHistory :execrows
+UPDATE playback_session SET
+  user_id = NULL
+WHERE user_id = $1;
@@ -0,0 +1,53 @@
+-- name: GetMovieRatingTrend :many
+WITH bucket_event AS (
+  SELECT DATE_TRUNC(sqlc.arg(bucket_size)::TEXT, created_at)::TIMESTAMP bucket,
+    COUNT(new_rating) FILTER (WHERE NOT backfilled) rate_count,
+    (AVG(new_rating) FILTER (WHERE NOT backfilled))::FLOAT8 bucket_rating,
+    SUM(CASE action WHEN 'create' THEN 1 WHEN 'delete' THEN -1 ELSE 0 END) amount_delta,
+    SUM(COALESCE(new_rating, 0) - COALESCE(old_rating, 0)) rating_delta
+  FROM rating_event
+  WHERE movie_id = sqlc.arg(movie_id)
+  GROUP BY 1
+), trend AS (
+  SELECT bucket, rate_count, bucket_rating,
+    SUM(amount_delta) OVER (ORDER BY bucket) amount_rates,
+    SUM(rating_delta) OVER (ORDER BY bucket) rating_sum
+  FROM bucket_event
+)
+SELECT bucket, rate_count,
+  COALESCE(bucket_rating, 0)::FLOAT8 bucket_rating,
+  amount_rates::BIGINT amount_rates,
+  COALESCE(rating_sum::FLOAT8 / NULLIF(amount_rates, 0), 0)::FLOAT8 rating
+FROM trend
+WHERE bucket >= DATE_TRUNC(sqlc.arg(bucket_size)::TEXT, sqlc.arg(since)::TIMESTAMP)
+ORDER BY bucket;
+
+-- name: GetUserRatingEventList :many
+SELECT re.id, re.movie_id, m.title, re.action, re.old_rating, re.new_rating, re.created_at
+FROM rating_event re
+JOIN movie m ON m.id = re.movie_id
+WHERE re.user_id = sqlc.arg(user_id)
+  AND (NOT sqlc.arg(current_only)::BOOLEAN OR (
+    EXISTS (SELECT 1 FROM rating r WHERE r.user_id = re.user_id AND r.movie_id = re.movie_id)
+    AND NOT EXISTS (
+      SELECT 1 FROM rating_event d
+      WHERE d.user_id = re.user_id AND d.movie_id = re.movie_id
+        AND d.action = 'delete' AND d.created_at >= re.created_at
+    )
+  ))
+ORDER BY re.created_at DESC, re.id
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: CountUserRatingEvents :one
+SELECT COUNT(*)
+FROM rating_event re
+WHERE re.user_id = sqlc.arg(user_id)
+  AND (NOT sqlc.arg(current_only)::BOOLEAN OR (
+    EXISTS (SELECT 1 FROM rating r WHERE r.user_id = re.user_id AND r.movie_id = re.movie_id)
+    AND NOT EXISTS (
+      SELECT 1 FROM rating_event d
+      WHERE d.user_id = re.user_id AND d.movie_id = re.movie_id
+        AND d.action = 'delete' AND d.created_at >= re.created_at
+    )
+  ));
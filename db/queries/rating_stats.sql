@@ -0,0 +1,24 @@
+-- name: GetMovieRatingStats :one
+WITH global AS (
+  SELECT COALESCE(SUM(rating_sum)::FLOAT8 / NULLIF(SUM(amount_rates), 0), 0)::FLOAT8 mean
+  FROM movie_rating_stats
+)
+SELECT m.id movie_id,
+  COALESCE(s.amount_rates, 0)::BIGINT amount_rates,
+  COALESCE(s.rating_sum::FLOAT8 / NULLIF(s.amount_rates, 0), 0)::FLOAT8 rating,
+  COALESCE(s.histogram, ARRAY_FILL(0::BIGINT, ARRAY[10]))::BIGINT[] histogram,
+  COALESCE(
+    SQRT(GREATEST(s.rating_square_sum::FLOAT8 / NULLIF(s.amount_rates, 0) - POWER(s.rating_sum::FLOAT8 / NULLIF(s.amount_rates, 0), 2), 0)),
+    0
+  )::FLOAT8 stddev,
+  g.mean global_rating,
+  COALESCE(
+    (COALESCE(s.rating_sum, 0) + sqlc.arg(min_votes)::INT * g.mean)
+      / NULLIF(COALESCE(s.amount_rates, 0) + sqlc.arg(min_votes)::INT, 0),
+    0
+  )::FLOAT8 weighted_rating,
+  s.updated_at
+FROM movie m
+CROSS JOIN global g
+LEFT JOIN movie_rating_stats s ON s.movie_id = m.id
+WHERE m.id = sqlc.arg(movie_id);
@@ -0,0 +1,79 @@
+-- name: CreateReview :one
+INSERT INTO review (user_id, movie_id, title, body)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, movie_id) DO NOTHING
+RETURNING id, user_id, movie_id, title, body, created_at, updated_at;
+
+-- name: SetReviewRating :exec
+INSERT INTO rating (user_id, movie_id, rating)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, movie_id) DO UPDATE SET
+  rating = EXCLUDED.rating;
+
+-- name: GetReview :one
+SELECT rv.id, rv.user_id, rv.movie_id, rv.title, rv.body, rt.rating,
+  v.helpful_count, v.unhelpful_count, rv.created_at, rv.updated_at
+FROM review rv
+LEFT JOIN rating rt ON rt.user_id = rv.user_id AND rt.movie_id = rv.movie_id
+CROSS JOIN LATERAL (
+  SELECT COUNT(*) FILTER (WHERE helpful) helpful_count,
+    COUNT(*) FILTER (WHERE NOT helpful) unhelpful_count
+  FROM review_vote
+  WHERE review_id = rv.id
+) v
+WHERE rv.id = $1;
+
+-- name: GetMovieReviewList :many
+SELECT rv.id, rv.user_id, rv.movie_id, rv.title, rv.body, rt.rating,
+  v.helpful_count, v.unhelpful_count, rv.created_at, rv.updated_at
+FROM review rv
+LEFT JOIN rating rt ON rt.user_id = rv.user_id AND rt.movie_id = rv.movie_id
+CROSS JOIN LATERAL (
+  SELECT COUNT(*) FILTER (WHERE helpful) helpful_count,
+    COUNT(*) FILTER (WHERE NOT helpful) unhelpful_count
+  FROM review_vote
+  WHERE review_id = rv.id
+) v
+WHERE rv.movie_id = sqlc.arg(movie_id)
+ORDER BY CASE WHEN sqlc.arg(sort_helpful)::BOOLEAN THEN v.helpful_count - v.unhelpful_count END DESC,
+  rv.created_at DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountMovieReviews :one
+SELECT COUNT(*)
+FROM review
+WHERE movie_id = $1;
+
+-- name: UpdateReview :one
+WITH rated AS (
+  INSERT INTO rating (user_id, movie_id, rating)
+  SELECT user_id, movie_id, sqlc.narg(rating)::SMALLINT
+  FROM review
+  WHERE id = sqlc.arg(id)
+    AND sqlc.narg(rating)::SMALLINT IS NOT NULL
+  ON CONFLICT (user_id, movie_id) DO UPDATE SET
+    rating = EXCLUDED.rating
+)
+UPDATE review SET
+  title = COALESCE(sqlc.narg(title), title),
+  body = COALESCE(sqlc.narg(body), body),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING id, user_id, movie_id, title, body, created_at, updated_at;
+
+-- name: DeleteReview :execrows
+DELETE FROM review
+WHERE id = $1;
+
+-- name: SetReviewVote :one
+INSERT INTO review_vote (review_id, user_id, helpful)
+VALUES ($1, $2, $3)
+ON CONFLICT (review_id, user_id) DO UPDATE SET
+  helpful = EXCLUDED.helpful,
+  created_at = NOW()
+RETURNING *;
+
+-- name: DeleteReviewVote :execrows
+DELETE FROM review_vote
+WHERE review_id = $1
+  AND user_id = $2;
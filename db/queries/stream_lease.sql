@@ -0,0 +1,55 @@
+-- name: LockUserStreamLeases :exec
+SELECT pg_advisory_xact_lock(hashtext('stream_lease:' || sqlc.arg(user_id)::UUID::TEXT));
+
+-- name: GetActiveStreamLease :one
+SELECT *
+FROM stream_lease
+WHERE user_id = $1
+  AND session_key = $2
+  AND expires_at > NOW();
+
+-- name: CountActiveStreamLeases :one
+SELECT COUNT(*)
+FROM stream_lease
+WHERE user_id = $1
+  AND expires_at > NOW();
+
+-- name: SaveStreamLease :one
+INSERT INTO stream_lease(user_id, movie_id, session_key, device, device_id, expires_at)
+VALUES (
+  sqlc.arg(user_id), sqlc.arg(movie_id), sqlc.arg(session_key), sqlc.arg(device), sqlc.arg(device_id),
+  NOW() + sqlc.arg(ttl_seconds)::INT * INTERVAL '1 second'
+)
+ON CONFLICT (user_id, session_key) DO UPDATE SET
+  movie_id = EXCLUDED.movie_id,
+  device = EXCLUDED.device,
+  device_id = CASE
+    WHEN EXCLUDED.device_id <> '' THEN EXCLUDED.device_id
+    WHEN stream_lease.expires_at > NOW() THEN stream_lease.device_id
+    ELSE ''
+  END,
+  started_at = CASE WHEN stream_lease.expires_at > NOW() THEN stream_lease.started_at ELSE NOW() END,
+  expires_at = EXCLUDED.expires_at
+RETURNING *;
+
+-- name: GetActiveStreamLeaseList :many
+SELECT sl.id, sl.movie_id, m.title, sl.device, sl.started_at, sl.expires_at
+FROM stream_lease sl
+JOIN movie m ON m.id = sl.movie_id
+WHERE sl.user_id = $1
+  AND sl.expires_at > NOW()
+ORDER BY sl.started_at;
+
+-- name: ReleaseStreamLease :execrows
+DELETE FROM stream_lease
+WHERE user_id = $1
+  AND session_key = $2;
+
+-- name: DeleteUserStreamLease :execrows
+DELETE FROM stream_lease
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: DeleteExpiredStreamLeases :execrows
+DELETE FROM stream_lease
+WHERE expires_at <= NOW();
@@ -0,0 +1,24 @@
+-- name: GetMovieSubtitleList :many
+SELECT id, movie_id, language, label, kind, source_format, created_at
+FROM subtitle
+WHERE movie_id = $1
+ORDER BY language, kind;
+
+-- name: GetMovieSubtitle :one
+SELECT *
+FROM subtitle
+WHERE id = $1 AND movie_id = $2;
+
+-- name: CreateSubtitle :one
+INSERT INTO subtitle(movie_id, language, label, kind, source_format, content)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (movie_id, language, kind) DO UPDATE SET
+  label = EXCLUDED.label,
+  source_format = EXCLUDED.source_format,
+  content = EXCLUDED.content,
+  created_at = NOW()
+RETURNING id, movie_id, language, label, kind, source_format, created_at;
+
+-- name: DeleteSubtitle :execrows
+DELETE FROM subtitle
+WHERE id = $1 AND movie_id = $2;
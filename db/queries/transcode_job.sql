@@ -0,0 +1,79 @@
+-- name: GetMovieTranscodeJobList :many
+SELECT *
+FROM transcode_job
+WHERE movie_id = $1
+ORDER BY created_at DESC;
+
+-- name: CreateTranscodeJob :one
+INSERT INTO transcode_job(movie_id, kind)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: ClaimTranscodeJob :one
+UPDATE transcode_job SET
+  status = 'running',
+  attempts = attempts + 1,
+  updated_at = NOW()
+WHERE id = (
+  SELECT id
+  FROM transcode_job
+  WHERE status = 'queued'
+    AND run_after <= NOW()
+  ORDER BY run_after
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: UpdateTranscodeJobProgress :execrows
+UPDATE transcode_job SET
+  stage = $2,
+  progress = $3,
+  updated_at = NOW()
+WHERE id = $1
+  AND status = 'running'
+  AND attempts = sqlc.arg(attempts);
+
+-- name: HeartbeatTranscodeJob :execrows
+UPDATE transcode_job SET
+  updated_at = NOW()
+WHERE id = $1
+  AND status = 'running'
+  AND attempts = sqlc.arg(attempts);
+
+-- name: CompleteTranscodeJob :execrows
+UPDATE transcode_job SET
+  status = 'done',
+  progress = 100,
+  last_error = NULL,
+  updated_at = NOW()
+WHERE id = $1
+  AND status = 'running'
+  AND attempts = sqlc.arg(attempts);
+
+-- name: RetryTranscodeJob :execrows
+UPDATE transcode_job SET
+  status = 'queued',
+  last_error = $2,
+  run_after = NOW() + sqlc.arg(backoff_seconds)::INT * INTERVAL '1 second',
+  updated_at = NOW()
+WHERE id = $1
+  AND status = 'running'
+  AND attempts = sqlc.arg(attempts);
+
+-- name: FailTranscodeJob :execrows
+UPDATE transcode_job SET
+  status = 'failed',
+  last_error = $2,
+  updated_at = NOW()
+WHERE id = $1
+  AND status = 'running'
+  AND attempts = sqlc.arg(attempts);
+
+-- name: ResetStaleTranscodeJobs :execrows
+UPDATE transcode_job SET
+  status = CASE WHEN attempts >= max_attempts THEN 'failed' ELSE 'queued' END,
+  last_error = 'worker stopped responding',
+  updated_at = NOW()
+WHERE status = 'running'
+  AND updated_at < NOW() - sqlc.arg(stale_seconds)::INT * INTERVAL '1 second';
@@ -0,0 +1,12 @@
+-- name: GetUserPreference :one
+SELECT *
+FROM user_preference
+WHERE user_id = $1;
+
+-- name: SetUserPreference :one
+INSERT INTO user_preference (user_id, hide_spoilers)
+VALUES (sqlc.arg(user_id), COALESCE(sqlc.narg(hide_spoilers), TRUE))
+ON CONFLICT (user_id) DO UPDATE SET
+  hide_spoilers = COALESCE(sqlc.narg(hide_spoilers), user_preference.hide_spoilers),
+  updated_at = NOW()
+RETURNING *;
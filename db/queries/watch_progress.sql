@@ -0,0 +1,33 @@
+-- name: GetContinueWatchingList :many
+SELECT wp.movie_id, m.title, wp.position_ms, wp.duration_ms, wp.updated_at
+FROM watch_progress wp
+JOIN movie m ON m.id = wp.movie_id
+WHERE wp.user_id = $1
+  AND NOT wp.watched
+ORDER BY wp.updated_at DESC
+LIMIT $2;
+
+-- name: GetWatchProgress :one
+SELECT *
+FROM watch_progress
+WHERE user_id = $1
+  AND movie_id = $2;
+
+-- name: SaveWatchProgress :one
+INSERT INTO watch_progress(user_id, movie_id, position_ms, duration_ms, watched)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, movie_id) DO UPDATE SET
+  position_ms = EXCLUDED.position_ms,
+  duration_ms = EXCLUDED.duration_ms,
+  watched = CASE WHEN sqlc.arg(rewatch)::BOOLEAN THEN EXCLUDED.watched
+    ELSE watch_progress.watched OR EXCLUDED.watched END,
+  updated_at = NOW()
+WHERE watch_progress.updated_at < NOW() - sqlc.arg(throttle_seconds)::INT * INTERVAL '1 second'
+  OR sqlc.arg(rewatch)::BOOLEAN
+  OR (EXCLUDED.watched AND NOT watch_progress.watched)
+RETURNING watched;
+
+-- name: DeleteWatchProgress :execrows
+DELETE FROM watch_progress
+WHERE user_id = $1
+  AND movie_id = $2;
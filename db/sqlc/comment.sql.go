@@ -11,20 +11,51 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countMovieRootComments = `-- name: CountMovieRootComments :one
+SELECT COUNT(*)
+FROM comment c
+WHERE c.movie_id = $1
+  AND c.parent_id IS NULL
+  AND ((c.state = 'visible' AND NOT c.is_deleted) OR EXISTS (SELECT 1 FROM comment r WHERE r.parent_id = c.id))
+`
+
+func (q *Queries) CountMovieRootComments(ctx context.Context, movieID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countMovieRootComments, movieID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createComment = `-- name: CreateComment :one
-INSERT INTO comment (user_id, movie_id, text)
-VALUES ($1, $2, $3)
-RETURNING id, user_id, movie_id, text, created_at
+INSERT INTO comment (user_id, movie_id, parent_id, depth, text, is_spoiler, state, filter_reason, language)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, user_id, movie_id, text, created_at, parent_id, depth, is_deleted, state, filter_reason, edited_at, is_spoiler, language
 `
 
 type CreateCommentParams struct {
-	UserID  pgtype.UUID `json:"user_id"`
-	MovieID pgtype.UUID `json:"movie_id"`
-	Text    string      `json:"text"`
+	UserID       pgtype.UUID `json:"user_id"`
+	MovieID      pgtype.UUID `json:"movie_id"`
+	ParentID     pgtype.UUID `json:"parent_id"`
+	Depth        int16       `json:"depth"`
+	Text         string      `json:"text"`
+	IsSpoiler    bool        `json:"is_spoiler"`
+	State        string      `json:"state"`
+	FilterReason *string     `json:"filter_reason"`
+	Language     *string     `json:"language"`
 }
 
 func (q *Queries) CreateComment(ctx context.Context, arg CreateCommentParams) (Comment, error) {
-	row := q.db.QueryRow(ctx, createComment, arg.UserID, arg.MovieID, arg.Text)
+	row := q.db.QueryRow(ctx, createComment,
+		arg.UserID,
+		arg.MovieID,
+		arg.ParentID,
+		arg.Depth,
+		arg.Text,
+		arg.IsSpoiler,
+		arg.State,
+		arg.FilterReason,
+		arg.Language,
+	)
 	var i Comment
 	err := row.Scan(
 		&i.ID,
@@ -32,6 +63,14 @@ func (q *Queries) CreateComment(ctx context.Context, arg CreateCommentParams) (C
 		&i.MovieID,
 		&i.Text,
 		&i.CreatedAt,
+		&i.ParentID,
+		&i.Depth,
+		&i.IsDeleted,
+		&i.State,
+		&i.FilterReason,
+		&i.EditedAt,
+		&i.IsSpoiler,
+		&i.Language,
 	)
 	return i, err
 }
@@ -39,6 +78,7 @@ func (q *Queries) CreateComment(ctx context.Context, arg CreateCommentParams) (C
 const deleteComment = `-- name: DeleteComment :execrows
 DELETE FROM comment
 WHERE id = $1
+  AND is_deleted = FALSE
 `
 
 func (q *Queries) DeleteComment(ctx context.Context, id pgtype.UUID) (int64, error) {
@@ -50,7 +90,7 @@ func (q *Queries) DeleteComment(ctx context.Context, id pgtype.UUID) (int64, err
 }
 
 const getComment = `-- name: GetComment :one
-SELECT id, user_id, movie_id, text, created_at
+SELECT id, user_id, movie_id, text, created_at, parent_id, depth, is_deleted, state, filter_reason, edited_at, is_spoiler, language
 FROM comment
 WHERE id = $1
 `
@@ -64,25 +104,165 @@ func (q *Queries) GetComment(ctx context.Context, id pgtype.UUID) (Comment, erro
 		&i.MovieID,
 		&i.Text,
 		&i.CreatedAt,
+		&i.ParentID,
+		&i.Depth,
+		&i.IsDeleted,
+		&i.State,
+		&i.FilterReason,
+		&i.EditedAt,
+		&i.IsSpoiler,
+		&i.Language,
 	)
 	return i, err
 }
 
+const getCommentReplyTree = `-- name: GetCommentReplyTree :many
+WITH RECURSIVE thread AS (
+  SELECT id, user_id, parent_id, depth, text, is_spoiler, is_deleted, state, created_at, edited_at
+  FROM comment
+  WHERE parent_id = ANY($1::UUID[])
+  UNION ALL
+  SELECT c.id, c.user_id, c.parent_id, c.depth, c.text, c.is_spoiler, c.is_deleted, c.state, c.created_at, c.edited_at
+  FROM comment c
+  JOIN thread t ON c.parent_id = t.id
+)
+SELECT t.id, t.user_id, t.parent_id, t.depth, t.text, t.is_spoiler, t.is_deleted, t.state, t.created_at, t.edited_at,
+  (SELECT COUNT(*) FROM comment r WHERE r.parent_id = t.id
+     AND ((r.state = 'visible' AND NOT r.is_deleted) OR EXISTS (SELECT 1 FROM comment rr WHERE rr.parent_id = r.id))) reply_count,
+  (SELECT COALESCE(SUM(CASE WHEN cr.reaction = 'dislike' THEN -1 ELSE 1 END), 0)::BIGINT
+   FROM comment_reaction cr WHERE cr.comment_id = t.id) score
+FROM thread t
+WHERE (t.state = 'visible' AND NOT t.is_deleted) OR EXISTS (SELECT 1 FROM comment r WHERE r.parent_id = t.id)
+ORDER BY t.created_at
+`
+
+type GetCommentReplyTreeRow struct {
+	ID         pgtype.UUID      `json:"id"`
+	UserID     pgtype.UUID      `json:"user_id"`
+	ParentID   pgtype.UUID      `json:"parent_id"`
+	Depth      int16            `json:"depth"`
+	Text       string           `json:"text"`
+	IsSpoiler  bool             `json:"is_spoiler"`
+	IsDeleted  bool             `json:"is_deleted"`
+	State      string           `json:"state"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	EditedAt   pgtype.Timestamp `json:"edited_at"`
+	ReplyCount int64            `json:"reply_count"`
+	Score      int64            `json:"score"`
+}
+
+func (q *Queries) GetCommentReplyTree(ctx context.Context, rootIds []pgtype.UUID) ([]GetCommentReplyTreeRow, error) {
+	rows, err := q.db.Query(ctx, getCommentReplyTree, rootIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCommentReplyTreeRow
+	for rows.Next() {
+		var i GetCommentReplyTreeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ParentID,
+			&i.Depth,
+			&i.Text,
+			&i.IsSpoiler,
+			&i.IsDeleted,
+			&i.State,
+			&i.CreatedAt,
+			&i.EditedAt,
+			&i.ReplyCount,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCommentRevisionList = `-- name: GetCommentRevisionList :many
+SELECT id, comment_id, text, created_at
+FROM comment_revision
+WHERE comment_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) GetCommentRevisionList(ctx context.Context, commentID pgtype.UUID) ([]CommentRevision, error) {
+	rows, err := q.db.Query(ctx, getCommentRevisionList, commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CommentRevision
+	for rows.Next() {
+		var i CommentRevision
+		if err := rows.Scan(
+			&i.ID,
+			&i.CommentID,
+			&i.Text,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getMovieCommentList = `-- name: GetMovieCommentList :many
-SELECT id, user_id, text, created_at
-FROM comment
-WHERE movie_id = $1
+SELECT c.id, c.user_id, c.parent_id, c.depth, c.text, c.is_spoiler, c.is_deleted, c.state, c.created_at, c.edited_at,
+  (SELECT COUNT(*) FROM comment r WHERE r.parent_id = c.id
+     AND ((r.state = 'visible' AND NOT r.is_deleted) OR EXISTS (SELECT 1 FROM comment rr WHERE rr.parent_id = r.id))) reply_count,
+  s.score
+FROM comment c
+CROSS JOIN LATERAL (
+  SELECT COALESCE(SUM(CASE WHEN cr.reaction = 'dislike' THEN -1 ELSE 1 END), 0)::BIGINT score
+  FROM comment_reaction cr
+  WHERE cr.comment_id = c.id
+) s
+WHERE c.movie_id = $1
+  AND c.parent_id IS NULL
+  AND ((c.state = 'visible' AND NOT c.is_deleted) OR EXISTS (SELECT 1 FROM comment r WHERE r.parent_id = c.id))
+ORDER BY CASE WHEN $2::BOOLEAN THEN s.score END DESC, c.created_at DESC
+LIMIT $3 OFFSET $4
 `
 
+type GetMovieCommentListParams struct {
+	MovieID    pgtype.UUID `json:"movie_id"`
+	SortTop    bool        `json:"sort_top"`
+	PageLimit  int32       `json:"page_limit"`
+	PageOffset int32       `json:"page_offset"`
+}
+
 type GetMovieCommentListRow struct {
-	ID        pgtype.UUID      `json:"id"`
-	UserID    pgtype.UUID      `json:"user_id"`
-	Text      string           `json:"text"`
-	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ID         pgtype.UUID      `json:"id"`
+	UserID     pgtype.UUID      `json:"user_id"`
+	ParentID   pgtype.UUID      `json:"parent_id"`
+	Depth      int16            `json:"depth"`
+	Text       string           `json:"text"`
+	IsSpoiler  bool             `json:"is_spoiler"`
+	IsDeleted  bool             `json:"is_deleted"`
+	State      string           `json:"state"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	EditedAt   pgtype.Timestamp `json:"edited_at"`
+	ReplyCount int64            `json:"reply_count"`
+	Score      int64            `json:"score"`
 }
 
-func (q *Queries) GetMovieCommentList(ctx context.Context, movieID pgtype.UUID) ([]GetMovieCommentListRow, error) {
-	rows, err := q.db.Query(ctx, getMovieCommentList, movieID)
+func (q *Queries) GetMovieCommentList(ctx context.Context, arg GetMovieCommentListParams) ([]GetMovieCommentListRow, error) {
+	rows, err := q.db.Query(ctx, getMovieCommentList,
+		arg.MovieID,
+		arg.SortTop,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -93,8 +273,16 @@ func (q *Queries) GetMovieCommentList(ctx context.Context, movieID pgtype.UUID)
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
+			&i.ParentID,
+			&i.Depth,
 			&i.Text,
+			&i.IsSpoiler,
+			&i.IsDeleted,
+			&i.State,
 			&i.CreatedAt,
+			&i.EditedAt,
+			&i.ReplyCount,
+			&i.Score,
 		); err != nil {
 			return nil, err
 		}
@@ -106,17 +294,56 @@ func (q *Queries) GetMovieCommentList(ctx context.Context, movieID pgtype.UUID)
 	return items, nil
 }
 
+const getRecentUserCommentTextList = `-- name: GetRecentUserCommentTextList :many
+SELECT text
+FROM comment
+WHERE user_id = $1
+  AND created_at > $2
+  AND id IS DISTINCT FROM $3
+  AND is_deleted = FALSE
+`
+
+type GetRecentUserCommentTextListParams struct {
+	UserID    pgtype.UUID      `json:"user_id"`
+	Since     pgtype.Timestamp `json:"since"`
+	ExcludeID pgtype.UUID      `json:"exclude_id"`
+}
+
+func (q *Queries) GetRecentUserCommentTextList(ctx context.Context, arg GetRecentUserCommentTextListParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, getRecentUserCommentTextList, arg.UserID, arg.Since, arg.ExcludeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var text string
+		if err := rows.Scan(&text); err != nil {
+			return nil, err
+		}
+		items = append(items, text)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserCommentList = `-- name: GetUserCommentList :many
-SELECT id, movie_id, text, created_at
+SELECT id, movie_id, text, is_spoiler, created_at, edited_at
 FROM comment
 WHERE user_id = $1
+  AND is_deleted = FALSE
+  AND state = 'visible'
 `
 
 type GetUserCommentListRow struct {
 	ID        pgtype.UUID      `json:"id"`
 	MovieID   pgtype.UUID      `json:"movie_id"`
 	Text      string           `json:"text"`
+	IsSpoiler bool             `json:"is_spoiler"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
+	EditedAt  pgtype.Timestamp `json:"edited_at"`
 }
 
 func (q *Queries) GetUserCommentList(ctx context.Context, userID pgtype.UUID) ([]GetUserCommentListRow, error) {
@@ -132,7 +359,9 @@ func (q *Queries) GetUserCommentList(ctx context.Context, userID pgtype.UUID) ([
 			&i.ID,
 			&i.MovieID,
 			&i.Text,
+			&i.IsSpoiler,
 			&i.CreatedAt,
+			&i.EditedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -144,20 +373,120 @@ func (q *Queries) GetUserCommentList(ctx context.Context, userID pgtype.UUID) ([
 	return items, nil
 }
 
-const updateComment = `-- name: UpdateComment :one
+const lockComment = `-- name: LockComment :execrows
+SELECT id
+FROM comment
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockComment(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, lockComment, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setCommentState = `-- name: SetCommentState :one
 UPDATE comment SET
-  text = $2
-WHERE id = $1 
-RETURNING id, user_id, movie_id, text, created_at
+  state = $2
+WHERE id = $1
+RETURNING id, user_id, movie_id, text, created_at, parent_id, depth, is_deleted, state, filter_reason, edited_at, is_spoiler, language
+`
+
+type SetCommentStateParams struct {
+	ID    pgtype.UUID `json:"id"`
+	State string      `json:"state"`
+}
+
+func (q *Queries) SetCommentState(ctx context.Context, arg SetCommentStateParams) (Comment, error) {
+	row := q.db.QueryRow(ctx, setCommentState, arg.ID, arg.State)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.MovieID,
+		&i.Text,
+		&i.CreatedAt,
+		&i.ParentID,
+		&i.Depth,
+		&i.IsDeleted,
+		&i.State,
+		&i.FilterReason,
+		&i.EditedAt,
+		&i.IsSpoiler,
+		&i.Language,
+	)
+	return i, err
+}
+
+const tombstoneComment = `-- name: TombstoneComment :execrows
+WITH previous AS (
+  SELECT id, text
+  FROM comment
+  WHERE id = $1
+    AND is_deleted = FALSE
+    AND (EXISTS (SELECT 1 FROM comment r WHERE r.parent_id = $1)
+      OR EXISTS (SELECT 1 FROM comment_revision cr WHERE cr.comment_id = $1)
+      OR EXISTS (SELECT 1 FROM comment_report rp WHERE rp.comment_id = $1))
+  FOR UPDATE
+), revision AS (
+  INSERT INTO comment_revision (comment_id, text)
+  SELECT id, text FROM previous
+)
+UPDATE comment c SET
+  is_deleted = TRUE,
+  text = '',
+  state = 'visible'
+FROM previous
+WHERE c.id = previous.id
+`
+
+func (q *Queries) TombstoneComment(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, tombstoneComment, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateComment = `-- name: UpdateComment :one
+WITH previous AS (
+  SELECT id, text
+  FROM comment
+  WHERE id = $1
+    AND is_deleted = FALSE
+  FOR UPDATE
+), revision AS (
+  INSERT INTO comment_revision (comment_id, text)
+  SELECT id, text FROM previous
+)
+UPDATE comment c SET
+  text = $2,
+  is_spoiler = COALESCE($3, c.is_spoiler),
+  edited_at = NOW(),
+  state = CASE WHEN $4::TEXT IS NULL OR c.state = 'hidden' THEN c.state ELSE 'pending' END,
+  filter_reason = COALESCE($4, c.filter_reason)
+FROM previous
+WHERE c.id = previous.id
+RETURNING c.id, c.user_id, c.movie_id, c.text, c.created_at, c.parent_id, c.depth, c.is_deleted, c.state, c.filter_reason, c.edited_at, c.is_spoiler, c.language
 `
 
 type UpdateCommentParams struct {
-	ID   pgtype.UUID `json:"id"`
-	Text string      `json:"text"`
+	ID           pgtype.UUID `json:"id"`
+	Text         string      `json:"text"`
+	IsSpoiler    *bool       `json:"is_spoiler"`
+	FilterReason *string     `json:"filter_reason"`
 }
 
 func (q *Queries) UpdateComment(ctx context.Context, arg UpdateCommentParams) (Comment, error) {
-	row := q.db.QueryRow(ctx, updateComment, arg.ID, arg.Text)
+	row := q.db.QueryRow(ctx, updateComment,
+		arg.ID,
+		arg.Text,
+		arg.IsSpoiler,
+		arg.FilterReason,
+	)
 	var i Comment
 	err := row.Scan(
 		&i.ID,
@@ -165,6 +494,14 @@ func (q *Queries) UpdateComment(ctx context.Context, arg UpdateCommentParams) (C
 		&i.MovieID,
 		&i.Text,
 		&i.CreatedAt,
+		&i.ParentID,
+		&i.Depth,
+		&i.IsDeleted,
+		&i.State,
+		&i.FilterReason,
+		&i.EditedAt,
+		&i.IsSpoiler,
+		&i.Language,
 	)
 	return i, err
 }
@@ -9,23 +9,136 @@ import (
 )
 
 type Comment struct {
-	ID        pgtype.UUID      `json:"id"`
+	ID           pgtype.UUID      `json:"id"`
+	UserID       pgtype.UUID      `json:"user_id"`
+	MovieID      pgtype.UUID      `json:"movie_id"`
+	Text         string           `json:"text"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	ParentID     pgtype.UUID      `json:"parent_id"`
+	Depth        int16            `json:"depth"`
+	IsDeleted    bool             `json:"is_deleted"`
+	State        string           `json:"state"`
+	FilterReason *string          `json:"filter_reason"`
+	EditedAt     pgtype.Timestamp `json:"edited_at"`
+	IsSpoiler    bool             `json:"is_spoiler"`
+	Language     *string          `json:"language"`
+}
+
+type CommentReaction struct {
 	UserID    pgtype.UUID      `json:"user_id"`
-	MovieID   pgtype.UUID      `json:"movie_id"`
+	CommentID pgtype.UUID      `json:"comment_id"`
+	Reaction  string           `json:"reaction"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type CommentReport struct {
+	ID         pgtype.UUID      `json:"id"`
+	CommentID  pgtype.UUID      `json:"comment_id"`
+	UserID     pgtype.UUID      `json:"user_id"`
+	Reason     string           `json:"reason"`
+	Message    *string          `json:"message"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	ResolvedAt pgtype.Timestamp `json:"resolved_at"`
+	Resolution *string          `json:"resolution"`
+}
+
+type CommentRevision struct {
+	ID        pgtype.UUID      `json:"id"`
+	CommentID pgtype.UUID      `json:"comment_id"`
 	Text      string           `json:"text"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
 
+type DownloadGrant struct {
+	ID            pgtype.UUID      `json:"id"`
+	UserID        pgtype.UUID      `json:"user_id"`
+	MovieID       pgtype.UUID      `json:"movie_id"`
+	MaxDownloads  int32            `json:"max_downloads"`
+	DownloadCount int32            `json:"download_count"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	ExpiresAt     pgtype.Timestamp `json:"expires_at"`
+	DownloadToken *string          `json:"download_token"`
+}
+
+type DownloadLog struct {
+	ID           pgtype.UUID      `json:"id"`
+	GrantID      pgtype.UUID      `json:"grant_id"`
+	UserID       pgtype.UUID      `json:"user_id"`
+	MovieID      pgtype.UUID      `json:"movie_id"`
+	DownloadedAt pgtype.Timestamp `json:"downloaded_at"`
+}
+
 type Favorite struct {
 	UserID  pgtype.UUID `json:"user_id"`
 	MovieID pgtype.UUID `json:"movie_id"`
 }
 
-type Movie struct {
+type IngestLog struct {
 	ID        pgtype.UUID      `json:"id"`
+	FileName  string           `json:"file_name"`
+	FileSize  int64            `json:"file_size"`
+	MovieID   pgtype.UUID      `json:"movie_id"`
 	Title     string           `json:"title"`
+	Status    string           `json:"status"`
+	Action    *string          `json:"action"`
+	Message   *string          `json:"message"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
-	MoviePath *string          `json:"movie_path"`
+}
+
+type Movie struct {
+	ID          pgtype.UUID      `json:"id"`
+	Title       string           `json:"title"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	MoviePath   *string          `json:"movie_path"`
+	DurationMs  *int64           `json:"duration_ms"`
+	Width       *int32           `json:"width"`
+	Height      *int32           `json:"height"`
+	VideoCodec  *string          `json:"video_codec"`
+	AudioCodec  *string          `json:"audio_codec"`
+	Bitrate     *int64           `json:"bitrate"`
+	Faststart   *bool            `json:"faststart"`
+	FileMissing bool             `json:"file_missing"`
+}
+
+type MovieAsset struct {
+	ID          pgtype.UUID      `json:"id"`
+	MovieID     pgtype.UUID      `json:"movie_id"`
+	Kind        string           `json:"kind"`
+	Language    *string          `json:"language"`
+	Quality     *string          `json:"quality"`
+	Title       *string          `json:"title"`
+	AssetPath   *string          `json:"asset_path"`
+	DurationMs  *int64           `json:"duration_ms"`
+	Width       *int32           `json:"width"`
+	Height      *int32           `json:"height"`
+	VideoCodec  *string          `json:"video_codec"`
+	AudioCodec  *string          `json:"audio_codec"`
+	Bitrate     *int64           `json:"bitrate"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	FileMissing bool             `json:"file_missing"`
+}
+
+type MovieRatingStat struct {
+	MovieID         pgtype.UUID      `json:"movie_id"`
+	AmountRates     int64            `json:"amount_rates"`
+	RatingSum       int64            `json:"rating_sum"`
+	RatingSquareSum int64            `json:"rating_square_sum"`
+	Histogram       []int64          `json:"histogram"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+}
+
+type MovieViewCountMview struct {
+	MovieID   pgtype.UUID `json:"movie_id"`
+	ViewCount int64       `json:"view_count"`
+}
+
+type PlaybackSession struct {
+	ID         pgtype.UUID      `json:"id"`
+	UserID     pgtype.UUID      `json:"user_id"`
+	MovieID    pgtype.UUID      `json:"movie_id"`
+	SessionKey string           `json:"session_key"`
+	StartedAt  pgtype.Timestamp `json:"started_at"`
+	LastSeenAt pgtype.Timestamp `json:"last_seen_at"`
 }
 
 type Rating struct {
@@ -34,10 +147,69 @@ type Rating struct {
 	Rating  int16       `json:"rating"`
 }
 
-type TotalRatingMview struct {
-	MovieID     pgtype.UUID `json:"movie_id"`
-	AmountRates int64       `json:"amount_rates"`
-	Rating      float64     `json:"rating"`
+type RatingEvent struct {
+	ID         pgtype.UUID      `json:"id"`
+	UserID     pgtype.UUID      `json:"user_id"`
+	MovieID    pgtype.UUID      `json:"movie_id"`
+	Action     string           `json:"action"`
+	OldRating  *int16           `json:"old_rating"`
+	NewRating  *int16           `json:"new_rating"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	Backfilled bool             `json:"backfilled"`
+}
+
+type Review struct {
+	ID        pgtype.UUID      `json:"id"`
+	UserID    pgtype.UUID      `json:"user_id"`
+	MovieID   pgtype.UUID      `json:"movie_id"`
+	Title     string           `json:"title"`
+	Body      string           `json:"body"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type ReviewVote struct {
+	ReviewID  pgtype.UUID      `json:"review_id"`
+	UserID    pgtype.UUID      `json:"user_id"`
+	Helpful   bool             `json:"helpful"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type StreamLease struct {
+	ID         pgtype.UUID      `json:"id"`
+	UserID     pgtype.UUID      `json:"user_id"`
+	MovieID    pgtype.UUID      `json:"movie_id"`
+	SessionKey string           `json:"session_key"`
+	Device     string           `json:"device"`
+	StartedAt  pgtype.Timestamp `json:"started_at"`
+	ExpiresAt  pgtype.Timestamp `json:"expires_at"`
+	DeviceID   string           `json:"device_id"`
+}
+
+type Subtitle struct {
+	ID           pgtype.UUID      `json:"id"`
+	MovieID      pgtype.UUID      `json:"movie_id"`
+	Language     string           `json:"language"`
+	Label        *string          `json:"label"`
+	Kind         string           `json:"kind"`
+	SourceFormat string           `json:"source_format"`
+	Content      string           `json:"content"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+}
+
+type TranscodeJob struct {
+	ID          pgtype.UUID      `json:"id"`
+	MovieID     pgtype.UUID      `json:"movie_id"`
+	Status      string           `json:"status"`
+	Stage       string           `json:"stage"`
+	Progress    int16            `json:"progress"`
+	Attempts    int32            `json:"attempts"`
+	MaxAttempts int32            `json:"max_attempts"`
+	LastError   *string          `json:"last_error"`
+	RunAfter    pgtype.Timestamp `json:"run_after"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	Kind        string           `json:"kind"`
 }
 
 type UserDatum struct {
@@ -48,3 +220,27 @@ type UserDatum struct {
 	IsAdmin         bool             `json:"-"`
 	CreatedAt       pgtype.Timestamp `json:"created_at"`
 }
+
+type UserPreference struct {
+	UserID       pgtype.UUID      `json:"user_id"`
+	HideSpoilers bool             `json:"hide_spoilers"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+}
+
+type UserWarning struct {
+	ID          pgtype.UUID      `json:"id"`
+	UserID      pgtype.UUID      `json:"user_id"`
+	CommentID   pgtype.UUID      `json:"comment_id"`
+	ModeratorID pgtype.UUID      `json:"moderator_id"`
+	Reason      string           `json:"reason"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+type WatchProgress struct {
+	UserID     pgtype.UUID      `json:"user_id"`
+	MovieID    pgtype.UUID      `json:"movie_id"`
+	PositionMs int64            `json:"position_ms"`
+	DurationMs *int64           `json:"duration_ms"`
+	Watched    bool             `json:"watched"`
+	UpdatedAt  pgtype.Timestamp `json:"updated_at"`
+}
@@ -33,7 +33,7 @@ func (q *Queries) AddMoviePath(ctx context.Context, arg AddMoviePathParams) (int
 const createMovie = `-- name: CreateMovie :one
 INSERT INTO movie(title)
 VALUES ($1)
-RETURNING id, title, created_at, movie_path
+RETURNING id, title, created_at, movie_path, duration_ms, width, height, video_codec, audio_codec, bitrate, faststart, file_missing
 `
 
 func (q *Queries) CreateMovie(ctx context.Context, title string) (Movie, error) {
@@ -44,6 +44,14 @@ func (q *Queries) CreateMovie(ctx context.Context, title string) (Movie, error)
 		&i.Title,
 		&i.CreatedAt,
 		&i.MoviePath,
+		&i.DurationMs,
+		&i.Width,
+		&i.Height,
+		&i.VideoCodec,
+		&i.AudioCodec,
+		&i.Bitrate,
+		&i.Faststart,
+		&i.FileMissing,
 	)
 	return i, err
 }
@@ -62,13 +70,14 @@ func (q *Queries) DeleteMovie(ctx context.Context, id pgtype.UUID) (int64, error
 }
 
 const getMovie = `-- name: GetMovie :one
-SELECT id, title, movie_path, COALESCE(amount_rates, 0) amount_rates, COALESCE(rating, 0) rating, created_at
+SELECT id, title, movie_path, COALESCE(amount_rates, 0) amount_rates,
+  COALESCE(rating_sum::FLOAT8 / NULLIF(amount_rates, 0), 0)::FLOAT8 rating, created_at,
+  duration_ms, width, height, video_codec, audio_codec, bitrate, faststart, COALESCE(view_count, 0) view_count, file_missing
 FROM (
-  select id, title, created_at, movie_path from movie where id = $1
+  select id, title, created_at, movie_path, duration_ms, width, height, video_codec, audio_codec, bitrate, faststart, file_missing from movie where id = $1
   ) m
-LEFT JOIN ( 
-  select movie_id, amount_rates, rating from total_rating_mview where movie_id = $1
-) mrv ON m.id = mrv.movie_id
+LEFT JOIN movie_rating_stats mrs ON m.id = mrs.movie_id
+LEFT JOIN movie_view_count_mview mvc ON m.id = mvc.movie_id
 `
 
 type GetMovieRow struct {
@@ -78,6 +87,15 @@ type GetMovieRow struct {
 	AmountRates int64            `json:"amount_rates"`
 	Rating      float64          `json:"rating"`
 	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	DurationMs  *int64           `json:"duration_ms"`
+	Width       *int32           `json:"width"`
+	Height      *int32           `json:"height"`
+	VideoCodec  *string          `json:"video_codec"`
+	AudioCodec  *string          `json:"audio_codec"`
+	Bitrate     *int64           `json:"bitrate"`
+	Faststart   *bool            `json:"faststart"`
+	ViewCount   int64            `json:"view_count"`
+	FileMissing bool             `json:"file_missing"`
 }
 
 func (q *Queries) GetMovie(ctx context.Context, id pgtype.UUID) (GetMovieRow, error) {
@@ -90,16 +108,27 @@ func (q *Queries) GetMovie(ctx context.Context, id pgtype.UUID) (GetMovieRow, er
 		&i.AmountRates,
 		&i.Rating,
 		&i.CreatedAt,
+		&i.DurationMs,
+		&i.Width,
+		&i.Height,
+		&i.VideoCodec,
+		&i.AudioCodec,
+		&i.Bitrate,
+		&i.Faststart,
+		&i.ViewCount,
+		&i.FileMissing,
 	)
 	return i, err
 }
 
 const getMovieByTitle = `-- name: GetMovieByTitle :one
-SELECT id, title, movie_path, COALESCE(amount_rates, 0) amount_rates, COALESCE(rating, 0) rating, created_at
+SELECT id, title, movie_path, COALESCE(amount_rates, 0) amount_rates,
+  COALESCE(rating_sum::FLOAT8 / NULLIF(amount_rates, 0), 0)::FLOAT8 rating, created_at,
+  duration_ms, width, height, video_codec, audio_codec, bitrate, faststart
 FROM (
-  select id, title, created_at, movie_path from movie where title = $1
+  select id, title, created_at, movie_path, duration_ms, width, height, video_codec, audio_codec, bitrate, faststart, file_missing from movie where title = $1
   ) m
-LEFT JOIN total_rating_mview ON m.id = mrv.movie_id
+LEFT JOIN movie_rating_stats mrs ON m.id = mrs.movie_id
 `
 
 type GetMovieByTitleRow struct {
@@ -109,6 +138,13 @@ type GetMovieByTitleRow struct {
 	AmountRates int64            `json:"amount_rates"`
 	Rating      float64          `json:"rating"`
 	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	DurationMs  *int64           `json:"duration_ms"`
+	Width       *int32           `json:"width"`
+	Height      *int32           `json:"height"`
+	VideoCodec  *string          `json:"video_codec"`
+	AudioCodec  *string          `json:"audio_codec"`
+	Bitrate     *int64           `json:"bitrate"`
+	Faststart   *bool            `json:"faststart"`
 }
 
 func (q *Queries) GetMovieByTitle(ctx context.Context, title string) (GetMovieByTitleRow, error) {
@@ -121,29 +157,95 @@ func (q *Queries) GetMovieByTitle(ctx context.Context, title string) (GetMovieBy
 		&i.AmountRates,
 		&i.Rating,
 		&i.CreatedAt,
+		&i.DurationMs,
+		&i.Width,
+		&i.Height,
+		&i.VideoCodec,
+		&i.AudioCodec,
+		&i.Bitrate,
+		&i.Faststart,
 	)
 	return i, err
 }
 
 const getMovieList = `-- name: GetMovieList :many
-SELECT id, title, created_at, movie_path
-FROM movie
+WITH global AS (
+  SELECT COALESCE(SUM(rating_sum)::FLOAT8 / NULLIF(SUM(amount_rates), 0), 0)::FLOAT8 mean
+  FROM movie_rating_stats
+)
+SELECT ml.id, ml.title, ml.created_at, ml.movie_path, ml.duration_ms, ml.width, ml.height, ml.video_codec,
+  ml.audio_codec, ml.bitrate, ml.faststart, ml.file_missing, ml.amount_rates, ml.rating, ml.weighted_rating
+FROM (
+  SELECT m.*,
+    COALESCE(s.amount_rates, 0)::BIGINT amount_rates,
+    COALESCE(s.rating_sum::FLOAT8 / NULLIF(s.amount_rates, 0), 0)::FLOAT8 rating,
+    COALESCE(
+      (COALESCE(s.rating_sum, 0) + $1::INT * g.mean)
+        / NULLIF(COALESCE(s.amount_rates, 0) + $1::INT, 0),
+      0
+    )::FLOAT8 weighted_rating
+  FROM movie m
+  CROSS JOIN global g
+  LEFT JOIN movie_rating_stats s ON s.movie_id = m.id
+) ml
+ORDER BY
+  CASE $2::TEXT
+    WHEN 'weighted' THEN ml.weighted_rating
+    WHEN 'rating' THEN ml.rating
+    WHEN 'votes' THEN ml.amount_rates
+  END DESC,
+  CASE WHEN $2::TEXT = 'new' THEN ml.created_at END DESC,
+  ml.title
 `
 
-func (q *Queries) GetMovieList(ctx context.Context) ([]Movie, error) {
-	rows, err := q.db.Query(ctx, getMovieList)
+type GetMovieListParams struct {
+	MinVotes int32  `json:"min_votes"`
+	Sort     string `json:"sort"`
+}
+
+type GetMovieListRow struct {
+	ID             pgtype.UUID      `json:"id"`
+	Title          string           `json:"title"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	MoviePath      *string          `json:"movie_path"`
+	DurationMs     *int64           `json:"duration_ms"`
+	Width          *int32           `json:"width"`
+	Height         *int32           `json:"height"`
+	VideoCodec     *string          `json:"video_codec"`
+	AudioCodec     *string          `json:"audio_codec"`
+	Bitrate        *int64           `json:"bitrate"`
+	Faststart      *bool            `json:"faststart"`
+	FileMissing    bool             `json:"file_missing"`
+	AmountRates    int64            `json:"amount_rates"`
+	Rating         float64          `json:"rating"`
+	WeightedRating float64          `json:"weighted_rating"`
+}
+
+func (q *Queries) GetMovieList(ctx context.Context, arg GetMovieListParams) ([]GetMovieListRow, error) {
+	rows, err := q.db.Query(ctx, getMovieList, arg.MinVotes, arg.Sort)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Movie
+	var items []GetMovieListRow
 	for rows.Next() {
-		var i Movie
+		var i GetMovieListRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
 			&i.CreatedAt,
 			&i.MoviePath,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.VideoCodec,
+			&i.AudioCodec,
+			&i.Bitrate,
+			&i.Faststart,
+			&i.FileMissing,
+			&i.AmountRates,
+			&i.Rating,
+			&i.WeightedRating,
 		); err != nil {
 			return nil, err
 		}
@@ -155,11 +257,48 @@ func (q *Queries) GetMovieList(ctx context.Context) ([]Movie, error) {
 	return items, nil
 }
 
+const setMovieMediaInfo = `-- name: SetMovieMediaInfo :exec
+UPDATE movie SET
+  duration_ms = $2,
+  width = $3,
+  height = $4,
+  video_codec = $5,
+  audio_codec = $6,
+  bitrate = $7,
+  faststart = $8
+WHERE id = $1
+`
+
+type SetMovieMediaInfoParams struct {
+	ID         pgtype.UUID `json:"id"`
+	DurationMs *int64      `json:"duration_ms"`
+	Width      *int32      `json:"width"`
+	Height     *int32      `json:"height"`
+	VideoCodec *string     `json:"video_codec"`
+	AudioCodec *string     `json:"audio_codec"`
+	Bitrate    *int64      `json:"bitrate"`
+	Faststart  *bool       `json:"faststart"`
+}
+
+func (q *Queries) SetMovieMediaInfo(ctx context.Context, arg SetMovieMediaInfoParams) error {
+	_, err := q.db.Exec(ctx, setMovieMediaInfo,
+		arg.ID,
+		arg.DurationMs,
+		arg.Width,
+		arg.Height,
+		arg.VideoCodec,
+		arg.AudioCodec,
+		arg.Bitrate,
+		arg.Faststart,
+	)
+	return err
+}
+
 const updateMovie = `-- name: UpdateMovie :one
 UPDATE movie SET
   title = COALESCE($2, title)
 WHERE id = $1
-RETURNING id, title, created_at, movie_path
+RETURNING id, title, created_at, movie_path, duration_ms, width, height, video_codec, audio_codec, bitrate, faststart, file_missing
 `
 
 type UpdateMovieParams struct {
@@ -175,6 +314,14 @@ func (q *Queries) UpdateMovie(ctx context.Context, arg UpdateMovieParams) (Movie
 		&i.Title,
 		&i.CreatedAt,
 		&i.MoviePath,
+		&i.DurationMs,
+		&i.Width,
+		&i.Height,
+		&i.VideoCodec,
+		&i.AudioCodec,
+		&i.Bitrate,
+		&i.Faststart,
+		&i.FileMissing,
 	)
 	return i, err
 }
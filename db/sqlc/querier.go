@@ -12,35 +12,116 @@ import (
 
 type Querier interface {
 	AddMoviePath(ctx context.Context, arg AddMoviePathParams) (int64, error)
+	ClaimTranscodeJob(ctx context.Context) (TranscodeJob, error)
+	ClearUserWatchHistory(ctx context.Context, userID pgtype.UUID) (int64, error)
+	CompleteTranscodeJob(ctx context.Context, arg CompleteTranscodeJobParams) (int64, error)
+	CountActiveStreamLeases(ctx context.Context, userID pgtype.UUID) (int64, error)
+	CountMovieReviews(ctx context.Context, movieID pgtype.UUID) (int64, error)
+	CountMovieRootComments(ctx context.Context, movieID pgtype.UUID) (int64, error)
+	CountUserRatingEvents(ctx context.Context, arg CountUserRatingEventsParams) (int64, error)
 	CreateComment(ctx context.Context, arg CreateCommentParams) (Comment, error)
+	CreateCommentReport(ctx context.Context, arg CreateCommentReportParams) (CommentReport, error)
+	CreateDownloadGrant(ctx context.Context, arg CreateDownloadGrantParams) (DownloadGrant, error)
 	CreateFavorite(ctx context.Context, arg CreateFavoriteParams) (Favorite, error)
+	CreateIngestLog(ctx context.Context, arg CreateIngestLogParams) (IngestLog, error)
 	CreateMovie(ctx context.Context, title string) (Movie, error)
+	CreateMovieAsset(ctx context.Context, arg CreateMovieAssetParams) (MovieAsset, error)
 	CreateRating(ctx context.Context, arg CreateRatingParams) (Rating, error)
+	CreateReview(ctx context.Context, arg CreateReviewParams) (Review, error)
+	CreateSubtitle(ctx context.Context, arg CreateSubtitleParams) (CreateSubtitleRow, error)
+	CreateTranscodeJob(ctx context.Context, arg CreateTranscodeJobParams) (TranscodeJob, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (UserDatum, error)
+	CreateUserWarning(ctx context.Context, arg CreateUserWarningParams) (UserWarning, error)
 	DeleteComment(ctx context.Context, id pgtype.UUID) (int64, error)
+	DeleteCommentReaction(ctx context.Context, arg DeleteCommentReactionParams) (int64, error)
+	DeleteExpiredDownloadGrants(ctx context.Context) (int64, error)
+	DeleteExpiredStreamLeases(ctx context.Context) (int64, error)
 	DeleteFavorite(ctx context.Context, arg DeleteFavoriteParams) (int64, error)
 	DeleteMovie(ctx context.Context, id pgtype.UUID) (int64, error)
+	DeleteMovieAsset(ctx context.Context, id pgtype.UUID) (*string, error)
 	DeleteRating(ctx context.Context, arg DeleteRatingParams) (int64, error)
+	DeleteReview(ctx context.Context, id pgtype.UUID) (int64, error)
+	DeleteReviewVote(ctx context.Context, arg DeleteReviewVoteParams) (int64, error)
+	DeleteSubtitle(ctx context.Context, arg DeleteSubtitleParams) (int64, error)
 	DeleteUser(ctx context.Context, id pgtype.UUID) (int64, error)
+	DeleteUserStreamLease(ctx context.Context, arg DeleteUserStreamLeaseParams) (int64, error)
+	DeleteWatchProgress(ctx context.Context, arg DeleteWatchProgressParams) (int64, error)
+	FailTranscodeJob(ctx context.Context, arg FailTranscodeJobParams) (int64, error)
+	GetActiveDownloadGrant(ctx context.Context, id pgtype.UUID) (DownloadGrant, error)
+	GetActiveStreamLease(ctx context.Context, arg GetActiveStreamLeaseParams) (StreamLease, error)
+	GetActiveStreamLeaseList(ctx context.Context, userID pgtype.UUID) ([]GetActiveStreamLeaseListRow, error)
 	GetComment(ctx context.Context, id pgtype.UUID) (Comment, error)
+	GetCommentReactionCountList(ctx context.Context, commentIds []pgtype.UUID) ([]GetCommentReactionCountListRow, error)
+	GetCommentReplyTree(ctx context.Context, rootIds []pgtype.UUID) ([]GetCommentReplyTreeRow, error)
+	GetCommentRevisionList(ctx context.Context, commentID pgtype.UUID) ([]CommentRevision, error)
+	GetContinueWatchingList(ctx context.Context, arg GetContinueWatchingListParams) ([]GetContinueWatchingListRow, error)
+	GetDownloadReport(ctx context.Context, arg GetDownloadReportParams) ([]GetDownloadReportRow, error)
 	GetFavorite(ctx context.Context, arg GetFavoriteParams) (Favorite, error)
+	GetIngestLogList(ctx context.Context, limit int32) ([]IngestLog, error)
+	GetModerationQueue(ctx context.Context, limit int32) ([]GetModerationQueueRow, error)
 	GetMovie(ctx context.Context, id pgtype.UUID) (GetMovieRow, error)
+	GetMovieAsset(ctx context.Context, id pgtype.UUID) (MovieAsset, error)
+	GetMovieAssetFileList(ctx context.Context) ([]GetMovieAssetFileListRow, error)
+	GetMovieAssetList(ctx context.Context, movieID pgtype.UUID) ([]MovieAsset, error)
 	GetMovieByTitle(ctx context.Context, title string) (GetMovieByTitleRow, error)
-	GetMovieCommentList(ctx context.Context, movieID pgtype.UUID) ([]GetMovieCommentListRow, error)
+	GetMovieCommentList(ctx context.Context, arg GetMovieCommentListParams) ([]GetMovieCommentListRow, error)
 	GetMovieFavoriteList(ctx context.Context, movieID pgtype.UUID) ([]pgtype.UUID, error)
-	GetMovieList(ctx context.Context) ([]Movie, error)
+	GetMovieFileList(ctx context.Context) ([]GetMovieFileListRow, error)
+	GetMovieList(ctx context.Context, arg GetMovieListParams) ([]GetMovieListRow, error)
 	GetMovieRatingList(ctx context.Context, userID pgtype.UUID) ([]GetMovieRatingListRow, error)
+	GetMovieRatingStats(ctx context.Context, arg GetMovieRatingStatsParams) (GetMovieRatingStatsRow, error)
+	GetMovieRatingTrend(ctx context.Context, arg GetMovieRatingTrendParams) ([]GetMovieRatingTrendRow, error)
+	GetMovieReviewList(ctx context.Context, arg GetMovieReviewListParams) ([]GetMovieReviewListRow, error)
+	GetMovieSubtitle(ctx context.Context, arg GetMovieSubtitleParams) (Subtitle, error)
+	GetMovieSubtitleList(ctx context.Context, movieID pgtype.UUID) ([]GetMovieSubtitleListRow, error)
+	GetMovieTranscodeJobList(ctx context.Context, movieID pgtype.UUID) ([]TranscodeJob, error)
+	GetOpenCommentReportList(ctx context.Context, commentID pgtype.UUID) ([]CommentReport, error)
+	GetPendingUploadMovieIDList(ctx context.Context) ([]pgtype.UUID, error)
 	GetRating(ctx context.Context, arg GetRatingParams) (Rating, error)
+	GetRecentUserCommentTextList(ctx context.Context, arg GetRecentUserCommentTextListParams) ([]string, error)
+	GetReview(ctx context.Context, id pgtype.UUID) (GetReviewRow, error)
 	GetUser(ctx context.Context, id pgtype.UUID) (UserDatum, error)
 	GetUserByLogin(ctx context.Context, login string) (UserDatum, error)
 	GetUserCommentList(ctx context.Context, userID pgtype.UUID) ([]GetUserCommentListRow, error)
 	GetUserFavoriteList(ctx context.Context, userID pgtype.UUID) ([]pgtype.UUID, error)
 	GetUserList(ctx context.Context) ([]UserDatum, error)
+	GetUserPreference(ctx context.Context, userID pgtype.UUID) (UserPreference, error)
+	GetUserRatingEventList(ctx context.Context, arg GetUserRatingEventListParams) ([]GetUserRatingEventListRow, error)
 	GetUserRatingList(ctx context.Context, userID pgtype.UUID) ([]GetUserRatingListRow, error)
+	GetUserWarningList(ctx context.Context, userID pgtype.UUID) ([]UserWarning, error)
+	GetUserWatchHistory(ctx context.Context, arg GetUserWatchHistoryParams) ([]GetUserWatchHistoryRow, error)
+	GetWatchProgress(ctx context.Context, arg GetWatchProgressParams) (WatchProgress, error)
+	HeartbeatTranscodeJob(ctx context.Context, arg HeartbeatTranscodeJobParams) (int64, error)
+	HoldReportedComment(ctx context.Context, arg HoldReportedCommentParams) (int64, error)
+	LockComment(ctx context.Context, id pgtype.UUID) (int64, error)
+	LockPlaybackSession(ctx context.Context, sessionKey string) error
+	LockUserStreamLeases(ctx context.Context, userID pgtype.UUID) error
+	RecordPlaybackSession(ctx context.Context, arg RecordPlaybackSessionParams) error
+	ReleaseStreamLease(ctx context.Context, arg ReleaseStreamLeaseParams) (int64, error)
+	RemoveUserWatchHistoryEntry(ctx context.Context, arg RemoveUserWatchHistoryEntryParams) (int64, error)
+	ResetStaleTranscodeJobs(ctx context.Context, staleSeconds int32) (int64, error)
+	ResolveCommentReports(ctx context.Context, arg ResolveCommentReportsParams) (int64, error)
+	RetryTranscodeJob(ctx context.Context, arg RetryTranscodeJobParams) (int64, error)
+	SaveStreamLease(ctx context.Context, arg SaveStreamLeaseParams) (StreamLease, error)
+	SaveWatchProgress(ctx context.Context, arg SaveWatchProgressParams) (bool, error)
+	SetCommentReaction(ctx context.Context, arg SetCommentReactionParams) (CommentReaction, error)
+	SetCommentState(ctx context.Context, arg SetCommentStateParams) (Comment, error)
+	SetMovieAssetFile(ctx context.Context, arg SetMovieAssetFileParams) (int64, error)
+	SetMovieAssetFileMissing(ctx context.Context, arg SetMovieAssetFileMissingParams) error
+	SetMovieFileMissing(ctx context.Context, arg SetMovieFileMissingParams) error
+	SetMovieMediaInfo(ctx context.Context, arg SetMovieMediaInfoParams) error
+	SetReviewRating(ctx context.Context, arg SetReviewRatingParams) error
+	SetReviewVote(ctx context.Context, arg SetReviewVoteParams) (ReviewVote, error)
+	SetUserPreference(ctx context.Context, arg SetUserPreferenceParams) (UserPreference, error)
+	SyncMovieMainAsset(ctx context.Context, id pgtype.UUID) error
+	TombstoneComment(ctx context.Context, id pgtype.UUID) (int64, error)
 	UpdateComment(ctx context.Context, arg UpdateCommentParams) (Comment, error)
 	UpdateMovie(ctx context.Context, arg UpdateMovieParams) (Movie, error)
 	UpdateRating(ctx context.Context, arg UpdateRatingParams) (Rating, error)
+	UpdateReview(ctx context.Context, arg UpdateReviewParams) (Review, error)
+	UpdateTranscodeJobProgress(ctx context.Context, arg UpdateTranscodeJobProgressParams) (int64, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (UserDatum, error)
+	UseDownloadGrant(ctx context.Context, arg UseDownloadGrantParams) (DownloadLog, error)
 }
 
 var _ Querier = (*Queries)(nil)
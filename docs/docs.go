@@ -16,6 +16,76 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/asset/{asset_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "video-manager",
+                    "admin"
+                ],
+                "summary": "Delete movie asset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "asset_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/auth/login": {
             "post": {
                 "description": "Get movie by id",
@@ -80,6 +150,7 @@ const docTemplate = `{
                         "OAuth2Password": []
                     }
                 ],
+                "description": "Create top level comment or reply when parent_id is set. Replies deeper than 5 levels\nare attached to the parent of replied comment. Comment caught by content filter is created\npending and waits for moderator, filter_reason tells why",
                 "consumes": [
                     "application/json"
                 ],
@@ -108,6 +179,15 @@ const docTemplate = `{
                             "$ref": "#/definitions/sqlc.Comment"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -140,7 +220,7 @@ const docTemplate = `{
         },
         "/comment/{comment_id}": {
             "get": {
-                "description": "Get comment by id",
+                "description": "Get comment by id. Spoilers are hidden the same way as in movie comments",
                 "consumes": [
                     "application/json"
                 ],
@@ -158,13 +238,32 @@ const docTemplate = `{
                         "name": "comment_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "show",
+                            "hide"
+                        ],
+                        "type": "string",
+                        "description": "Show or hide spoilers, user preference by default",
+                        "name": "spoilers",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/sqlc.Comment"
+                            "$ref": "#/definitions/reqmodel.CommentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -193,6 +292,7 @@ const docTemplate = `{
                         "OAuth2Password": []
                     }
                 ],
+                "description": "Comment having replies, edits or reports is turned into tombstone, so its history stays\nreviewable by moderators. Otherwise it is deleted. Tombstones without replies aren't listed.\nAuthor can't delete comment waiting for moderator",
                 "consumes": [
                     "application/json"
                 ],
@@ -236,6 +336,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -253,6 +362,7 @@ const docTemplate = `{
                         "OAuth2Password": []
                     }
                 ],
+                "description": "Edited text caught by content filter puts comment on hold for moderator, comment hidden by moderator\nstays hidden",
                 "consumes": [
                     "application/json"
                 ],
@@ -318,8 +428,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/favorite": {
+        "/comment/{comment_id}/history": {
             "get": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Get comment with every text it had before edits, including text of deleted comment kept as tombstone",
                 "consumes": [
                     "application/json"
                 ],
@@ -327,22 +443,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favorite"
+                    "comment",
+                    "moderation",
+                    "admin"
                 ],
-                "summary": "Get favorite",
+                "summary": "Get comment edit history",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Movie ID",
-                        "name": "movie_id",
-                        "in": "query",
+                        "description": "Comment ID",
+                        "name": "comment_id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -350,7 +461,25 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/sqlc.Favorite"
+                            "$ref": "#/definitions/reqmodel.CommentHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -372,14 +501,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/comment/{comment_id}/reaction": {
+            "put": {
                 "security": [
                     {
                         "OAuth2Password": []
                     }
                 ],
-                "description": "Add movie to user's favorite",
+                "description": "React to comment with like, dislike or emoji. User has one reaction per comment, new one replaces previous",
                 "consumes": [
                     "application/json"
                 ],
@@ -387,17 +518,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favorite"
+                    "comment"
                 ],
-                "summary": "Add favorite movie",
+                "summary": "Set comment reaction",
                 "parameters": [
                     {
-                        "description": "Comment update data",
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "comment_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reaction",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/reqmodel.FavoriteCreateRequest"
+                            "$ref": "#/definitions/reqmodel.CommentReactionRequest"
                         }
                     }
                 ],
@@ -405,11 +543,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/sqlc.Favorite"
+                            "$ref": "#/definitions/sqlc.CommentReaction"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -443,6 +581,7 @@ const docTemplate = `{
                         "OAuth2Password": []
                     }
                 ],
+                "description": "Remove current user reaction to comment",
                 "consumes": [
                     "application/json"
                 ],
@@ -450,27 +589,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favorite",
-                    "admin"
+                    "comment"
                 ],
-                "summary": "Delete favorite",
+                "summary": "Delete comment reaction",
                 "parameters": [
                     {
-                        "description": "Comment delete data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/reqmodel.FavoriteDeleteRequest"
-                        }
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "comment_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "204": {
                         "description": "No Content"
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -499,13 +635,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/favorite/my": {
-            "delete": {
+        "/comment/{comment_id}/report": {
+            "post": {
                 "security": [
                     {
                         "OAuth2Password": []
                     }
                 ],
+                "description": "Report comment to moderators. Comment reported by several users is hidden until reviewed",
                 "consumes": [
                     "application/json"
                 ],
@@ -513,24 +650,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favorite",
-                    "user"
+                    "comment",
+                    "moderation"
                 ],
-                "summary": "Delete my favorite",
+                "summary": "Report comment",
                 "parameters": [
                     {
-                        "description": "Comment delete data",
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "comment_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Report reason",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/reqmodel.MyFavoriteDeleteRequest"
+                            "$ref": "#/definitions/reqmodel.CommentReportRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.CommentReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -541,6 +697,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -553,9 +718,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/healthcheck": {
+        "/download/report": {
             "get": {
-                "description": "Healtcheck dummy output",
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Get downloads per user and movie for licensing reports. Period is last 30 days by default",
                 "consumes": [
                     "application/json"
                 ],
@@ -563,12 +733,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "healthcheck"
+                    "download",
+                    "admin"
+                ],
+                "summary": "Get download report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Period start date, e.g. 2024-01-01",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Period end date, exclusive",
+                        "name": "to",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Dummy healthcheck",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.DownloadReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -576,37 +767,14 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/movie": {
-            "get": {
-                "description": "Get all movie list",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "movie"
-                ],
-                "summary": "Get movie list",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/reqmodel.MovieListResponse"
-                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -627,44 +795,166 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "security": [
-                    {
-                        "OAuth2Password": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/download/{grant_id}": {
+            "get": {
+                "description": "Serve movie file as attachment. The first GET request uses one download of grant and redirects\nto URL with download token, requests with the token resume already started download or download\nit with parallel ranges. HEAD requests never use grant",
                 "produces": [
-                    "application/json"
+                    "video/mp4"
                 ],
                 "tags": [
-                    "movie",
-                    "admin"
+                    "download"
                 ],
-                "summary": "Create movie",
+                "summary": "Download movie",
                 "parameters": [
                     {
-                        "description": "Movie creation data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Download grant ID",
+                        "name": "grant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Download token from redirect",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte ranges to resume download, e.g. bytes=1048576-",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/reqmodel.MovieCreateRequest"
+                            "type": "array",
+                            "items": {
+                                "type": "integer",
+                                "format": "int32"
+                            }
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer",
+                                "format": "int32"
+                            }
+                        }
+                    },
+                    "302": {
+                        "description": "Found"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "416": {
+                        "description": "Requested Range Not Satisfiable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
+                }
+            },
+            "head": {
+                "description": "Serve movie file as attachment. The first GET request uses one download of grant and redirects\nto URL with download token, requests with the token resume already started download or download\nit with parallel ranges. HEAD requests never use grant",
+                "produces": [
+                    "video/mp4"
+                ],
+                "tags": [
+                    "download"
+                ],
+                "summary": "Download movie",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Download grant ID",
+                        "name": "grant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Download token from redirect",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte ranges to resume download, e.g. bytes=1048576-",
+                        "name": "Range",
+                        "in": "header"
+                    }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/sqlc.Movie"
+                            "type": "array",
+                            "items": {
+                                "type": "integer",
+                                "format": "int32"
+                            }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer",
+                                "format": "int32"
+                            }
+                        }
+                    },
+                    "302": {
+                        "description": "Found"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -681,6 +971,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "416": {
+                        "description": "Requested Range Not Satisfiable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -693,9 +1001,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/movie/{movie_id}": {
+        "/favorite": {
             "get": {
-                "description": "Get movie by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -703,15 +1010,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "movie"
+                    "favorite"
                 ],
-                "summary": "Get movie",
+                "summary": "Get favorite",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Movie ID",
                         "name": "movie_id",
-                        "in": "path",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -719,7 +1033,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/sqlc.Movie"
+                            "$ref": "#/definitions/sqlc.Favorite"
                         }
                     },
                     "404": {
@@ -742,12 +1056,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "OAuth2Password": []
                     }
                 ],
+                "description": "Add movie to user's favorite",
                 "consumes": [
                     "application/json"
                 ],
@@ -755,22 +1070,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "movie",
-                    "admin"
+                    "favorite"
                 ],
-                "summary": "Delete movie",
+                "summary": "Add favorite movie",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Movie ID",
-                        "name": "movie_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Comment update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.FavoriteCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.Favorite"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -801,13 +1120,12 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "delete": {
                 "security": [
                     {
                         "OAuth2Password": []
                     }
                 ],
-                "description": "Update movie",
                 "consumes": [
                     "application/json"
                 ],
@@ -815,34 +1133,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "movie",
+                    "favorite",
                     "admin"
                 ],
-                "summary": "Update movie",
+                "summary": "Delete favorite",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Movie ID",
-                        "name": "movie_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Movie creation data",
+                        "description": "Comment delete data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/reqmodel.MovieUpdateRequest"
+                            "$ref": "#/definitions/reqmodel.FavoriteDeleteRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/sqlc.Movie"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -874,9 +1182,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/movie/{movie_id}/comment": {
-            "get": {
-                "description": "Get comment list for certain movie",
+        "/favorite/my": {
+            "delete": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -884,25 +1196,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "comment",
-                    "movie"
+                    "favorite",
+                    "user"
                 ],
-                "summary": "Get movie comments list",
+                "summary": "Delete my favorite",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Movie ID",
-                        "name": "movie_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Comment delete data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.MyFavoriteDeleteRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/reqmodel.MovieCommentListResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "404": {
                         "description": "Not Found",
@@ -925,9 +1236,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/movie/{movie_id}/favorite": {
+        "/healthcheck": {
             "get": {
-                "description": "Get list users who marked this movie as favorite",
+                "description": "Healtcheck dummy output",
                 "consumes": [
                     "application/json"
                 ],
@@ -935,28 +1246,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favorite",
-                    "movie"
-                ],
-                "summary": "Get movie favorite list",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Movie ID",
-                        "name": "movie_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "healthcheck"
                 ],
+                "summary": "Dummy healthcheck",
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/reqmodel.MovieFavoriteListResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -964,8 +1259,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -976,9 +1271,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/movie/{movie_id}/rating": {
+        "/ingest/log": {
             "get": {
-                "description": "Get users who rated movie",
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Get outcomes of watch folder ingestion, most recent first",
                 "consumes": [
                     "application/json"
                 ],
@@ -986,24 +1286,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "rating",
-                    "movie"
+                    "video-manager",
+                    "admin"
                 ],
-                "summary": "Get movie rating list",
+                "summary": "Get ingest log",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Movie ID",
-                        "name": "movie_id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "Max entries amount, 100 by default, 1000 at most",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/reqmodel.MovieRatingListResponse"
+                            "$ref": "#/definitions/reqmodel.IngestLogListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -1027,14 +1344,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/rating": {
-            "post": {
+        "/media/gc": {
+            "get": {
                 "security": [
                     {
                         "OAuth2Password": []
                     }
                 ],
-                "description": "Rate movie by user",
+                "description": "Get report of the latest media storage reconciliation: orphaned files and movies with missing files",
                 "consumes": [
                     "application/json"
                 ],
@@ -1042,25 +1359,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "rating"
-                ],
-                "summary": "Create rating",
-                "parameters": [
-                    {
-                        "description": "Rate movie data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/reqmodel.RatingCreateRequest"
-                        }
-                    }
+                    "video-manager",
+                    "admin"
                 ],
+                "summary": "Get media GC report",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/sqlc.Rating"
+                            "$ref": "#/definitions/mediagc.Report"
                         }
                     },
                     "401": {
@@ -1092,13 +1399,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "OAuth2Password": []
                     }
                 ],
-                "description": "Delete certain rating",
+                "description": "Reconcile media storage with database now. Orphans older than grace period are deleted\nonly when server runs with MEDIA_GC_DELETE enabled, otherwise they are reported",
                 "consumes": [
                     "application/json"
                 ],
@@ -1106,24 +1413,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "rating",
+                    "video-manager",
                     "admin"
                 ],
-                "summary": "Delete rating",
-                "parameters": [
-                    {
-                        "description": "Delete rating",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                "summary": "Run media GC",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/reqmodel.RatingDeleteRequest"
+                            "$ref": "#/definitions/mediagc.Report"
                         }
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -1143,6 +1442,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1153,14 +1461,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/moderation/comment": {
+            "get": {
                 "security": [
                     {
                         "OAuth2Password": []
                     }
                 ],
-                "description": "Update rating",
+                "description": "Get comments waiting for review and comments with open reports. Held comments go first,\nthen most reported ones",
                 "consumes": [
                     "application/json"
                 ],
@@ -1168,25 +1478,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "rating"
+                    "moderation",
+                    "admin"
                 ],
-                "summary": "Update rating",
+                "summary": "Get moderation queue",
                 "parameters": [
                     {
-                        "description": "Updated rating",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/reqmodel.RatingUpdateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Max comments amount, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/sqlc.Rating"
+                            "$ref": "#/definitions/reqmodel.ModerationQueueResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
@@ -1219,13 +1536,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/rating/my": {
-            "delete": {
+        "/moderation/comment/{comment_id}": {
+            "post": {
                 "security": [
                     {
                         "OAuth2Password": []
                     }
                 ],
+                "description": "Resolve open reports of comment with action. hide removes comment from listings, delete\ndeletes it like its author would, warn hides comment and issues warning to author,\ndismiss makes comment visible again",
                 "consumes": [
                     "application/json"
                 ],
@@ -1233,24 +1551,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "rating",
-                    "user"
+                    "moderation",
+                    "admin"
                 ],
-                "summary": "Delete my rating",
+                "summary": "Moderate comment",
                 "parameters": [
                     {
-                        "description": "Delete rating",
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "comment_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Moderation action",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/reqmodel.RatingMyDeleteRequest"
+                            "$ref": "#/definitions/reqmodel.ModerationActionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.ModerationActionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -1282,41 +1619,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/stream/movie/{movie_id}": {
+        "/moderation/comment/{comment_id}/report": {
             "get": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Get open reports of comment",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "video/mp4"
+                    "application/json"
                 ],
                 "tags": [
-                    "video-manager"
+                    "moderation",
+                    "admin"
                 ],
-                "summary": "Stream movie",
+                "summary": "Get comment reports",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Movie ID",
-                        "name": "movie_id",
+                        "description": "Comment ID",
+                        "name": "comment_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Byte range",
-                        "name": "Range",
-                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "integer",
-                                "format": "int32"
+                            "$ref": "#/definitions/reqmodel.CommentReportListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -1341,49 +1693,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/upload/movie/{movie_id}": {
-            "post": {
+        "/moderation/user/{user_id}/warning": {
+            "get": {
                 "security": [
                     {
                         "OAuth2Password": []
                     }
                 ],
-                "description": "Upload movie data as []bytes stream",
+                "description": "Get warnings issued to certain user",
                 "consumes": [
-                    "application/octet-stream"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "video-manager",
+                    "moderation",
                     "admin"
                 ],
-                "summary": "Upload movie",
+                "summary": "Get user warnings",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Movie ID",
-                        "name": "movie_id",
+                        "description": "User ID",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.UserWarningListResponse"
+                        }
                     },
-                    {
-                        "description": "Streaming Bytes",
-                        "name": "tequest",
-                        "in": "body",
-                        "required": true,
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "integer"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
                     },
                     "404": {
                         "description": "Not Found",
@@ -1406,9 +1767,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
+        "/movie": {
             "get": {
-                "description": "Get user list",
+                "description": "Get all movie list with rating. Weighted rating is Bayesian average pulling movies with few votes to global rating",
                 "consumes": [
                     "application/json"
                 ],
@@ -1416,14 +1777,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "movie"
+                ],
+                "summary": "Get movie list",
+                "parameters": [
+                    {
+                        "enum": [
+                            "title",
+                            "new",
+                            "rating",
+                            "weighted",
+                            "votes"
+                        ],
+                        "type": "string",
+                        "description": "Movie order, title by default",
+                        "name": "sort",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Show user list",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/reqmodel.UserListResponse"
+                            "$ref": "#/definitions/reqmodel.MovieListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -1453,37 +1838,41 @@ const docTemplate = `{
                     }
                 ],
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "movie",
+                    "admin"
                 ],
-                "summary": "Create user",
+                "summary": "Create movie",
                 "parameters": [
                     {
-                        "type": "string",
-                        "name": "login",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "name": "name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "name": "password",
-                        "in": "formData"
+                        "description": "Movie creation data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.MovieCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/sqlc.UserDatum"
+                            "$ref": "#/definitions/sqlc.Movie"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -1507,8 +1896,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/me": {
-            "delete": {
+        "/movie/{movie_id}": {
+            "get": {
+                "description": "Get movie by id with rating and technical metadata of uploaded file",
                 "consumes": [
                     "application/json"
                 ],
@@ -1516,20 +1906,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "movie"
+                ],
+                "summary": "Get movie",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Delete myself",
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/sqlc.GetMovieRow"
                         }
                     },
                     "404": {
@@ -1552,13 +1945,12 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "delete": {
                 "security": [
                     {
                         "OAuth2Password": []
                     }
                 ],
-                "description": "Update user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1566,26 +1958,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "movie",
+                    "admin"
                 ],
-                "summary": "Update user",
+                "summary": "Delete movie",
                 "parameters": [
                     {
-                        "description": "User creation data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/reqmodel.UserUpdateRequest"
-                        }
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/sqlc.UserDatum"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -1615,16 +2003,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/user/my/comment": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "OAuth2Password": []
                     }
                 ],
-                "description": "Get current user comment list",
+                "description": "Update movie",
                 "consumes": [
                     "application/json"
                 ],
@@ -1632,15 +2018,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "comment",
-                    "user"
+                    "movie",
+                    "admin"
+                ],
+                "summary": "Update movie",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Movie creation data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.MovieUpdateRequest"
+                        }
+                    }
                 ],
-                "summary": "Get my user comments list",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/reqmodel.UserCommentListResponse"
+                            "$ref": "#/definitions/sqlc.Movie"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -1664,14 +2077,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/my/favorite": {
+        "/movie/{movie_id}/asset": {
             "get": {
-                "security": [
-                    {
-                        "OAuth2Password": []
-                    }
-                ],
-                "description": "Get current user favorite list",
+                "description": "Get every asset of movie with kind, language, quality and technical metadata",
                 "consumes": [
                     "application/json"
                 ],
@@ -1679,15 +2087,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favorite",
-                    "user"
+                    "video-manager",
+                    "movie"
+                ],
+                "summary": "Get movie assets list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get my user favorite list",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/reqmodel.UserFavoriteListResponse"
+                            "$ref": "#/definitions/reqmodel.MovieAssetListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -1709,16 +2135,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/user/my/rating": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "OAuth2Password": []
                     }
                 ],
-                "description": "Get current user rating list",
+                "description": "Create asset record (main feature, trailer, extra, dubbed version, director's cut), file is uploaded separately",
                 "consumes": [
                     "application/json"
                 ],
@@ -1726,15 +2150,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "rating",
-                    "user"
+                    "video-manager",
+                    "admin"
+                ],
+                "summary": "Create movie asset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Asset description",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.MovieAssetCreateRequest"
+                        }
+                    }
                 ],
-                "summary": "Get my user rating list",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/reqmodel.UserRatingListResponse"
+                            "$ref": "#/definitions/sqlc.MovieAsset"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -1758,9 +2218,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/{user_id}": {
+        "/movie/{movie_id}/comment": {
             "get": {
-                "description": "Get user by id",
+                "description": "Get page of top level comments for certain movie, newest first or by score. Every comment\ncontains its whole reply tree and reaction counts, replies are ordered from oldest or by score.\nDeleted comments having replies are kept as tombstones without author and text, comments\nhidden by moderator or held for review are kept the same way as placeholders. Spoilers are\nhidden by default, authorized user may change default in preferences",
                 "consumes": [
                     "application/json"
                 ],
@@ -1768,23 +2228,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "comment",
+                    "movie"
                 ],
-                "summary": "Show user",
+                "summary": "Get movie comments list",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "Movie ID",
+                        "name": "movie_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Top level comments amount, 20 by default, 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Top level comments to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "new",
+                            "top"
+                        ],
+                        "type": "string",
+                        "description": "Comment order, new by default",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "show",
+                            "hide"
+                        ],
+                        "type": "string",
+                        "description": "Show or hide spoilers, user preference by default",
+                        "name": "spoilers",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/sqlc.UserDatum"
+                            "$ref": "#/definitions/reqmodel.MovieCommentListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -1806,14 +2308,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/movie/{movie_id}/dash": {
+            "post": {
                 "security": [
                     {
                         "OAuth2Password": []
                     }
                 ],
-                "description": "Delete any user as admin",
+                "description": "Rebuild DASH manifest and segments from uploaded movie in background",
                 "consumes": [
                     "application/json"
                 ],
@@ -1821,22 +2325,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user",
+                    "video-manager",
                     "admin"
                 ],
-                "summary": "Delete user",
+                "summary": "Package movie into DASH",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "Movie ID",
+                        "name": "movie_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "202": {
+                        "description": "Accepted"
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -1868,9 +2372,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/{user_id}/comment": {
-            "get": {
-                "description": "Get comment list for certain user",
+        "/movie/{movie_id}/download": {
+            "post": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Issue one-time URL to download movie for offline watching. URL expires in 48 hours,\ninterrupted download can be resumed with Range requests by the same client until then",
                 "consumes": [
                     "application/json"
                 ],
@@ -1878,15 +2387,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "comment",
-                    "user"
+                    "download",
+                    "movie"
                 ],
-                "summary": "Get user comments list",
+                "summary": "Create download grant",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "Movie ID",
+                        "name": "movie_id",
                         "in": "path",
                         "required": true
                     }
@@ -1895,7 +2404,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/reqmodel.UserCommentListResponse"
+                            "$ref": "#/definitions/reqmodel.DownloadGrantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -1919,9 +2437,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/{user_id}/favorite": {
+        "/movie/{movie_id}/favorite": {
             "get": {
-                "description": "Get user's favorite movie list",
+                "description": "Get list users who marked this movie as favorite",
                 "consumes": [
                     "application/json"
                 ],
@@ -1930,14 +2448,14 @@ const docTemplate = `{
                 ],
                 "tags": [
                     "favorite",
-                    "user"
+                    "movie"
                 ],
-                "summary": "Get user favorite list",
+                "summary": "Get movie favorite list",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "Movie ID",
+                        "name": "movie_id",
                         "in": "path",
                         "required": true
                     }
@@ -1946,7 +2464,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/reqmodel.UserFavoriteListResponse"
+                            "$ref": "#/definitions/reqmodel.MovieFavoriteListResponse"
                         }
                     },
                     "404": {
@@ -1970,9 +2488,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/{user_id}/rating": {
+        "/movie/{movie_id}/jobs": {
             "get": {
-                "description": "Get user's rated movies",
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Get transcoding jobs of movie with status and progress",
                 "consumes": [
                     "application/json"
                 ],
@@ -1980,15 +2503,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "rating",
-                    "user"
+                    "video-manager",
+                    "admin"
                 ],
-                "summary": "Get user rating list",
+                "summary": "Get movie processing jobs",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "Movie ID",
+                        "name": "movie_id",
                         "in": "path",
                         "required": true
                     }
@@ -1997,7 +2520,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/reqmodel.UserRatingListResponse"
+                            "$ref": "#/definitions/reqmodel.MovieTranscodeJobListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -2020,209 +2552,5844 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "oauth2.Token": {
-            "type": "object",
-            "properties": {
-                "access_token": {
-                    "description": "AccessToken is the token that authorizes and authenticates\nthe requests.",
-                    "type": "string"
-                },
-                "expires_in": {
-                    "description": "ExpiresIn is the OAuth2 wire format \"expires_in\" field,\nwhich specifies how many seconds later the token expires,\nrelative to an unknown time base approximately around \"now\".\nIt is the application's responsibility to populate\n` + "`" + `Expiry` + "`" + ` from ` + "`" + `ExpiresIn` + "`" + ` when required.",
-                    "type": "integer"
-                },
-                "expiry": {
-                    "description": "Expiry is the optional expiration time of the access token.\n\nIf zero, [TokenSource] implementations will reuse the same\ntoken forever and RefreshToken or equivalent\nmechanisms for that TokenSource will not be used.",
-                    "type": "string"
-                },
-                "refresh_token": {
-                    "description": "RefreshToken is a token that's used by the application\n(as opposed to the user) to refresh the access token\nif it expires.",
-                    "type": "string"
-                },
-                "token_type": {
-                    "description": "TokenType is the type of token.\nThe Type method returns either this or \"Bearer\", the default.",
-                    "type": "string"
-                }
-            }
-        },
-        "pgtype.InfinityModifier": {
-            "type": "integer",
-            "format": "int32",
-            "enum": [
-                1,
-                0,
-                -1
-            ],
-            "x-enum-varnames": [
-                "Infinity",
-                "Finite",
-                "NegativeInfinity"
-            ]
-        },
-        "pgtype.Timestamp": {
-            "type": "object",
-            "properties": {
-                "infinityModifier": {
-                    "$ref": "#/definitions/pgtype.InfinityModifier"
-                },
-                "time": {
-                    "description": "Time zone will be ignored when encoding to PostgreSQL.",
-                    "type": "string"
-                },
-                "valid": {
-                    "type": "boolean"
-                }
-            }
-        },
-        "reqmodel.CommentCreateRequest": {
-            "type": "object",
-            "properties": {
-                "movie_id": {
-                    "type": "string"
-                },
-                "text": {
-                    "type": "string"
-                }
-            }
-        },
-        "reqmodel.CommentUpdateRequest": {
-            "type": "object",
-            "properties": {
-                "text": {
-                    "type": "string"
-                }
-            }
-        },
-        "reqmodel.FavoriteCreateRequest": {
-            "type": "object",
-            "properties": {
-                "movie_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "reqmodel.FavoriteDeleteRequest": {
-            "type": "object",
-            "properties": {
-                "movie_id": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
-                }
-            }
         },
-        "reqmodel.MovieCommentListResponse": {
-            "type": "object",
-            "properties": {
-                "movie_comment_list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/sqlc.GetMovieCommentListRow"
+        "/movie/{movie_id}/progress": {
+            "get": {
+                "security": [
+                    {
+                        "OAuth2Password": []
                     }
-                },
-                "movie_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "reqmodel.MovieCreateRequest": {
-            "type": "object",
-            "properties": {
-                "title": {
-                    "type": "string"
-                }
-            }
-        },
-        "reqmodel.MovieFavoriteListResponse": {
-            "type": "object",
-            "properties": {
-                "favorite_movie_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+                ],
+                "description": "Get saved playback position of current user to resume movie",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watch-progress",
+                    "movie"
+                ],
+                "summary": "Get my watch progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
                     }
-                },
-                "movie_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "reqmodel.MovieListResponse": {
-            "type": "object",
-            "properties": {
-                "movie_list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/sqlc.Movie"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.WatchProgress"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            }
-        },
-        "reqmodel.MovieRatingListResponse": {
-            "type": "object",
-            "properties": {
-                "movie_id": {
-                    "type": "string"
+            },
+            "put": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Report playback position heartbeat. Heartbeats sooner than 10 seconds after the saved one are dropped.\nMovie is marked watched once position is past 90% of duration, later heartbeats keep the mark.\nRewatch heartbeat clears the mark when position is before 90%. Heartbeats setting the mark and rewatch ones are never dropped",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watch-progress",
+                    "movie"
+                ],
+                "summary": "Save watch progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Playback position",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.WatchProgressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.WatchProgressSaveResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Forget playback position, movie disappears from continue watching list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watch-progress",
+                    "movie"
+                ],
+                "summary": "Delete my watch progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/movie/{movie_id}/rating": {
+            "get": {
+                "description": "Get users who rated movie",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating",
+                    "movie"
+                ],
+                "summary": "Get movie rating list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.MovieRatingListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/movie/{movie_id}/rating/stats": {
+            "get": {
+                "description": "Get movie rating histogram, median, standard deviation and Bayesian weighted rating",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating",
+                    "movie"
+                ],
+                "summary": "Get movie rating stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.MovieRatingStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/movie/{movie_id}/rating/trend": {
+            "get": {
+                "description": "Get movie rating changes grouped by day or week. Buckets without changes are skipped,\namount_rates and rating are totals at bucket end, bucket_rating is average of ratings given within bucket.\nRatings given before rating history was kept count in totals only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating",
+                    "movie"
+                ],
+                "summary": "Get movie rating trend",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week"
+                        ],
+                        "type": "string",
+                        "description": "Bucket size, day by default",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days to look back, 90 by default, 3650 at most",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.MovieRatingTrendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/movie/{movie_id}/reviews": {
+            "get": {
+                "description": "Get page of movie reviews with ratings of their authors. Most helpful go first by default,\nhelpfulness is helpful votes minus unhelpful ones",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review",
+                    "movie"
+                ],
+                "summary": "Get movie reviews",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "helpful",
+                            "new"
+                        ],
+                        "type": "string",
+                        "description": "Review order, helpful by default",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Reviews amount, 20 by default, 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Reviews to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.MovieReviewListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/movie/{movie_id}/subtitle": {
+            "get": {
+                "description": "Get subtitle and caption tracks available for movie",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subtitle",
+                    "movie"
+                ],
+                "summary": "Get movie subtitles list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.MovieSubtitleListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Upload SRT or WebVTT track as raw body. SRT is converted to WebVTT, cue timing is validated.\nTrack with the same language and kind is replaced",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subtitle",
+                    "admin"
+                ],
+                "summary": "Upload movie subtitle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "BCP 47 language tag, e.g. en or pt-BR",
+                        "name": "language",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Track label shown by player",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "subtitles (default) or captions",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "description": "SRT or WebVTT file",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.CreateSubtitleRow"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/movie/{movie_id}/subtitle/{subtitle_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subtitle",
+                    "admin"
+                ],
+                "summary": "Delete movie subtitle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subtitle ID",
+                        "name": "subtitle_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/movie/{movie_id}/thumbnails": {
+            "post": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Queue extraction of seek bar preview sprites and thumbnails track. Movie should be processed already",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "video-manager",
+                    "admin"
+                ],
+                "summary": "Generate movie thumbnails",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.TranscodeJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/movie/{movie_id}/thumbnails/thumbnails.vtt": {
+            "get": {
+                "description": "WebVTT track with seek bar previews. Each cue points to frame inside sprite as sprite-001.jpg#xywh=x,y,w,h,\nsprite references carry the same signed query as track",
+                "produces": [
+                    "text/vtt"
+                ],
+                "tags": [
+                    "video-manager",
+                    "movie"
+                ],
+                "summary": "Get movie thumbnails track",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID from signed URL",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix expiration time from signed URL",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Playback session from signed URL",
+                        "name": "session",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature from signed URL",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/movie/{movie_id}/thumbnails/{sprite}": {
+            "get": {
+                "produces": [
+                    "image/jpeg"
+                ],
+                "tags": [
+                    "video-manager",
+                    "movie"
+                ],
+                "summary": "Get movie thumbnails sprite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sprite name from thumbnails track",
+                        "name": "sprite",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID from signed URL",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix expiration time from signed URL",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Playback session from signed URL",
+                        "name": "session",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature from signed URL",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer",
+                                "format": "int32"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/rating": {
+            "post": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Rate movie by user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating"
+                ],
+                "summary": "Create rating",
+                "parameters": [
+                    {
+                        "description": "Rate movie data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.RatingCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.Rating"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Delete certain rating",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating",
+                    "admin"
+                ],
+                "summary": "Delete rating",
+                "parameters": [
+                    {
+                        "description": "Delete rating",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.RatingDeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Update rating",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating"
+                ],
+                "summary": "Update rating",
+                "parameters": [
+                    {
+                        "description": "Updated rating",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.RatingUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.Rating"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/rating/my": {
+            "delete": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating",
+                    "user"
+                ],
+                "summary": "Delete my rating",
+                "parameters": [
+                    {
+                        "description": "Delete rating",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.RatingMyDeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review": {
+            "post": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Create review of movie, one per user and movie. Rating of review replaces user rating of the movie",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Create review",
+                "parameters": [
+                    {
+                        "description": "Review create data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.ReviewCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.GetReviewRow"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/{review_id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Get review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.GetReviewRow"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Delete review of author or by admin, rating it was linked to is kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review",
+                    "admin",
+                    "user"
+                ],
+                "summary": "Delete review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Update own review, omitted fields keep their values. Rating updates user rating of the movie",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Update review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.ReviewUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.GetReviewRow"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/{review_id}/vote": {
+            "put": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Mark review of another user helpful or not, new vote replaces previous one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Vote for review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Vote",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.ReviewVoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.ReviewVote"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Remove current user vote for review",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Delete review vote",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stream/movie/{movie_id}": {
+            "get": {
+                "description": "Progressive stream with RFC 7233 ranges (suffix and multiple ranges) and conditional requests.\nRequest starting playback is recorded into watch history",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "video/mp4",
+                    "multipart/byteranges"
+                ],
+                "tags": [
+                    "video-manager"
+                ],
+                "summary": "Stream movie",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte ranges, e.g. bytes=0-499,-500",
+                        "name": "Range",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag or date, Range is ignored when representation changed",
+                        "name": "If-Range",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag list",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "HTTP date",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID from signed URL",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix expiration time from signed URL",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Playback session from signed URL",
+                        "name": "session",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature from signed URL",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer",
+                                "format": "int32"
+                            }
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer",
+                                "format": "int32"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "416": {
+                        "description": "Requested Range Not Satisfiable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.StreamLimitErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Progressive stream with RFC 7233 ranges (suffix and multiple ranges) and conditional requests.\nRequest starting playback is recorded into watch history",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "video/mp4",
+                    "multipart/byteranges"
+                ],
+                "tags": [
+                    "video-manager"
+                ],
+                "summary": "Stream movie",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte ranges, e.g. bytes=0-499,-500",
+                        "name": "Range",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag or date, Range is ignored when representation changed",
+                        "name": "If-Range",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag list",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "HTTP date",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID from signed URL",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix expiration time from signed URL",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Playback session from signed URL",
+                        "name": "session",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature from signed URL",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer",
+                                "format": "int32"
+                            }
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer",
+                                "format": "int32"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "416": {
+                        "description": "Requested Range Not Satisfiable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.StreamLimitErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stream/movie/{movie_id}/asset/{asset_id}": {
+            "get": {
+                "description": "Progressive stream of asset with the same range and conditional requests support as movie stream.\nUse the same signed query as movie stream URL",
+                "produces": [
+                    "video/mp4",
+                    "multipart/byteranges"
+                ],
+                "tags": [
+                    "video-manager"
+                ],
+                "summary": "Stream movie asset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "asset_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte ranges, e.g. bytes=0-499,-500",
+                        "name": "Range",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID from signed URL",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix expiration time from signed URL",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Playback session from signed URL",
+                        "name": "session",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature from signed URL",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer",
+                                "format": "int32"
+                            }
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer",
+                                "format": "int32"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "416": {
+                        "description": "Requested Range Not Satisfiable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.StreamLimitErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Progressive stream of asset with the same range and conditional requests support as movie stream.\nUse the same signed query as movie stream URL",
+                "produces": [
+                    "video/mp4",
+                    "multipart/byteranges"
+                ],
+                "tags": [
+                    "video-manager"
+                ],
+                "summary": "Stream movie asset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "asset_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte ranges, e.g. bytes=0-499,-500",
+                        "name": "Range",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID from signed URL",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix expiration time from signed URL",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Playback session from signed URL",
+                        "name": "session",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature from signed URL",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer",
+                                "format": "int32"
+                            }
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer",
+                                "format": "int32"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "416": {
+                        "description": "Requested Range Not Satisfiable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.StreamLimitErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stream/movie/{movie_id}/dash/manifest.mpd": {
+            "get": {
+                "description": "Segment references of manifest carry the same signed query",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/dash+xml"
+                ],
+                "tags": [
+                    "video-manager"
+                ],
+                "summary": "Stream movie DASH manifest",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID from signed URL",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix expiration time from signed URL",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Playback session from signed URL",
+                        "name": "session",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature from signed URL",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.StreamLimitErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stream/movie/{movie_id}/dash/{segment}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "video/iso.segment"
+                ],
+                "tags": [
+                    "video-manager"
+                ],
+                "summary": "Stream movie DASH segment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Segment name from manifest",
+                        "name": "segment",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID from signed URL",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix expiration time from signed URL",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Playback session from signed URL",
+                        "name": "session",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature from signed URL",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer",
+                                "format": "int32"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.StreamLimitErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stream/movie/{movie_id}/heartbeat": {
+            "post": {
+                "description": "Renew stream lease of playback session. Stream leases expire in 2 minutes without heartbeats",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "video-manager"
+                ],
+                "summary": "Stream heartbeat",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID from signed URL",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix expiration time from signed URL",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Playback session from signed URL",
+                        "name": "session",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature from signed URL",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device name shown in active streams list, User-Agent by default",
+                        "name": "device",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.StreamLimitErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stream/movie/{movie_id}/lease": {
+            "delete": {
+                "description": "Free concurrent stream slot when playback is stopped",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "video-manager"
+                ],
+                "summary": "Release stream lease",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID from signed URL",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix expiration time from signed URL",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Playback session from signed URL",
+                        "name": "session",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature from signed URL",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stream/movie/{movie_id}/sign": {
+            "post": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Mint time-limited stream URLs bound to user and movie. Players can't send Authorization header,\nso stream endpoints verify URL signature instead. Every URL is new playback session taking\none concurrent stream slot. Response sets device cookie, session is played by one device sending it at a time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "video-manager"
+                ],
+                "summary": "Sign movie stream URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.StreamURLResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stream/movie/{movie_id}/subtitle/{subtitle_id}": {
+            "get": {
+                "description": "Serve WebVTT track for player. Use the same signed query as movie stream URL",
+                "produces": [
+                    "text/vtt"
+                ],
+                "tags": [
+                    "subtitle",
+                    "video-manager"
+                ],
+                "summary": "Stream movie subtitle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subtitle ID",
+                        "name": "subtitle_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID from signed URL",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix expiration time from signed URL",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Playback session from signed URL",
+                        "name": "session",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature from signed URL",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stream/stats": {
+            "get": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Get counters of streamed and throttled bytes since server start",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "video-manager",
+                    "admin"
+                ],
+                "summary": "Get stream throttling stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.StreamThrottleStatsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/upload/asset/{asset_id}": {
+            "post": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Upload faststart mp4 file of asset as []bytes stream. Previous file of asset is replaced",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "video-manager",
+                    "admin"
+                ],
+                "summary": "Upload movie asset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "asset_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Streaming Bytes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.MovieAsset"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/upload/movie/{movie_id}": {
+            "post": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Upload mp4 movie data as []bytes stream. Uploaded file is queued for processing.\nFiles with moov atom at the end are rejected unless remux is requested",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "video-manager",
+                    "admin"
+                ],
+                "summary": "Upload movie",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Accept file with moov atom at the end, processing move it to the beginning",
+                        "name": "remux",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Streaming Bytes",
+                        "name": "tequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.TranscodeJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "description": "Get user list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Show user list",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.UserListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Create user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "login",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "name": "name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "name": "password",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.UserDatum"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me": {
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Delete myself",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Update user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update user",
+                "parameters": [
+                    {
+                        "description": "User creation data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.UserUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.UserDatum"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/my/comment": {
+            "get": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Get current user comment list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comment",
+                    "user"
+                ],
+                "summary": "Get my user comments list",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.UserCommentListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/my/continue-watching": {
+            "get": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Get started but not watched movies of current user, most recent first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watch-progress",
+                    "user"
+                ],
+                "summary": "Get my continue watching list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Max movies amount, 20 by default, 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.ContinueWatchingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/my/favorite": {
+            "get": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Get current user favorite list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "favorite",
+                    "user"
+                ],
+                "summary": "Get my user favorite list",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.UserFavoriteListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/my/history": {
+            "get": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Get playback sessions of current user, most recent first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watch-history",
+                    "user"
+                ],
+                "summary": "Get my watch history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Max entries amount, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.WatchHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Remove every playback session from history. Views stay counted anonymously",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watch-history",
+                    "user"
+                ],
+                "summary": "Clear my watch history",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/my/history/{session_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Remove playback session from history. View stays counted anonymously",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watch-history",
+                    "user"
+                ],
+                "summary": "Delete my watch history entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Playback session ID",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/my/preference": {
+            "get": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get my preferences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.UserPreference"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Update preferences, omitted fields keep their values",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update my preferences",
+                "parameters": [
+                    {
+                        "description": "Preference update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.UserPreferenceUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.UserPreference"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/my/rating": {
+            "get": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Get current user rating list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating",
+                    "user"
+                ],
+                "summary": "Get my user rating list",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.UserRatingListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/my/rating/history": {
+            "get": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Get current user rating creations, changes and deletions, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating",
+                    "user"
+                ],
+                "summary": "Get my rating history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Events amount, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Events to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.UserRatingHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/my/streams": {
+            "get": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Get devices currently streaming with account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "video-manager",
+                    "user"
+                ],
+                "summary": "Get my active streams",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.ActiveStreamListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/my/streams/{lease_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Drop lease of stream on another device, so its slot can be taken",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "video-manager",
+                    "user"
+                ],
+                "summary": "Stop my stream",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stream lease ID",
+                        "name": "lease_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/my/warning": {
+            "get": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Get warnings moderators issued to current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation",
+                    "user"
+                ],
+                "summary": "Get my warnings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.UserWarningListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user_id}": {
+            "get": {
+                "description": "Get user by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Show user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sqlc.UserDatum"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "OAuth2Password": []
+                    }
+                ],
+                "description": "Delete any user as admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user",
+                    "admin"
+                ],
+                "summary": "Delete user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user_id}/comment": {
+            "get": {
+                "description": "Get comment list for certain user. Spoilers are hidden the same way as in movie comments",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comment",
+                    "user"
+                ],
+                "summary": "Get user comments list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "show",
+                            "hide"
+                        ],
+                        "type": "string",
+                        "description": "Show or hide spoilers, user preference by default",
+                        "name": "spoilers",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.UserCommentListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user_id}/favorite": {
+            "get": {
+                "description": "Get user's favorite movie list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "favorite",
+                    "user"
+                ],
+                "summary": "Get user favorite list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.UserFavoriteListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user_id}/rating": {
+            "get": {
+                "description": "Get user's rated movies",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating",
+                    "user"
+                ],
+                "summary": "Get user rating list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.UserRatingListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user_id}/rating/history": {
+            "get": {
+                "description": "Get creations and changes of current user ratings, newest first. Deleted ratings are left out,\nwhole history is available to user only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating",
+                    "user"
+                ],
+                "summary": "Get user rating history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Events amount, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Events to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reqmodel.UserRatingHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "mediagc.MissingFile": {
+            "type": "object",
+            "properties": {
+                "asset_id": {
+                    "description": "Set for movie assets only",
+                    "type": "string"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                }
+            }
+        },
+        "mediagc.Orphan": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "boolean"
+                },
+                "mod_time": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "mediagc.Report": {
+            "type": "object",
+            "properties": {
+                "delete_mode": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "missing_files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mediagc.MissingFile"
+                    }
+                },
+                "orphan_bytes": {
+                    "type": "integer"
+                },
+                "orphans": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mediagc.Orphan"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "oauth2.Token": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "description": "AccessToken is the token that authorizes and authenticates\nthe requests.",
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "ExpiresIn is the OAuth2 wire format \"expires_in\" field,\nwhich specifies how many seconds later the token expires,\nrelative to an unknown time base approximately around \"now\".\nIt is the application's responsibility to populate\n` + "`" + `Expiry` + "`" + ` from ` + "`" + `ExpiresIn` + "`" + ` when required.",
+                    "type": "integer"
+                },
+                "expiry": {
+                    "description": "Expiry is the optional expiration time of the access token.\n\nIf zero, [TokenSource] implementations will reuse the same\ntoken forever and RefreshToken or equivalent\nmechanisms for that TokenSource will not be used.",
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "description": "RefreshToken is a token that's used by the application\n(as opposed to the user) to refresh the access token\nif it expires.",
+                    "type": "string"
+                },
+                "token_type": {
+                    "description": "TokenType is the type of token.\nThe Type method returns either this or \"Bearer\", the default.",
+                    "type": "string"
+                }
+            }
+        },
+        "pgtype.InfinityModifier": {
+            "type": "integer",
+            "format": "int32",
+            "enum": [
+                1,
+                0,
+                -1
+            ],
+            "x-enum-varnames": [
+                "Infinity",
+                "Finite",
+                "NegativeInfinity"
+            ]
+        },
+        "pgtype.Timestamp": {
+            "type": "object",
+            "properties": {
+                "infinityModifier": {
+                    "$ref": "#/definitions/pgtype.InfinityModifier"
+                },
+                "time": {
+                    "description": "Time zone will be ignored when encoding to PostgreSQL.",
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "reqmodel.ActiveStreamListResponse": {
+            "type": "object",
+            "properties": {
+                "stream_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.GetActiveStreamLeaseListRow"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.CommentCreateRequest": {
+            "type": "object",
+            "properties": {
+                "is_spoiler": {
+                    "description": "Whole comment is spoiler",
+                    "type": "boolean"
+                },
+                "language": {
+                    "description": "BCP 47 tag of text language, it picks banned words list. Default list is used when omitted",
+                    "type": "string"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "Set to reply to comment of the same movie",
+                    "type": "string"
+                },
+                "text": {
+                    "description": "Text may mark inline spoilers with double bars: \"the killer is ||the butler||\"",
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.CommentHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "$ref": "#/definitions/sqlc.Comment"
+                },
+                "revision_list": {
+                    "description": "Previous texts, oldest first. Current text is in comment",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.CommentRevision"
+                    }
+                }
+            }
+        },
+        "reqmodel.CommentNode": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "depth": {
+                    "type": "integer"
+                },
+                "edited_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_deleted": {
+                    "type": "boolean"
+                },
+                "is_hidden": {
+                    "description": "Set for placeholder of hidden or held comment",
+                    "type": "boolean"
+                },
+                "is_spoiler": {
+                    "type": "boolean"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "reactions": {
+                    "description": "Amount of every reaction, score is reactions except dislikes minus dislikes",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "replies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/reqmodel.CommentNode"
+                    }
+                },
+                "reply_count": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "segments": {
+                    "description": "Text split into plain and spoiler parts",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/spoiler.Segment"
+                    }
+                },
+                "spoiler_hidden": {
+                    "description": "Set when text of spoiler comment or inline spoilers was removed from response",
+                    "type": "boolean"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.CommentReactionRequest": {
+            "type": "object",
+            "properties": {
+                "reaction": {
+                    "description": "One of like, dislike, love, laugh, wow, sad",
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.CommentReportListResponse": {
+            "type": "object",
+            "properties": {
+                "comment_id": {
+                    "type": "string"
+                },
+                "report_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.CommentReport"
+                    }
+                }
+            }
+        },
+        "reqmodel.CommentReportRequest": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "One of spam, abuse, spoiler, off_topic, other",
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.CommentReportResponse": {
+            "type": "object",
+            "properties": {
+                "on_hold": {
+                    "description": "Set when this report put comment on hold for review",
+                    "type": "boolean"
+                },
+                "report": {
+                    "$ref": "#/definitions/sqlc.CommentReport"
+                }
+            }
+        },
+        "reqmodel.CommentResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "depth": {
+                    "type": "integer"
+                },
+                "edited_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "filter_reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_deleted": {
+                    "type": "boolean"
+                },
+                "is_spoiler": {
+                    "type": "boolean"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "segments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/spoiler.Segment"
+                    }
+                },
+                "spoiler_hidden": {
+                    "type": "boolean"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.CommentUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "is_spoiler": {
+                    "description": "Omitted keeps current flag",
+                    "type": "boolean"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.ContinueWatchingResponse": {
+            "type": "object",
+            "properties": {
+                "movie_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.GetContinueWatchingListRow"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.DownloadGrantResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_downloads": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.DownloadReportResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.GetDownloadReportRow"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.FavoriteCreateRequest": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.FavoriteDeleteRequest": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.IngestLogListResponse": {
+            "type": "object",
+            "properties": {
+                "ingest_log_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.IngestLog"
+                    }
+                }
+            }
+        },
+        "reqmodel.ModerationActionRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "One of hide, delete, warn, dismiss",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Required for warn, shown to warned user",
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.ModerationActionResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "comment_id": {
+                    "type": "string"
+                },
+                "resolved_reports": {
+                    "type": "integer"
+                },
+                "state": {
+                    "description": "Comment state after action, empty when comment was deleted",
+                    "type": "string"
+                },
+                "warning": {
+                    "$ref": "#/definitions/sqlc.UserWarning"
+                }
+            }
+        },
+        "reqmodel.ModerationQueueResponse": {
+            "type": "object",
+            "properties": {
+                "comment_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.GetModerationQueueRow"
+                    }
+                }
+            }
+        },
+        "reqmodel.MovieAssetCreateRequest": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "description": "main, trailer, extra, dub or directors_cut",
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "quality": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.MovieAssetListResponse": {
+            "type": "object",
+            "properties": {
+                "asset_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.MovieAsset"
+                    }
+                },
+                "movie_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.MovieCommentListResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "movie_comment_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/reqmodel.CommentNode"
+                    }
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "sort": {
+                    "type": "string"
+                },
+                "spoilers_hidden": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "description": "Amount of top level comments",
+                    "type": "integer"
+                }
+            }
+        },
+        "reqmodel.MovieCreateRequest": {
+            "type": "object",
+            "properties": {
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.MovieFavoriteListResponse": {
+            "type": "object",
+            "properties": {
+                "favorite_movie_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "movie_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.MovieListResponse": {
+            "type": "object",
+            "properties": {
+                "min_votes": {
+                    "type": "integer"
+                },
+                "movie_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.GetMovieListRow"
+                    }
+                },
+                "sort": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.MovieRatingListResponse": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "string"
+                },
+                "movie_rating_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.GetMovieRatingListRow"
+                    }
+                }
+            }
+        },
+        "reqmodel.MovieRatingStatsResponse": {
+            "type": "object",
+            "properties": {
+                "amount_rates": {
+                    "type": "integer"
+                },
+                "average": {
+                    "type": "number"
+                },
+                "global_rating": {
+                    "type": "number"
+                },
+                "histogram": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/reqmodel.RatingHistogramBucket"
+                    }
+                },
+                "median": {
+                    "type": "number"
+                },
+                "min_votes": {
+                    "type": "integer"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "std_dev": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "description": "Last rating change, null for movie without ratings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/pgtype.Timestamp"
+                        }
+                    ]
+                },
+                "weighted_rating": {
+                    "description": "Bayesian average pulling movies with few votes to global rating",
+                    "type": "number"
+                }
+            }
+        },
+        "reqmodel.MovieRatingTrendResponse": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "since": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "trend": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.GetMovieRatingTrendRow"
+                    }
+                }
+            }
+        },
+        "reqmodel.MovieReviewListResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "movie_review_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.GetMovieReviewListRow"
+                    }
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "sort": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "reqmodel.MovieSubtitleListResponse": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "string"
+                },
+                "subtitle_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.GetMovieSubtitleListRow"
+                    }
+                }
+            }
+        },
+        "reqmodel.MovieTranscodeJobListResponse": {
+            "type": "object",
+            "properties": {
+                "job_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.TranscodeJob"
+                    }
+                },
+                "movie_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.MovieUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.MyFavoriteDeleteRequest": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.RatingCreateRequest": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                }
+            }
+        },
+        "reqmodel.RatingDeleteRequest": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.RatingHistogramBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "integer"
+                }
+            }
+        },
+        "reqmodel.RatingMyDeleteRequest": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.RatingUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                }
+            }
+        },
+        "reqmodel.ReviewCreateRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "description": "From 1 to 10, replaces existing rating of the movie",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.ReviewUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.ReviewVoteRequest": {
+            "type": "object",
+            "properties": {
+                "helpful": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "reqmodel.StreamLimitErrorResponse": {
+            "type": "object",
+            "properties": {
+                "active_streams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.GetActiveStreamLeaseListRow"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "max_streams": {
+                    "type": "integer"
+                }
+            }
+        },
+        "reqmodel.StreamThrottleStatsResponse": {
+            "type": "object",
+            "properties": {
+                "active_connections": {
+                    "type": "integer"
+                },
+                "admin_stream_rate": {
+                    "type": "integer"
+                },
+                "bytes_sent": {
+                    "type": "integer"
+                },
+                "bytes_throttled": {
+                    "type": "integer"
+                },
+                "global_rate": {
+                    "type": "integer"
+                },
+                "stream_rate": {
+                    "type": "integer"
+                },
+                "throttled_wait_ms": {
+                    "type": "integer"
+                }
+            }
+        },
+        "reqmodel.StreamURLResponse": {
+            "type": "object",
+            "properties": {
+                "dash_url": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "thumbnails_url": {
+                    "description": "Seek bar previews, signed like media since frames show movie content",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.UserComment": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "edited_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_spoiler": {
+                    "type": "boolean"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "segments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/spoiler.Segment"
+                    }
+                },
+                "spoiler_hidden": {
+                    "type": "boolean"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.UserCommentListResponse": {
+            "type": "object",
+            "properties": {
+                "spoilers_hidden": {
+                    "type": "boolean"
+                },
+                "user_comment_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/reqmodel.UserComment"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.UserFavoriteListResponse": {
+            "type": "object",
+            "properties": {
+                "favorite_user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.UserListResponse": {
+            "type": "object",
+            "properties": {
+                "user_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.UserDatum"
+                    }
+                }
+            }
+        },
+        "reqmodel.UserPreferenceUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "hide_spoilers": {
+                    "description": "Hide spoilers in comment lists unless requested otherwise",
+                    "type": "boolean"
+                }
+            }
+        },
+        "reqmodel.UserRatingHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "rating_event_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.GetUserRatingEventListRow"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.UserRatingListResponse": {
+            "type": "object",
+            "properties": {
+                "user_id": {
+                    "type": "string"
+                },
+                "user_rating_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.GetUserRatingListRow"
+                    }
+                }
+            }
+        },
+        "reqmodel.UserUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.UserWarningListResponse": {
+            "type": "object",
+            "properties": {
+                "user_id": {
+                    "type": "string"
+                },
+                "warning_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.UserWarning"
+                    }
+                }
+            }
+        },
+        "reqmodel.WatchHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sqlc.GetUserWatchHistoryRow"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "reqmodel.WatchProgressRequest": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "description": "Used only when movie duration isn't known yet",
+                    "type": "integer"
+                },
+                "position_ms": {
+                    "type": "integer"
+                },
+                "rewatch": {
+                    "description": "Movie is started over, watched mark follows position again",
+                    "type": "boolean"
+                }
+            }
+        },
+        "reqmodel.WatchProgressSaveResponse": {
+            "type": "object",
+            "properties": {
+                "saved": {
+                    "description": "False when heartbeat came sooner than throttle period and was dropped",
+                    "type": "boolean"
+                },
+                "watched": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "spoiler.Segment": {
+            "type": "object",
+            "properties": {
+                "hidden": {
+                    "description": "Set when spoiler text was removed from response",
+                    "type": "boolean"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "text or spoiler",
+                    "type": "string"
+                }
+            }
+        },
+        "sqlc.Comment": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "depth": {
+                    "type": "integer"
+                },
+                "edited_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "filter_reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_deleted": {
+                    "type": "boolean"
+                },
+                "is_spoiler": {
+                    "type": "boolean"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "sqlc.CommentReaction": {
+            "type": "object",
+            "properties": {
+                "comment_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "reaction": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "sqlc.CommentReport": {
+            "type": "object",
+            "properties": {
+                "comment_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
                 },
-                "movie_rating_list": {
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "resolution": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "sqlc.CommentRevision": {
+            "type": "object",
+            "properties": {
+                "comment_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "sqlc.CreateSubtitleRow": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "source_format": {
+                    "type": "string"
+                }
+            }
+        },
+        "sqlc.Favorite": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "sqlc.GetActiveStreamLeaseListRow": {
+            "type": "object",
+            "properties": {
+                "device": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "sqlc.GetContinueWatchingListRow": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "position_ms": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                }
+            }
+        },
+        "sqlc.GetDownloadReportRow": {
+            "type": "object",
+            "properties": {
+                "downloads": {
+                    "type": "integer"
+                },
+                "last_downloaded_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "sqlc.GetModerationQueueRow": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "filter_reason": {
+                    "type": "string"
+                },
+                "first_reported_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "open_reports": {
+                    "type": "integer"
+                },
+                "reasons": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/sqlc.GetMovieRatingListRow"
+                        "type": "string"
                     }
+                },
+                "state": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "reqmodel.MovieUpdateRequest": {
+        "sqlc.GetMovieListRow": {
+            "type": "object",
+            "properties": {
+                "amount_rates": {
+                    "type": "integer"
+                },
+                "audio_codec": {
+                    "type": "string"
+                },
+                "bitrate": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "faststart": {
+                    "type": "boolean"
+                },
+                "file_missing": {
+                    "type": "boolean"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "movie_path": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "video_codec": {
+                    "type": "string"
+                },
+                "weighted_rating": {
+                    "type": "number"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "sqlc.GetMovieRatingListRow": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                }
+            }
+        },
+        "sqlc.GetMovieRatingTrendRow": {
             "type": "object",
             "properties": {
+                "amount_rates": {
+                    "type": "integer"
+                },
+                "bucket": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "bucket_rating": {
+                    "type": "number"
+                },
+                "rate_count": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "number"
+                }
+            }
+        },
+        "sqlc.GetMovieReviewListRow": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "helpful_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "unhelpful_count": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "sqlc.GetMovieRow": {
+            "type": "object",
+            "properties": {
+                "amount_rates": {
+                    "type": "integer"
+                },
+                "audio_codec": {
+                    "type": "string"
+                },
+                "bitrate": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "faststart": {
+                    "type": "boolean"
+                },
+                "file_missing": {
+                    "type": "boolean"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "movie_path": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
                 "title": {
                     "type": "string"
+                },
+                "video_codec": {
+                    "type": "string"
+                },
+                "view_count": {
+                    "type": "integer"
+                },
+                "width": {
+                    "type": "integer"
                 }
             }
         },
-        "reqmodel.MyFavoriteDeleteRequest": {
+        "sqlc.GetMovieSubtitleListRow": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
                 "movie_id": {
                     "type": "string"
+                },
+                "source_format": {
+                    "type": "string"
                 }
             }
         },
-        "reqmodel.RatingCreateRequest": {
+        "sqlc.GetReviewRow": {
             "type": "object",
             "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "helpful_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
                 "movie_id": {
                     "type": "string"
                 },
                 "rating": {
                     "type": "integer"
-                }
-            }
-        },
-        "reqmodel.RatingDeleteRequest": {
-            "type": "object",
-            "properties": {
-                "movie_id": {
+                },
+                "title": {
                     "type": "string"
                 },
+                "unhelpful_count": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
                 "user_id": {
                     "type": "string"
                 }
             }
         },
-        "reqmodel.RatingMyDeleteRequest": {
+        "sqlc.GetUserRatingEventListRow": {
             "type": "object",
             "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "id": {
+                    "type": "string"
+                },
                 "movie_id": {
                     "type": "string"
+                },
+                "new_rating": {
+                    "type": "integer"
+                },
+                "old_rating": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
-        "reqmodel.RatingUpdateRequest": {
+        "sqlc.GetUserRatingListRow": {
             "type": "object",
             "properties": {
                 "movie_id": {
@@ -2233,114 +8400,173 @@ const docTemplate = `{
                 }
             }
         },
-        "reqmodel.UserCommentListResponse": {
+        "sqlc.GetUserWatchHistoryRow": {
             "type": "object",
             "properties": {
-                "user_comment_list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/sqlc.GetUserCommentListRow"
-                    }
+                "id": {
+                    "type": "string"
                 },
-                "user_id": {
+                "last_seen_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "movie_id": {
                     "type": "string"
-                }
-            }
-        },
-        "reqmodel.UserFavoriteListResponse": {
-            "type": "object",
-            "properties": {
-                "favorite_user_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
                 },
-                "user_id": {
+                "started_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "reqmodel.UserListResponse": {
-            "type": "object",
-            "properties": {
-                "user_list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/sqlc.UserDatum"
-                    }
-                }
-            }
-        },
-        "reqmodel.UserRatingListResponse": {
+        "sqlc.IngestLog": {
             "type": "object",
             "properties": {
-                "user_id": {
+                "action": {
                     "type": "string"
                 },
-                "user_rating_list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/sqlc.GetUserRatingListRow"
-                    }
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "file_size": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "movie_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
-        "reqmodel.UserUpdateRequest": {
+        "sqlc.Movie": {
             "type": "object",
             "properties": {
-                "login": {
+                "audio_codec": {
                     "type": "string"
                 },
-                "name": {
+                "bitrate": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "faststart": {
+                    "type": "boolean"
+                },
+                "file_missing": {
+                    "type": "boolean"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
                     "type": "string"
                 },
-                "password": {
+                "movie_path": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "video_codec": {
                     "type": "string"
+                },
+                "width": {
+                    "type": "integer"
                 }
             }
         },
-        "sqlc.Comment": {
+        "sqlc.MovieAsset": {
             "type": "object",
             "properties": {
+                "asset_path": {
+                    "type": "string"
+                },
+                "audio_codec": {
+                    "type": "string"
+                },
+                "bitrate": {
+                    "type": "integer"
+                },
                 "created_at": {
                     "$ref": "#/definitions/pgtype.Timestamp"
                 },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "file_missing": {
+                    "type": "boolean"
+                },
+                "height": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "string"
                 },
+                "kind": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
                 "movie_id": {
                     "type": "string"
                 },
-                "text": {
+                "quality": {
                     "type": "string"
                 },
-                "user_id": {
+                "title": {
+                    "type": "string"
+                },
+                "video_codec": {
                     "type": "string"
+                },
+                "width": {
+                    "type": "integer"
                 }
             }
         },
-        "sqlc.Favorite": {
+        "sqlc.Rating": {
             "type": "object",
             "properties": {
                 "movie_id": {
                     "type": "string"
                 },
+                "rating": {
+                    "type": "integer"
+                },
                 "user_id": {
                     "type": "string"
                 }
             }
         },
-        "sqlc.GetMovieCommentListRow": {
+        "sqlc.ReviewVote": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "$ref": "#/definitions/pgtype.Timestamp"
                 },
-                "id": {
-                    "type": "string"
+                "helpful": {
+                    "type": "boolean"
                 },
-                "text": {
+                "review_id": {
                     "type": "string"
                 },
                 "user_id": {
@@ -2348,18 +8574,48 @@ const docTemplate = `{
                 }
             }
         },
-        "sqlc.GetMovieRatingListRow": {
+        "sqlc.TranscodeJob": {
             "type": "object",
             "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
                 "movie_id": {
                     "type": "string"
                 },
-                "rating": {
+                "progress": {
                     "type": "integer"
+                },
+                "run_after": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "stage": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
                 }
             }
         },
-        "sqlc.GetUserCommentListRow": {
+        "sqlc.UserDatum": {
             "type": "object",
             "properties": {
                 "created_at": {
@@ -2368,67 +8624,68 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
-                "movie_id": {
-                    "type": "string"
-                },
-                "text": {
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "sqlc.GetUserRatingListRow": {
+        "sqlc.UserPreference": {
             "type": "object",
             "properties": {
-                "movie_id": {
-                    "type": "string"
+                "hide_spoilers": {
+                    "type": "boolean"
                 },
-                "rating": {
-                    "type": "integer"
+                "updated_at": {
+                    "$ref": "#/definitions/pgtype.Timestamp"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "sqlc.Movie": {
+        "sqlc.UserWarning": {
             "type": "object",
             "properties": {
+                "comment_id": {
+                    "type": "string"
+                },
                 "created_at": {
                     "$ref": "#/definitions/pgtype.Timestamp"
                 },
                 "id": {
                     "type": "string"
                 },
-                "movie_path": {
+                "moderator_id": {
                     "type": "string"
                 },
-                "title": {
+                "reason": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "sqlc.Rating": {
+        "sqlc.WatchProgress": {
             "type": "object",
             "properties": {
+                "duration_ms": {
+                    "type": "integer"
+                },
                 "movie_id": {
                     "type": "string"
                 },
-                "rating": {
+                "position_ms": {
                     "type": "integer"
                 },
-                "user_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "sqlc.UserDatum": {
-            "type": "object",
-            "properties": {
-                "created_at": {
+                "updated_at": {
                     "$ref": "#/definitions/pgtype.Timestamp"
                 },
-                "id": {
+                "user_id": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "watched": {
+                    "type": "boolean"
                 }
             }
         }
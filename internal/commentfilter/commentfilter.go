@@ -0,0 +1,260 @@
+// Package commentfilter check comment text before it is published. Comment caught by any
+// filter isn't rejected, it is held for moderator with the reason filter gave
+package commentfilter
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"movie_backend_go/db/sqlc"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	DefaultMinLength    = 1
+	DefaultMaxLength    = 2000
+	DefaultMaxLinks     = 2
+	DefaultRepeatWindow = 10 * time.Minute
+	DefaultMaxPerWindow = 5
+	DefaultLanguage     = "en"
+	bannedWordsExt      = ".txt"
+)
+
+// Comment is text being checked together with its author
+type Comment struct {
+	UserID pgtype.UUID
+	// Set when existing comment is edited, it isn't compared with itself
+	CommentID pgtype.UUID
+	Text      string
+	// BCP 47 tag picking banned words list, default list is used when it's empty
+	Language string
+}
+
+// Filter return reason to hold comment or empty string to let it through
+type Filter interface {
+	Check(ctx context.Context, comment Comment) (string, error)
+}
+
+// Pipeline run filters in order and stop on the first one holding comment
+type Pipeline struct {
+	Filters []Filter
+}
+
+func (p *Pipeline) Add(filter Filter) {
+	p.Filters = append(p.Filters, filter)
+}
+
+// Check return reason of the first filter holding comment. Nil pipeline lets everything through
+func (p *Pipeline) Check(ctx context.Context, comment Comment) (string, error) {
+	if p == nil {
+		return "", nil
+	}
+	for _, filter := range p.Filters {
+		reason, err := filter.Check(ctx, comment)
+		if err != nil || reason != "" {
+			return reason, err
+		}
+	}
+	return "", nil
+}
+
+type Config struct {
+	// Directory with <language>.txt files, one banned word per line. Empty disables the filter
+	BannedWordsDir string
+	// List checked for comments without language or with language having no list
+	DefaultLanguage string
+	MinLength       int
+	MaxLength       int
+	// Negative disables the filter
+	MaxLinks     int
+	RepeatWindow time.Duration
+	// Comments one user may post within repeat window, 0 disables the limit
+	MaxPerWindow int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		DefaultLanguage: DefaultLanguage,
+		MinLength:       DefaultMinLength,
+		MaxLength:       DefaultMaxLength,
+		MaxLinks:        DefaultMaxLinks,
+		RepeatWindow:    DefaultRepeatWindow,
+		MaxPerWindow:    DefaultMaxPerWindow,
+	}
+}
+
+// New build pipeline of every filter enabled by config, cheap filters go first
+func New(querier sqlc.Querier, cfg Config) (*Pipeline, error) {
+	pipeline := &Pipeline{}
+	pipeline.Add(LengthFilter{Min: cfg.MinLength, Max: cfg.MaxLength})
+	if cfg.MaxLinks >= 0 {
+		pipeline.Add(LinkFilter{Max: cfg.MaxLinks})
+	}
+	if cfg.BannedWordsDir != "" {
+		bannedWords, err := LoadBannedWords(cfg.BannedWordsDir, cfg.DefaultLanguage)
+		if err != nil {
+			return nil, err
+		}
+		pipeline.Add(bannedWords)
+	}
+	if cfg.RepeatWindow > 0 {
+		pipeline.Add(&RepeatFilter{Querier: querier, Window: cfg.RepeatWindow, MaxPerWindow: cfg.MaxPerWindow})
+	}
+	return pipeline, nil
+}
+
+// LengthFilter hold comments shorter or longer than limits in characters, 0 disables limit
+type LengthFilter struct {
+	Min int
+	Max int
+}
+
+func (f LengthFilter) Check(_ context.Context, comment Comment) (string, error) {
+	length := utf8.RuneCountInString(strings.TrimSpace(comment.Text))
+	switch {
+	case f.Min > 0 && length < f.Min:
+		return fmt.Sprintf("text is shorter than %d characters", f.Min), nil
+	case f.Max > 0 && length > f.Max:
+		return fmt.Sprintf("text is longer than %d characters", f.Max), nil
+	}
+	return "", nil
+}
+
+var linkRegexp = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// LinkFilter hold comments with more links than allowed
+type LinkFilter struct {
+	Max int
+}
+
+func (f LinkFilter) Check(_ context.Context, comment Comment) (string, error) {
+	if links := len(linkRegexp.FindAllStringIndex(comment.Text, -1)); links > f.Max {
+		return fmt.Sprintf("text contains %d links, %d allowed", links, f.Max), nil
+	}
+	return "", nil
+}
+
+// BannedWordsFilter hold comments containing any word of comment language list. Words are
+// compared case-insensitively as whole words, so banned word inside longer one is allowed
+type BannedWordsFilter struct {
+	// Lowercase language to set of lowercase words
+	Lists map[string]map[string]bool
+	// Language of list checked when comment language has none
+	Default string
+}
+
+// LoadBannedWords read every <language>.txt of dir. Empty lines and lines starting with # are skipped.
+// List of default language has to be among them
+func LoadBannedWords(dir string, defaultLanguage string) (BannedWordsFilter, error) {
+	filter := BannedWordsFilter{Lists: map[string]map[string]bool{}, Default: strings.ToLower(defaultLanguage)}
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+bannedWordsExt))
+	if err != nil {
+		return filter, err
+	}
+	if len(paths) == 0 {
+		return filter, fmt.Errorf("no banned word lists in %s", dir)
+	}
+
+	for _, path := range paths {
+		language := strings.ToLower(strings.TrimSuffix(filepath.Base(path), bannedWordsExt))
+		words, err := readWordList(path)
+		if err != nil {
+			return filter, fmt.Errorf("read banned words %s: %w", language, err)
+		}
+		filter.Lists[language] = words
+	}
+	if _, ok := filter.Lists[filter.Default]; !ok {
+		return filter, fmt.Errorf("no banned words list of default language %q in %s", defaultLanguage, dir)
+	}
+	return filter, nil
+}
+
+// list pick list of language tag, then of its primary language like "pt" of "pt-BR", then default one
+func (f BannedWordsFilter) list(tag string) (string, map[string]bool) {
+	language := strings.ToLower(tag)
+	if list, ok := f.Lists[language]; ok {
+		return language, list
+	}
+	language, _, _ = strings.Cut(language, "-")
+	if list, ok := f.Lists[language]; ok {
+		return language, list
+	}
+	return f.Default, f.Lists[f.Default]
+}
+
+func readWordList(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	words := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words[word] = true
+	}
+	return words, scanner.Err()
+}
+
+func (f BannedWordsFilter) Check(_ context.Context, comment Comment) (string, error) {
+	words := strings.FieldsFunc(strings.ToLower(comment.Text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	language, list := f.list(comment.Language)
+	for _, word := range words {
+		if list[word] {
+			return fmt.Sprintf("text contains banned word of %s list", language), nil
+		}
+	}
+	return "", nil
+}
+
+// RepeatFilter hold comment repeating recent comment of the same user or exceeding
+// amount of comments user may post within window
+type RepeatFilter struct {
+	Querier      sqlc.Querier
+	Window       time.Duration
+	MaxPerWindow int
+}
+
+func (f *RepeatFilter) Check(ctx context.Context, comment Comment) (string, error) {
+	recentGet := sqlc.GetRecentUserCommentTextListParams{
+		UserID:    comment.UserID,
+		Since:     pgtype.Timestamp{Time: time.Now().Add(-f.Window), Valid: true},
+		ExcludeID: comment.CommentID,
+	}
+	recentList, err := f.Querier.GetRecentUserCommentTextList(ctx, recentGet)
+	if err != nil {
+		return "", fmt.Errorf("get recent user comments: %w", err)
+	}
+
+	text := normalize(comment.Text)
+	for _, recent := range recentList {
+		if normalize(recent) == text {
+			return "text repeats recent comment", nil
+		}
+	}
+	// Edit doesn't post new comment
+	if f.MaxPerWindow > 0 && !comment.CommentID.Valid && len(recentList) >= f.MaxPerWindow {
+		return fmt.Sprintf("more than %d comments in %v", f.MaxPerWindow, f.Window), nil
+	}
+	return "", nil
+}
+
+// normalize make copies differing in case and spacing equal
+func normalize(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
@@ -1,7 +1,33 @@
 package crudl
 
 import (
+	"context"
 	"errors"
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrEmptyDeletion = errors.New("0 values was deleted")
+	ErrEmptyUpdate   = errors.New("0 values was updated")
 )
 
-var ErrEmptyDeletion = errors.New("0 values was deleted")
+// TxBeginner start transaction, pgxpool.Pool satisfies it
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// inTx run fn with queries of one transaction, it is committed when fn succeeds and rolled back otherwise
+func inTx(ctx context.Context, db TxBeginner, fn func(querier *sqlc.Queries) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(sqlc.New(tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -12,7 +12,32 @@ func CreateComment(ctx context.Context, querier sqlc.Querier, commentCreate sqlc
 	return movieComment, err
 }
 
-func DeleteComment(ctx context.Context, querier sqlc.Querier, commentID pgtype.UUID) error {
+// DeleteComment turn comment with replies, edits or reports into tombstone keeping them for moderators,
+// other comment is deleted. Tombstone keeps last text as revision. Comment is locked till commit,
+// so reply can't land between statements
+func DeleteComment(ctx context.Context, db TxBeginner, commentID pgtype.UUID) error {
+	return inTx(ctx, db, func(querier *sqlc.Queries) error {
+		return deleteComment(ctx, querier, commentID)
+	})
+}
+
+func deleteComment(ctx context.Context, querier sqlc.Querier, commentID pgtype.UUID) error {
+	numLocked, err := querier.LockComment(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if numLocked == 0 {
+		return ErrEmptyDeletion
+	}
+
+	numTombstoned, err := querier.TombstoneComment(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if numTombstoned > 0 {
+		return nil
+	}
+
 	numDel, err := querier.DeleteComment(ctx, commentID)
 	if err != nil {
 		return err
@@ -23,11 +48,25 @@ func DeleteComment(ctx context.Context, querier sqlc.Querier, commentID pgtype.U
 	return nil
 }
 
-func GetMovieCommentList(ctx context.Context, querier sqlc.Querier, movieID pgtype.UUID) ([]sqlc.GetMovieCommentListRow, error) {
-	movieCommentList, err := querier.GetMovieCommentList(ctx, movieID)
+func GetMovieCommentList(ctx context.Context, querier sqlc.Querier, commentListGet sqlc.GetMovieCommentListParams) ([]sqlc.GetMovieCommentListRow, error) {
+	movieCommentList, err := querier.GetMovieCommentList(ctx, commentListGet)
 	return movieCommentList, err
 }
 
+func CountMovieRootComments(ctx context.Context, querier sqlc.Querier, movieID pgtype.UUID) (int64, error) {
+	count, err := querier.CountMovieRootComments(ctx, movieID)
+	return count, err
+}
+
+// GetCommentReplyTree get every reply under root comments, parents go before their replies
+func GetCommentReplyTree(ctx context.Context, querier sqlc.Querier, rootIDs []pgtype.UUID) ([]sqlc.GetCommentReplyTreeRow, error) {
+	if len(rootIDs) == 0 {
+		return nil, nil
+	}
+	replyList, err := querier.GetCommentReplyTree(ctx, rootIDs)
+	return replyList, err
+}
+
 func GetUserCommentList(ctx context.Context, querier sqlc.Querier, userID pgtype.UUID) ([]sqlc.GetUserCommentListRow, error) {
 	userCommentList, err := querier.GetUserCommentList(ctx, userID)
 	return userCommentList, err
@@ -42,3 +81,9 @@ func UpdateMovieComment(ctx context.Context, querier sqlc.Querier, movieCommentU
 	movieComment, err := querier.UpdateComment(ctx, movieCommentUpdate)
 	return movieComment, err
 }
+
+// GetCommentRevisionList get texts comment had before edits, oldest first
+func GetCommentRevisionList(ctx context.Context, querier sqlc.Querier, commentID pgtype.UUID) ([]sqlc.CommentRevision, error) {
+	revisionList, err := querier.GetCommentRevisionList(ctx, commentID)
+	return revisionList, err
+}
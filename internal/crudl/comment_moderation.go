@@ -0,0 +1,102 @@
+package crudl
+
+import (
+	"context"
+	"errors"
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrCommentReported = errors.New("comment was reported by user already")
+
+// ReportComment create report and put comment on hold once it collected threshold of open reports.
+// Return whether comment was put on hold by this report
+func ReportComment(ctx context.Context, querier sqlc.Querier, reportCreate sqlc.CreateCommentReportParams, threshold int32) (sqlc.CommentReport, bool, error) {
+	report, err := querier.CreateCommentReport(ctx, reportCreate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return report, false, ErrCommentReported
+	}
+	if err != nil {
+		return report, false, err
+	}
+	if threshold <= 0 {
+		return report, false, nil
+	}
+
+	holdSet := sqlc.HoldReportedCommentParams{CommentID: reportCreate.CommentID, Threshold: threshold}
+	numHeld, err := querier.HoldReportedComment(ctx, holdSet)
+	return report, numHeld > 0, err
+}
+
+func GetModerationQueue(ctx context.Context, querier sqlc.Querier, limit int32) ([]sqlc.GetModerationQueueRow, error) {
+	queue, err := querier.GetModerationQueue(ctx, limit)
+	return queue, err
+}
+
+func GetOpenCommentReportList(ctx context.Context, querier sqlc.Querier, commentID pgtype.UUID) ([]sqlc.CommentReport, error) {
+	reportList, err := querier.GetOpenCommentReportList(ctx, commentID)
+	return reportList, err
+}
+
+func SetCommentState(ctx context.Context, querier sqlc.Querier, stateSet sqlc.SetCommentStateParams) (sqlc.Comment, error) {
+	comment, err := querier.SetCommentState(ctx, stateSet)
+	return comment, err
+}
+
+// CommentModeration is outcome of ModerateComment, comment is empty when it was deleted
+type CommentModeration struct {
+	Comment         sqlc.Comment
+	Warning         *sqlc.UserWarning
+	ResolvedReports int64
+}
+
+// ModerateComment apply moderation action and resolve open reports of comment in one transaction,
+// so reports stay open when action fails. Comment is deleted when state is empty, user is warned
+// when warningCreate is set
+func ModerateComment(ctx context.Context, db TxBeginner, stateSet sqlc.SetCommentStateParams, resolution string, warningCreate *sqlc.CreateUserWarningParams) (CommentModeration, error) {
+	var moderation CommentModeration
+	err := inTx(ctx, db, func(querier *sqlc.Queries) error {
+		var err error
+		if stateSet.State == "" {
+			// Reported comment is kept as tombstone, so resolved reports stay for audit
+			moderation.ResolvedReports, err = ResolveCommentReports(ctx, querier, stateSet.ID, resolution)
+			if err != nil {
+				return err
+			}
+			return deleteComment(ctx, querier, stateSet.ID)
+		}
+
+		if warningCreate != nil {
+			warning, err := CreateUserWarning(ctx, querier, *warningCreate)
+			if err != nil {
+				return err
+			}
+			moderation.Warning = &warning
+		}
+		moderation.Comment, err = SetCommentState(ctx, querier, stateSet)
+		if err != nil {
+			return err
+		}
+		moderation.ResolvedReports, err = ResolveCommentReports(ctx, querier, stateSet.ID, resolution)
+		return err
+	})
+	return moderation, err
+}
+
+func ResolveCommentReports(ctx context.Context, querier sqlc.Querier, commentID pgtype.UUID, resolution string) (int64, error) {
+	reportsResolve := sqlc.ResolveCommentReportsParams{CommentID: commentID, Resolution: &resolution}
+	numResolved, err := querier.ResolveCommentReports(ctx, reportsResolve)
+	return numResolved, err
+}
+
+func CreateUserWarning(ctx context.Context, querier sqlc.Querier, warningCreate sqlc.CreateUserWarningParams) (sqlc.UserWarning, error) {
+	warning, err := querier.CreateUserWarning(ctx, warningCreate)
+	return warning, err
+}
+
+func GetUserWarningList(ctx context.Context, querier sqlc.Querier, userID pgtype.UUID) ([]sqlc.UserWarning, error) {
+	warningList, err := querier.GetUserWarningList(ctx, userID)
+	return warningList, err
+}
@@ -0,0 +1,43 @@
+package crudl
+
+import (
+	"context"
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func SetCommentReaction(ctx context.Context, querier sqlc.Querier, reactionSet sqlc.SetCommentReactionParams) (sqlc.CommentReaction, error) {
+	reaction, err := querier.SetCommentReaction(ctx, reactionSet)
+	return reaction, err
+}
+
+func DeleteCommentReaction(ctx context.Context, querier sqlc.Querier, reactionDelete sqlc.DeleteCommentReactionParams) error {
+	numDel, err := querier.DeleteCommentReaction(ctx, reactionDelete)
+	if err != nil {
+		return err
+	}
+	if numDel == 0 {
+		return ErrEmptyDeletion
+	}
+	return nil
+}
+
+// GetCommentReactionCounts count reactions of every comment, comments without reactions are absent
+func GetCommentReactionCounts(ctx context.Context, querier sqlc.Querier, commentIDs []pgtype.UUID) (map[pgtype.UUID]map[string]int64, error) {
+	counts := map[pgtype.UUID]map[string]int64{}
+	if len(commentIDs) == 0 {
+		return counts, nil
+	}
+	countList, err := querier.GetCommentReactionCountList(ctx, commentIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, count := range countList {
+		if counts[count.CommentID] == nil {
+			counts[count.CommentID] = map[string]int64{}
+		}
+		counts[count.CommentID][count.Reaction] = count.Amount
+	}
+	return counts, nil
+}
@@ -0,0 +1,37 @@
+package crudl
+
+import (
+	"context"
+	"errors"
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrDownloadGrantUsed = errors.New("download grant is expired or used up")
+
+func CreateDownloadGrant(ctx context.Context, querier sqlc.Querier, grantCreate sqlc.CreateDownloadGrantParams) (sqlc.DownloadGrant, error) {
+	grant, err := querier.CreateDownloadGrant(ctx, grantCreate)
+	return grant, err
+}
+
+func GetActiveDownloadGrant(ctx context.Context, querier sqlc.Querier, grantID pgtype.UUID) (sqlc.DownloadGrant, error) {
+	grant, err := querier.GetActiveDownloadGrant(ctx, grantID)
+	return grant, err
+}
+
+// UseDownloadGrant count download of grant and log it for licensing reports. Token of previous
+// download is replaced, so only the new downloader may resume
+func UseDownloadGrant(ctx context.Context, querier sqlc.Querier, grantUse sqlc.UseDownloadGrantParams) (sqlc.DownloadLog, error) {
+	downloadLog, err := querier.UseDownloadGrant(ctx, grantUse)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.DownloadLog{}, ErrDownloadGrantUsed
+	}
+	return downloadLog, err
+}
+
+func GetDownloadReport(ctx context.Context, querier sqlc.Querier, reportGet sqlc.GetDownloadReportParams) ([]sqlc.GetDownloadReportRow, error) {
+	report, err := querier.GetDownloadReport(ctx, reportGet)
+	return report, err
+}
@@ -0,0 +1,11 @@
+package crudl
+
+import (
+	"context"
+	"movie_backend_go/db/sqlc"
+)
+
+func GetIngestLogList(ctx context.Context, querier sqlc.Querier, limit int32) ([]sqlc.IngestLog, error) {
+	ingestLogList, err := querier.GetIngestLogList(ctx, limit)
+	return ingestLogList, err
+}
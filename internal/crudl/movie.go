@@ -32,8 +32,8 @@ func GetMovieByTitle(ctx context.Context, querier sqlc.Querier, movieTitle strin
 	return movie, err
 }
 
-func GetMovieList(ctx context.Context, querier sqlc.Querier) ([]sqlc.Movie, error) {
-	movieList, err := querier.GetMovieList(ctx)
+func GetMovieList(ctx context.Context, querier sqlc.Querier, movieListGet sqlc.GetMovieListParams) ([]sqlc.GetMovieListRow, error) {
+	movieList, err := querier.GetMovieList(ctx, movieListGet)
 	return movieList, err
 }
 
@@ -0,0 +1,40 @@
+package crudl
+
+import (
+	"context"
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func CreateMovieAsset(ctx context.Context, querier sqlc.Querier, assetCreate sqlc.CreateMovieAssetParams) (sqlc.MovieAsset, error) {
+	asset, err := querier.CreateMovieAsset(ctx, assetCreate)
+	return asset, err
+}
+
+// DeleteMovieAsset return path of asset file, nil if file wasn't uploaded
+func DeleteMovieAsset(ctx context.Context, querier sqlc.Querier, assetID pgtype.UUID) (*string, error) {
+	assetPath, err := querier.DeleteMovieAsset(ctx, assetID)
+	return assetPath, err
+}
+
+func GetMovieAsset(ctx context.Context, querier sqlc.Querier, assetID pgtype.UUID) (sqlc.MovieAsset, error) {
+	asset, err := querier.GetMovieAsset(ctx, assetID)
+	return asset, err
+}
+
+func GetMovieAssetList(ctx context.Context, querier sqlc.Querier, movieID pgtype.UUID) ([]sqlc.MovieAsset, error) {
+	assetList, err := querier.GetMovieAssetList(ctx, movieID)
+	return assetList, err
+}
+
+func SetMovieAssetFile(ctx context.Context, querier sqlc.Querier, assetFile sqlc.SetMovieAssetFileParams) error {
+	numUpd, err := querier.SetMovieAssetFile(ctx, assetFile)
+	if err != nil {
+		return err
+	}
+	if numUpd == 0 {
+		return ErrEmptyUpdate
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package crudl
+
+import (
+	"context"
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ClearUserWatchHistory detach every session from user, views stay counted
+func ClearUserWatchHistory(ctx context.Context, querier sqlc.Querier, userID pgtype.UUID) error {
+	_, err := querier.ClearUserWatchHistory(ctx, userID)
+	return err
+}
+
+func GetUserWatchHistory(ctx context.Context, querier sqlc.Querier, historyGet sqlc.GetUserWatchHistoryParams) ([]sqlc.GetUserWatchHistoryRow, error) {
+	history, err := querier.GetUserWatchHistory(ctx, historyGet)
+	return history, err
+}
+
+// RecordPlaybackSession start playback session or touch the one seen within window. Session is locked
+// till commit, so parallel first requests of player record it once
+func RecordPlaybackSession(ctx context.Context, db TxBeginner, sessionRecord sqlc.RecordPlaybackSessionParams) error {
+	return inTx(ctx, db, func(querier *sqlc.Queries) error {
+		if err := querier.LockPlaybackSession(ctx, sessionRecord.SessionKey); err != nil {
+			return err
+		}
+		return querier.RecordPlaybackSession(ctx, sessionRecord)
+	})
+}
+
+// RemoveUserWatchHistoryEntry detach session from user, view stays counted
+func RemoveUserWatchHistoryEntry(ctx context.Context, querier sqlc.Querier, entryRemove sqlc.RemoveUserWatchHistoryEntryParams) error {
+	numUpd, err := querier.RemoveUserWatchHistoryEntry(ctx, entryRemove)
+	if err != nil {
+		return err
+	}
+	if numUpd == 0 {
+		return ErrEmptyDeletion
+	}
+	return nil
+}
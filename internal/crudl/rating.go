@@ -12,6 +12,11 @@ func GetMovieRatingList(ctx context.Context, querier sqlc.Querier, movieID pgtyp
 	return movieRatingList, err
 }
 
+func GetMovieRatingStats(ctx context.Context, querier sqlc.Querier, ratingStatsGet sqlc.GetMovieRatingStatsParams) (sqlc.GetMovieRatingStatsRow, error) {
+	ratingStats, err := querier.GetMovieRatingStats(ctx, ratingStatsGet)
+	return ratingStats, err
+}
+
 func GetUserRatingList(ctx context.Context, querier sqlc.Querier, userID pgtype.UUID) ([]sqlc.GetUserRatingListRow, error) {
 	userRatingList, err := querier.GetUserRatingList(ctx, userID)
 	return userRatingList, err
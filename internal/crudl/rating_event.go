@@ -0,0 +1,24 @@
+package crudl
+
+import (
+	"context"
+	"movie_backend_go/db/sqlc"
+)
+
+// GetMovieRatingTrend return buckets with rating changes only, totals are running ones at bucket end
+func GetMovieRatingTrend(ctx context.Context, querier sqlc.Querier, trendGet sqlc.GetMovieRatingTrendParams) ([]sqlc.GetMovieRatingTrendRow, error) {
+	trend, err := querier.GetMovieRatingTrend(ctx, trendGet)
+	return trend, err
+}
+
+// GetUserRatingEventList return every event of user, or only events of current ratings since their
+// last deletion when CurrentOnly is set
+func GetUserRatingEventList(ctx context.Context, querier sqlc.Querier, eventListGet sqlc.GetUserRatingEventListParams) ([]sqlc.GetUserRatingEventListRow, error) {
+	eventList, err := querier.GetUserRatingEventList(ctx, eventListGet)
+	return eventList, err
+}
+
+func CountUserRatingEvents(ctx context.Context, querier sqlc.Querier, eventsCount sqlc.CountUserRatingEventsParams) (int64, error) {
+	count, err := querier.CountUserRatingEvents(ctx, eventsCount)
+	return count, err
+}
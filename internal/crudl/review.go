@@ -0,0 +1,79 @@
+package crudl
+
+import (
+	"context"
+	"errors"
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrReviewExists = errors.New("user has reviewed movie already")
+
+// CreateReview create review and set rating it is linked to, existing rating is replaced.
+// Rating is kept untouched when user has reviewed movie already
+func CreateReview(ctx context.Context, db TxBeginner, reviewCreate sqlc.CreateReviewParams, rating int16) (sqlc.Review, error) {
+	var review sqlc.Review
+	err := inTx(ctx, db, func(querier *sqlc.Queries) error {
+		var err error
+		review, err = querier.CreateReview(ctx, reviewCreate)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrReviewExists
+		}
+		if err != nil {
+			return err
+		}
+		ratingSet := sqlc.SetReviewRatingParams{UserID: reviewCreate.UserID, MovieID: reviewCreate.MovieID, Rating: rating}
+		return querier.SetReviewRating(ctx, ratingSet)
+	})
+	return review, err
+}
+
+func GetReview(ctx context.Context, querier sqlc.Querier, reviewID pgtype.UUID) (sqlc.GetReviewRow, error) {
+	review, err := querier.GetReview(ctx, reviewID)
+	return review, err
+}
+
+func GetMovieReviewList(ctx context.Context, querier sqlc.Querier, reviewListGet sqlc.GetMovieReviewListParams) ([]sqlc.GetMovieReviewListRow, error) {
+	reviewList, err := querier.GetMovieReviewList(ctx, reviewListGet)
+	return reviewList, err
+}
+
+func CountMovieReviews(ctx context.Context, querier sqlc.Querier, movieID pgtype.UUID) (int64, error) {
+	count, err := querier.CountMovieReviews(ctx, movieID)
+	return count, err
+}
+
+func UpdateReview(ctx context.Context, querier sqlc.Querier, reviewUpdate sqlc.UpdateReviewParams) (sqlc.Review, error) {
+	review, err := querier.UpdateReview(ctx, reviewUpdate)
+	return review, err
+}
+
+// DeleteReview delete review only, rating it was linked to is kept
+func DeleteReview(ctx context.Context, querier sqlc.Querier, reviewID pgtype.UUID) error {
+	numDel, err := querier.DeleteReview(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if numDel == 0 {
+		return ErrEmptyDeletion
+	}
+	return nil
+}
+
+func SetReviewVote(ctx context.Context, querier sqlc.Querier, voteSet sqlc.SetReviewVoteParams) (sqlc.ReviewVote, error) {
+	vote, err := querier.SetReviewVote(ctx, voteSet)
+	return vote, err
+}
+
+func DeleteReviewVote(ctx context.Context, querier sqlc.Querier, voteDelete sqlc.DeleteReviewVoteParams) error {
+	numDel, err := querier.DeleteReviewVote(ctx, voteDelete)
+	if err != nil {
+		return err
+	}
+	if numDel == 0 {
+		return ErrEmptyDeletion
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package crudl
+
+import (
+	"context"
+	"errors"
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrStreamLimitReached = errors.New("concurrent streams limit reached")
+	ErrStreamSessionTaken = errors.New("stream session is played on another device")
+)
+
+// AcquireStreamLease start or renew lease of stream session. Active lease of session is renewed for
+// the device holding it only, ErrStreamSessionTaken is returned for any other. Leases and requests
+// without device id skip the check, lease is bound to the first device id sent. New lease takes slot,
+// ErrStreamLimitReached is returned when user already has maxStreams active ones.
+// Leases of user are locked till commit, so concurrent requests can't take the last slot both
+func AcquireStreamLease(ctx context.Context, db TxBeginner, leaseSave sqlc.SaveStreamLeaseParams, maxStreams int) (sqlc.StreamLease, error) {
+	var lease sqlc.StreamLease
+	err := inTx(ctx, db, func(querier *sqlc.Queries) error {
+		if err := querier.LockUserStreamLeases(ctx, leaseSave.UserID); err != nil {
+			return err
+		}
+
+		leaseGet := sqlc.GetActiveStreamLeaseParams{UserID: leaseSave.UserID, SessionKey: leaseSave.SessionKey}
+		activeLease, err := querier.GetActiveStreamLease(ctx, leaseGet)
+		switch {
+		case err == nil:
+			if activeLease.DeviceID != "" && leaseSave.DeviceID != "" && activeLease.DeviceID != leaseSave.DeviceID {
+				return ErrStreamSessionTaken
+			}
+		case errors.Is(err, pgx.ErrNoRows):
+			activeCount, err := querier.CountActiveStreamLeases(ctx, leaseSave.UserID)
+			if err != nil {
+				return err
+			}
+			if activeCount >= int64(maxStreams) {
+				return ErrStreamLimitReached
+			}
+		default:
+			return err
+		}
+
+		lease, err = querier.SaveStreamLease(ctx, leaseSave)
+		return err
+	})
+	return lease, err
+}
+
+func DeleteExpiredStreamLeases(ctx context.Context, querier sqlc.Querier) (int64, error) {
+	numDel, err := querier.DeleteExpiredStreamLeases(ctx)
+	return numDel, err
+}
+
+func DeleteUserStreamLease(ctx context.Context, querier sqlc.Querier, leaseDelete sqlc.DeleteUserStreamLeaseParams) error {
+	numDel, err := querier.DeleteUserStreamLease(ctx, leaseDelete)
+	if err != nil {
+		return err
+	}
+	if numDel == 0 {
+		return ErrEmptyDeletion
+	}
+	return nil
+}
+
+func GetActiveStreamLeaseList(ctx context.Context, querier sqlc.Querier, userID pgtype.UUID) ([]sqlc.GetActiveStreamLeaseListRow, error) {
+	leaseList, err := querier.GetActiveStreamLeaseList(ctx, userID)
+	return leaseList, err
+}
+
+func ReleaseStreamLease(ctx context.Context, querier sqlc.Querier, leaseRelease sqlc.ReleaseStreamLeaseParams) error {
+	numDel, err := querier.ReleaseStreamLease(ctx, leaseRelease)
+	if err != nil {
+		return err
+	}
+	if numDel == 0 {
+		return ErrEmptyDeletion
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package crudl
+
+import (
+	"context"
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func CreateSubtitle(ctx context.Context, querier sqlc.Querier, subtitleCreate sqlc.CreateSubtitleParams) (sqlc.CreateSubtitleRow, error) {
+	subtitle, err := querier.CreateSubtitle(ctx, subtitleCreate)
+	return subtitle, err
+}
+
+func DeleteSubtitle(ctx context.Context, querier sqlc.Querier, subtitleDelete sqlc.DeleteSubtitleParams) error {
+	numDel, err := querier.DeleteSubtitle(ctx, subtitleDelete)
+	if err != nil {
+		return err
+	}
+	if numDel == 0 {
+		return ErrEmptyDeletion
+	}
+	return nil
+}
+
+func GetMovieSubtitle(ctx context.Context, querier sqlc.Querier, subtitleGet sqlc.GetMovieSubtitleParams) (sqlc.Subtitle, error) {
+	subtitle, err := querier.GetMovieSubtitle(ctx, subtitleGet)
+	return subtitle, err
+}
+
+func GetMovieSubtitleList(ctx context.Context, querier sqlc.Querier, movieID pgtype.UUID) ([]sqlc.GetMovieSubtitleListRow, error) {
+	subtitleList, err := querier.GetMovieSubtitleList(ctx, movieID)
+	return subtitleList, err
+}
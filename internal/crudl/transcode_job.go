@@ -0,0 +1,18 @@
+package crudl
+
+import (
+	"context"
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func CreateTranscodeJob(ctx context.Context, querier sqlc.Querier, jobCreate sqlc.CreateTranscodeJobParams) (sqlc.TranscodeJob, error) {
+	job, err := querier.CreateTranscodeJob(ctx, jobCreate)
+	return job, err
+}
+
+func GetMovieTranscodeJobList(ctx context.Context, querier sqlc.Querier, movieID pgtype.UUID) ([]sqlc.TranscodeJob, error) {
+	jobList, err := querier.GetMovieTranscodeJobList(ctx, movieID)
+	return jobList, err
+}
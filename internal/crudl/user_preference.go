@@ -0,0 +1,24 @@
+package crudl
+
+import (
+	"context"
+	"errors"
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GetUserPreference get user preferences, user who never changed them gets defaults
+func GetUserPreference(ctx context.Context, querier sqlc.Querier, userID pgtype.UUID) (sqlc.UserPreference, error) {
+	preference, err := querier.GetUserPreference(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.UserPreference{UserID: userID, HideSpoilers: true}, nil
+	}
+	return preference, err
+}
+
+func SetUserPreference(ctx context.Context, querier sqlc.Querier, preferenceSet sqlc.SetUserPreferenceParams) (sqlc.UserPreference, error) {
+	preference, err := querier.SetUserPreference(ctx, preferenceSet)
+	return preference, err
+}
@@ -0,0 +1,48 @@
+package crudl
+
+import (
+	"context"
+	"errors"
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func DeleteWatchProgress(ctx context.Context, querier sqlc.Querier, progressDelete sqlc.DeleteWatchProgressParams) error {
+	numDel, err := querier.DeleteWatchProgress(ctx, progressDelete)
+	if err != nil {
+		return err
+	}
+	if numDel == 0 {
+		return ErrEmptyDeletion
+	}
+	return nil
+}
+
+func GetContinueWatchingList(ctx context.Context, querier sqlc.Querier, continueWatchingGet sqlc.GetContinueWatchingListParams) ([]sqlc.GetContinueWatchingListRow, error) {
+	movieList, err := querier.GetContinueWatchingList(ctx, continueWatchingGet)
+	return movieList, err
+}
+
+func GetWatchProgress(ctx context.Context, querier sqlc.Querier, progressGet sqlc.GetWatchProgressParams) (sqlc.WatchProgress, error) {
+	progress, err := querier.GetWatchProgress(ctx, progressGet)
+	return progress, err
+}
+
+// SaveWatchProgress return false when heartbeat was dropped by throttling, and watched mark kept in progress
+func SaveWatchProgress(ctx context.Context, querier sqlc.Querier, progressSave sqlc.SaveWatchProgressParams) (bool, bool, error) {
+	watched, err := querier.SaveWatchProgress(ctx, progressSave)
+	if err == nil {
+		return true, watched, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return false, false, err
+	}
+
+	progressGet := sqlc.GetWatchProgressParams{UserID: progressSave.UserID, MovieID: progressSave.MovieID}
+	progress, err := querier.GetWatchProgress(ctx, progressGet)
+	if err != nil {
+		return false, false, err
+	}
+	return false, progress.Watched, nil
+}
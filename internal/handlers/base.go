@@ -2,22 +2,44 @@ package handlers
 
 import (
 	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/commentfilter"
+	"movie_backend_go/internal/mediagc"
+	"movie_backend_go/pkg/throttle"
 	"time"
 
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 const (
 	OpTimeContext          = 5 * time.Minute
 	CheckHealthTimeContext = 2 * time.Minute
+	DefaultRatingMinVotes  = 10
 )
 
 type HandlerObj struct {
 	QuerierDB *sqlc.Queries
-	Logger    *log.Logger
+	// Operations of several queries run in transaction of pool
+	DBPool *pgxpool.Pool
+	Logger *log.Logger
+	// 0 disables concurrent streams limit
+	MaxConcurrentStreams int
+	// Shared by every stream connection, nil disables throttling
+	StreamLimiter *throttle.Limiter
+	// Bytes per second of single stream connection, 0 is unlimited
+	StreamRate      int64
+	AdminStreamRate int64
+	MediaGC         *mediagc.Collector
+	// Open reports putting comment on hold, 0 disables holding
+	CommentReportThreshold int
+	// Comments caught by filter are held for review, nil lets everything through
+	CommentFilter *commentfilter.Pipeline
+	// Votes weighted rating trusts as much as global rating
+	RatingMinVotes int
 }
 
 func writeResponseBody(rw http.ResponseWriter, responseObj any, responseObjName string) {
@@ -30,3 +52,18 @@ func writeResponseBody(rw http.ResponseWriter, responseObj any, responseObjName
 		return
 	}
 }
+
+// writeResponseBodyStatus encode body before status is written, so encoding failure still answers 500
+func writeResponseBodyStatus(rw http.ResponseWriter, responseObj any, responseObjName string, status int) {
+	body, err := json.Marshal(responseObj)
+	if err != nil {
+		log.Println(err)
+		http.Error(rw, fmt.Sprintf("can't send %s data", responseObjName), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	if _, err := rw.Write(append(body, '\n')); err != nil {
+		log.Println(err)
+	}
+}
@@ -1,25 +1,136 @@
 package handlers
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/commentfilter"
 	"movie_backend_go/internal/crudl"
 	"movie_backend_go/internal/handlers/reqmodel"
 	"movie_backend_go/pkg/auth"
+	"movie_backend_go/pkg/spoiler"
+	"movie_backend_go/pkg/subtitle"
 	"net/http"
+	"slices"
+	"strconv"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const (
+	// Replies to comment on the deepest level are attached to its parent
+	MaxCommentDepth    = 5
+	commentPageLimit   = 20
+	commentPageMaxSize = 100
+
+	CommentSortNew = "new"
+	CommentSortTop = "top"
+
+	// Anonymous users don't see spoilers until they ask
+	DefaultHideSpoilers = true
+)
+
+// commentSegments split comment text into segments. Hidden spoilers leave only their place in segments,
+// returned flag tells whether anything was hidden
+func commentSegments(text string, isSpoiler bool, hideSpoilers bool) ([]spoiler.Segment, string, bool) {
+	segments := spoiler.Parse(text)
+	switch {
+	case hideSpoilers && isSpoiler:
+		return []spoiler.Segment{}, "", true
+	case hideSpoilers && spoiler.Contains(text):
+		segments, text = spoiler.Hide(segments)
+		return segments, text, true
+	}
+	return segments, text, false
+}
+
+// commentNode convert comment row into tree node. Tombstone and placeholder of hidden or held
+// comment keep only their place in thread
+func commentNode(row sqlc.GetCommentReplyTreeRow, hideSpoilers bool) *reqmodel.CommentNode {
+	node := &reqmodel.CommentNode{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		ParentID:   row.ParentID,
+		Depth:      row.Depth,
+		IsSpoiler:  row.IsSpoiler,
+		IsDeleted:  row.IsDeleted,
+		IsHidden:   row.State != CommentStateVisible,
+		CreatedAt:  row.CreatedAt,
+		EditedAt:   row.EditedAt,
+		ReplyCount: row.ReplyCount,
+		Reactions:  map[string]int64{},
+		Score:      row.Score,
+		Replies:    []*reqmodel.CommentNode{},
+	}
+	if node.IsDeleted || node.IsHidden {
+		node.UserID = pgtype.UUID{}
+		node.IsSpoiler = false
+		node.Segments = []spoiler.Segment{}
+		return node
+	}
+	node.Segments, node.Text, node.SpoilerHidden = commentSegments(row.Text, row.IsSpoiler, hideSpoilers)
+	return node
+}
+
+// filterComment run comment through content filter and return state it should be saved with.
+// Comment is held for review when filter fails as well
+func (ho *HandlerObj) filterComment(ctx context.Context, comment commentfilter.Comment) (string, *string) {
+	reason, err := ho.CommentFilter.Check(ctx, comment)
+	if err != nil {
+		ho.Logger.Printf("proceed filtering comment: %v", err)
+		reason = "content filter is unavailable"
+	}
+	if reason == "" {
+		return CommentStateVisible, nil
+	}
+	return CommentStatePending, &reason
+}
+
+// hideSpoilers resolve whether spoilers are hidden from query, then from preference of authorized user.
+// Return false on wrong query value
+func (ho *HandlerObj) hideSpoilers(ctx context.Context, r *http.Request) (bool, bool) {
+	switch r.URL.Query().Get("spoilers") {
+	case "show":
+		return false, true
+	case "hide":
+		return true, true
+	case "":
+	default:
+		return false, false
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		return DefaultHideSpoilers, true
+	}
+	preference, err := crudl.GetUserPreference(ctx, ho.QuerierDB, userTokenData.UserID)
+	if err != nil {
+		ho.Logger.Printf("proceed getting user preference: %v", err)
+		return DefaultHideSpoilers, true
+	}
+	return preference.HideSpoilers, true
+}
+
 // @Summary 		Get movie comments list
-// @Description	Get comment list for certain movie
+// @Description	Get page of top level comments for certain movie, newest first or by score. Every comment
+// @Description	contains its whole reply tree and reaction counts, replies are ordered from oldest or by score.
+// @Description	Deleted comments having replies are kept as tombstones without author and text, comments
+// @Description	hidden by moderator or held for review are kept the same way as placeholders. Spoilers are
+// @Description	hidden by default, authorized user may change default in preferences
 // @Tags        comment, movie
 // @Accept      json
 // @Produce     json
 // @Param       movie_id   path		string	true	"Movie ID"
+// @Param       limit   	query	int 	false  "Top level comments amount, 20 by default, 100 at most"
+// @Param       offset   	query	int 	false  "Top level comments to skip"
+// @Param       sort   	query	string 	false  "Comment order, new by default" Enums(new, top)
+// @Param       spoilers   	query	string 	false  "Show or hide spoilers, user preference by default" Enums(show, hide)
 // @Success     200		{object}	reqmodel.MovieCommentListResponse
+// @Failure     400  	{object}  map[string]string
 // @Failure     404  	{object}  map[string]string
 // @Failure     500  	{object}  map[string]string
 // @Router      /movie/{movie_id}/comment [get]
@@ -34,13 +145,115 @@ func (ho *HandlerObj) GetMovieCommentListHandler(rw http.ResponseWriter, r *http
 		return
 	}
 
-	movieCommentList, err := crudl.GetMovieCommentList(ctx, ho.QuerierDB, movieID)
+	var err error
+	limit := commentPageLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			http.Error(rw, "limit should be positive number", http.StatusBadRequest)
+			return
+		}
+		limit = min(limit, commentPageMaxSize)
+	}
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			http.Error(rw, "offset should be non negative number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	sort := r.URL.Query().Get("sort")
+	if sort == "" {
+		sort = CommentSortNew
+	}
+	if sort != CommentSortNew && sort != CommentSortTop {
+		http.Error(rw, "sort should be new or top", http.StatusBadRequest)
+		return
+	}
+
+	hideSpoilers, ok := ho.hideSpoilers(ctx, r)
+	if !ok {
+		http.Error(rw, "spoilers should be show or hide", http.StatusBadRequest)
+		return
+	}
+
+	commentListGet := sqlc.GetMovieCommentListParams{
+		MovieID:    movieID,
+		SortTop:    sort == CommentSortTop,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	}
+	rootList, err := crudl.GetMovieCommentList(ctx, ho.QuerierDB, commentListGet)
 	if err != nil {
 		ho.Logger.Printf("proceed getting movie comment list: %v", err)
 		http.Error(rw, "Can't get movie comment list", http.StatusNotFound)
 		return
 	}
-	movieCommentListResp := reqmodel.MovieCommentListResponse{MovieID: movieID, MovieCommentList: movieCommentList}
+	total, err := crudl.CountMovieRootComments(ctx, ho.QuerierDB, movieID)
+	if err != nil {
+		ho.Logger.Printf("proceed counting movie comments: %v", err)
+		http.Error(rw, "Can't get movie comment list", http.StatusNotFound)
+		return
+	}
+
+	roots := make([]*reqmodel.CommentNode, 0, len(rootList))
+	nodes := make(map[pgtype.UUID]*reqmodel.CommentNode, len(rootList))
+	rootIDs := make([]pgtype.UUID, 0, len(rootList))
+	for _, root := range rootList {
+		node := commentNode(sqlc.GetCommentReplyTreeRow(root), hideSpoilers)
+		roots = append(roots, node)
+		nodes[node.ID] = node
+		rootIDs = append(rootIDs, node.ID)
+	}
+
+	replyList, err := crudl.GetCommentReplyTree(ctx, ho.QuerierDB, rootIDs)
+	if err != nil {
+		ho.Logger.Printf("proceed getting comment replies: %v", err)
+		http.Error(rw, "Can't get movie comment list", http.StatusNotFound)
+		return
+	}
+	// Replies are ordered by creation, so parent is always placed before its replies
+	for _, reply := range replyList {
+		node := commentNode(reply, hideSpoilers)
+		nodes[node.ID] = node
+		if parent, ok := nodes[node.ParentID]; ok {
+			parent.Replies = append(parent.Replies, node)
+		}
+	}
+
+	commentIDs := make([]pgtype.UUID, 0, len(nodes))
+	for id := range nodes {
+		commentIDs = append(commentIDs, id)
+	}
+	reactionCounts, err := crudl.GetCommentReactionCounts(ctx, ho.QuerierDB, commentIDs)
+	if err != nil {
+		ho.Logger.Printf("proceed getting comment reactions: %v", err)
+		http.Error(rw, "Can't get movie comment list", http.StatusNotFound)
+		return
+	}
+	for id, counts := range reactionCounts {
+		nodes[id].Reactions = counts
+	}
+	if sort == CommentSortTop {
+		for _, node := range nodes {
+			// Stable sort keeps older reply first among equally scored
+			slices.SortStableFunc(node.Replies, func(a, b *reqmodel.CommentNode) int {
+				return cmp.Compare(b.Score, a.Score)
+			})
+		}
+	}
+
+	movieCommentListResp := reqmodel.MovieCommentListResponse{
+		MovieID:          movieID,
+		Total:            total,
+		Limit:            limit,
+		Offset:           offset,
+		Sort:             sort,
+		SpoilersHidden:   hideSpoilers,
+		MovieCommentList: roots,
+	}
 	writeResponseBody(rw, movieCommentListResp, "movie comment list")
 }
 
@@ -71,17 +284,23 @@ func (ho *HandlerObj) GetMyUserCommentListHandler(rw http.ResponseWriter, r *htt
 		http.Error(rw, "Can't get user comment list", http.StatusNotFound)
 		return
 	}
-	userCommentListResp := reqmodel.UserCommentListResponse{UserID: userTokenData.UserID, UserCommentList: userCommentList}
+	// Author always sees own spoilers
+	userCommentListResp := reqmodel.UserCommentListResponse{
+		UserID:          userTokenData.UserID,
+		UserCommentList: userComments(userCommentList, false),
+	}
 	writeResponseBody(rw, userCommentListResp, "user comment list")
 }
 
 // @Summary 		Get user comments list
-// @Description	Get comment list for certain user
+// @Description	Get comment list for certain user. Spoilers are hidden the same way as in movie comments
 // @Tags        comment, user
 // @Accept      json
 // @Produce     json
 // @Param       user_id   path		string	true	"User ID"
+// @Param       spoilers   	query	string 	false  "Show or hide spoilers, user preference by default" Enums(show, hide)
 // @Success     200		{object}	reqmodel.UserCommentListResponse
+// @Failure     400  	{object}  map[string]string
 // @Failure     404  	{object}  map[string]string
 // @Failure     500  	{object}  map[string]string
 // @Router      /user/{user_id}/comment [get]
@@ -96,23 +315,45 @@ func (ho *HandlerObj) GetUserCommentListHandler(rw http.ResponseWriter, r *http.
 		return
 	}
 
+	hideSpoilers, ok := ho.hideSpoilers(ctx, r)
+	if !ok {
+		http.Error(rw, "spoilers should be show or hide", http.StatusBadRequest)
+		return
+	}
+
 	userCommentList, err := crudl.GetUserCommentList(ctx, ho.QuerierDB, userID)
 	if err != nil {
 		ho.Logger.Printf("proceed getting user comment list: %v", err)
 		http.Error(rw, "Can't get user comment list", http.StatusNotFound)
 		return
 	}
-	userCommentListResp := reqmodel.UserCommentListResponse{UserID: userID, UserCommentList: userCommentList}
+	userCommentListResp := reqmodel.UserCommentListResponse{
+		UserID:          userID,
+		SpoilersHidden:  hideSpoilers,
+		UserCommentList: userComments(userCommentList, hideSpoilers),
+	}
 	writeResponseBody(rw, userCommentListResp, "user comment list")
 }
 
+func userComments(userCommentList []sqlc.GetUserCommentListRow, hideSpoilers bool) []reqmodel.UserComment {
+	userComments := make([]reqmodel.UserComment, 0, len(userCommentList))
+	for _, row := range userCommentList {
+		userComment := reqmodel.UserComment{GetUserCommentListRow: row}
+		userComment.Segments, userComment.Text, userComment.SpoilerHidden = commentSegments(row.Text, row.IsSpoiler, hideSpoilers)
+		userComments = append(userComments, userComment)
+	}
+	return userComments
+}
+
 // @Summary 		Get comment
-// @Description	Get comment by id
+// @Description	Get comment by id. Spoilers are hidden the same way as in movie comments
 // @Tags        comment
 // @Accept      json
 // @Produce     json
 // @Param       comment_id   path		string	true	"Comment ID"
-// @Success     200		{object}	sqlc.Comment
+// @Param       spoilers   	query	string 	false  "Show or hide spoilers, user preference by default" Enums(show, hide)
+// @Success     200		{object}	reqmodel.CommentResponse
+// @Failure     400  	{object}  map[string]string
 // @Failure     404  	{object}  map[string]string
 // @Failure     500  	{object}  map[string]string
 // @Router      /comment/{comment_id} [get]
@@ -127,22 +368,39 @@ func (ho *HandlerObj) GetCommentHandler(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	hideSpoilers, ok := ho.hideSpoilers(ctx, r)
+	if !ok {
+		http.Error(rw, "spoilers should be show or hide", http.StatusBadRequest)
+		return
+	}
+
 	comment, err := crudl.GetComment(ctx, ho.QuerierDB, commentID)
 	if err != nil {
 		ho.Logger.Printf("proceed getting comment comment list: %v", err)
 		http.Error(rw, "Can't get user comment list", http.StatusNotFound)
 		return
 	}
-	writeResponseBody(rw, comment, "user comment list")
+	// Hidden and held comments are seen by moderators only
+	if comment.State != CommentStateVisible {
+		http.Error(rw, "comment not found", http.StatusNotFound)
+		return
+	}
+	commentResp := reqmodel.CommentResponse{Comment: comment}
+	commentResp.Segments, commentResp.Text, commentResp.SpoilerHidden = commentSegments(comment.Text, comment.IsSpoiler, hideSpoilers)
+	writeResponseBody(rw, commentResp, "user comment list")
 }
 
 // @Summary 		Create comments
+// @Description	Create top level comment or reply when parent_id is set. Replies deeper than 5 levels
+// @Description	are attached to the parent of replied comment. Comment caught by content filter is created
+// @Description	pending and waits for moderator, filter_reason tells why
 // @Tags        comment
 // @Accept      json
 // @Produce     json
 // @Security 		OAuth2Password
 // @Param       request   body	reqmodel.CommentCreateRequest	true	"Comment create data"
 // @Success     200		{object}	sqlc.Comment
+// @Failure     400  	{object}  map[string]string
 // @Failure     401  	{object}  map[string]string
 // @Failure     404  	{object}  map[string]string
 // @Failure     500  	{object}  map[string]string
@@ -169,7 +427,50 @@ func (ho *HandlerObj) CreateCommentHandler(rw http.ResponseWriter, r *http.Reque
 	}
 
 	// Verify
-	commentCreate := sqlc.CreateCommentParams{UserID: userTokenData.UserID, MovieID: commentReq.MovieID, Text: commentReq.Text}
+	commentCreate := sqlc.CreateCommentParams{
+		UserID:    userTokenData.UserID,
+		MovieID:   commentReq.MovieID,
+		Text:      commentReq.Text,
+		IsSpoiler: commentReq.IsSpoiler,
+		Language:  commentReq.Language,
+	}
+	if commentReq.Language != nil && !subtitle.ValidLanguage(*commentReq.Language) {
+		http.Error(rw, "language should be BCP 47 tag, e.g. en or pt-BR", http.StatusBadRequest)
+		return
+	}
+	if commentReq.ParentID.Valid {
+		parent, err := crudl.GetComment(ctx, ho.QuerierDB, commentReq.ParentID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(rw, "parent comment not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			ho.Logger.Printf("searching parent comment by id - %v: %v", commentReq.ParentID, err)
+			http.Error(rw, "Can't create movie comment", http.StatusInternalServerError)
+			return
+		}
+		if parent.MovieID != commentReq.MovieID {
+			http.Error(rw, "parent comment belongs to another movie", http.StatusBadRequest)
+			return
+		}
+		if parent.IsDeleted || parent.State != CommentStateVisible {
+			http.Error(rw, "parent comment was deleted or hidden", http.StatusBadRequest)
+			return
+		}
+		commentCreate.ParentID = parent.ID
+		commentCreate.Depth = parent.Depth + 1
+		// Thread stays readable on narrow screens, too deep reply becomes sibling of replied comment
+		if commentCreate.Depth > MaxCommentDepth {
+			commentCreate.ParentID = parent.ParentID
+			commentCreate.Depth = parent.Depth
+		}
+	}
+
+	filterComment := commentfilter.Comment{UserID: userTokenData.UserID, Text: commentReq.Text}
+	if commentReq.Language != nil {
+		filterComment.Language = *commentReq.Language
+	}
+	commentCreate.State, commentCreate.FilterReason = ho.filterComment(ctx, filterComment)
 
 	comment, err := crudl.CreateComment(ctx, ho.QuerierDB, commentCreate)
 	if err != nil {
@@ -181,6 +482,8 @@ func (ho *HandlerObj) CreateCommentHandler(rw http.ResponseWriter, r *http.Reque
 }
 
 // @Summary 		Update comments
+// @Description	Edited text caught by content filter puts comment on hold for moderator, comment hidden by moderator
+// @Description	stays hidden
 // @Tags        comment
 // @Accept      json
 // @Produce     json
@@ -223,17 +526,26 @@ func (ho *HandlerObj) UpdateCommentHandler(rw http.ResponseWriter, r *http.Reque
 	// Verify
 	commentData, err := crudl.GetComment(ctx, ho.QuerierDB, commentID)
 	if err != nil {
-		ho.Logger.Fatalf("searching comment by id - %s: %v", commentID, err)
+		ho.Logger.Printf("searching comment by id - %v: %v", commentID, err)
 		http.Error(rw, "Can't find comment with current id", http.StatusBadRequest)
 		return
 	}
+	if commentData.IsDeleted {
+		http.Error(rw, "Deleted comment can't be updated", http.StatusBadRequest)
+		return
+	}
 	if commentData.UserID != userTokenData.UserID {
 		ho.Logger.Println("Unauthorized user")
 		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
 		return
 	}
 
-	commentUpdate := sqlc.UpdateCommentParams{ID: commentID, Text: commentReq.Text}
+	commentUpdate := sqlc.UpdateCommentParams{ID: commentID, Text: commentReq.Text, IsSpoiler: commentReq.IsSpoiler}
+	filterComment := commentfilter.Comment{UserID: userTokenData.UserID, CommentID: commentID, Text: commentReq.Text}
+	if commentData.Language != nil {
+		filterComment.Language = *commentData.Language
+	}
+	_, commentUpdate.FilterReason = ho.filterComment(ctx, filterComment)
 	comment, err := crudl.UpdateMovieComment(ctx, ho.QuerierDB, commentUpdate)
 	if err != nil {
 		ho.Logger.Printf("proceed update comment: %v", err)
@@ -244,6 +556,9 @@ func (ho *HandlerObj) UpdateCommentHandler(rw http.ResponseWriter, r *http.Reque
 }
 
 // @Summary      Delete comment
+// @Description  Comment having replies, edits or reports is turned into tombstone, so its history stays
+// @Description  reviewable by moderators. Otherwise it is deleted. Tombstones without replies aren't listed.
+// @Description  Author can't delete comment waiting for moderator
 // @Tags         comment, admin, user
 // @Accept       json
 // @Produce      json
@@ -252,6 +567,7 @@ func (ho *HandlerObj) UpdateCommentHandler(rw http.ResponseWriter, r *http.Reque
 // @Success      204
 // @Failure      401  {object}  map[string]string
 // @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /comment/{comment_id} [delete]
 func (ho *HandlerObj) DeleteCommentHandler(rw http.ResponseWriter, r *http.Request) {
@@ -276,7 +592,7 @@ func (ho *HandlerObj) DeleteCommentHandler(rw http.ResponseWriter, r *http.Reque
 		commentData, err := crudl.GetComment(ctx, ho.QuerierDB, commentID)
 		if err != nil {
 			// TODO: add extra information like commentID in error Logger like this
-			ho.Logger.Fatalf("searching comment: %v", err)
+			ho.Logger.Printf("searching comment: %v", err)
 			http.Error(rw, "Can't find comment with current id", http.StatusBadRequest)
 			return
 		}
@@ -285,12 +601,32 @@ func (ho *HandlerObj) DeleteCommentHandler(rw http.ResponseWriter, r *http.Reque
 			http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
 			return
 		}
+		// Author can't take comment away from moderators
+		if commentData.State == CommentStatePending {
+			http.Error(rw, "Comment waits for moderator and can't be deleted", http.StatusConflict)
+			return
+		}
+		reportList, err := crudl.GetOpenCommentReportList(ctx, ho.QuerierDB, commentID)
+		if err != nil {
+			ho.Logger.Printf("proceed getting comment reports: %v", err)
+			http.Error(rw, "Can't delete movie comment", http.StatusInternalServerError)
+			return
+		}
+		if len(reportList) > 0 {
+			http.Error(rw, "Reported comment waits for moderator and can't be deleted", http.StatusConflict)
+			return
+		}
 	}
 
-	if err := crudl.DeleteComment(ctx, ho.QuerierDB, commentID); err != nil {
-		ho.Logger.Println("proceed delete movie comment request")
+	err = crudl.DeleteComment(ctx, ho.DBPool, commentID)
+	if errors.Is(err, crudl.ErrEmptyDeletion) {
 		http.Error(rw, "Can't delete movie comment", http.StatusNotFound)
 		return
 	}
+	if err != nil {
+		ho.Logger.Printf("proceed delete movie comment: %v", err)
+		http.Error(rw, "Can't delete movie comment", http.StatusInternalServerError)
+		return
+	}
 	rw.WriteHeader(http.StatusNoContent)
 }
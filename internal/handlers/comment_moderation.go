@@ -0,0 +1,462 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/crudl"
+	"movie_backend_go/internal/handlers/reqmodel"
+	"movie_backend_go/pkg/auth"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// Comment reported by this many users waits for moderator, 0 disables holding
+	DefaultCommentReportThreshold = 3
+	moderationQueueLimit          = 50
+	moderationQueueMaxSize        = 500
+
+	CommentStateVisible = "visible"
+	CommentStateHidden  = "hidden"
+	CommentStatePending = "pending"
+
+	ModerationHide    = "hide"
+	ModerationDelete  = "delete"
+	ModerationWarn    = "warn"
+	ModerationDismiss = "dismiss"
+)
+
+// Same set is checked by comment_report table
+var commentReportReasons = map[string]bool{
+	"spam":      true,
+	"abuse":     true,
+	"spoiler":   true,
+	"off_topic": true,
+	"other":     true,
+}
+
+// @Summary 		Report comment
+// @Description	Report comment to moderators. Comment reported by several users is hidden until reviewed
+// @Tags        comment, moderation
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Param       comment_id   path		string	true	"Comment ID"
+// @Param       request   body		reqmodel.CommentReportRequest	true	"Report reason"
+// @Success     200		{object}	reqmodel.CommentReportResponse
+// @Failure     400  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     409  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /comment/{comment_id}/report [post]
+func (ho *HandlerObj) ReportCommentHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var commentID pgtype.UUID
+	if err := commentID.Scan(r.PathValue("comment_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested comment id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var reportReq reqmodel.CommentReportRequest
+	err := decoder.Decode(&reportReq)
+	if err != nil && err != io.EOF {
+		ho.Logger.Printf("proceed body request: %v", err)
+		http.Error(rw, "Can't proceed body request", http.StatusBadRequest)
+		return
+	}
+	if !commentReportReasons[reportReq.Reason] {
+		http.Error(rw, "reason should be one of spam, abuse, spoiler, off_topic, other", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	comment, err := crudl.GetComment(ctx, ho.QuerierDB, commentID)
+	if errors.Is(err, pgx.ErrNoRows) || err == nil && (comment.IsDeleted || comment.State != CommentStateVisible) {
+		http.Error(rw, "comment not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		ho.Logger.Printf("searching comment by id - %v: %v", commentID, err)
+		http.Error(rw, "Can't report comment", http.StatusInternalServerError)
+		return
+	}
+	if comment.UserID == userTokenData.UserID {
+		http.Error(rw, "Own comment can't be reported", http.StatusBadRequest)
+		return
+	}
+
+	reportCreate := sqlc.CreateCommentReportParams{
+		CommentID: commentID,
+		UserID:    userTokenData.UserID,
+		Reason:    reportReq.Reason,
+		Message:   reportReq.Message,
+	}
+	report, onHold, err := crudl.ReportComment(ctx, ho.QuerierDB, reportCreate, int32(ho.CommentReportThreshold))
+	if errors.Is(err, crudl.ErrCommentReported) {
+		http.Error(rw, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		ho.Logger.Printf("proceed reporting comment: %v", err)
+		http.Error(rw, "Can't report comment", http.StatusInternalServerError)
+		return
+	}
+	writeResponseBody(rw, reqmodel.CommentReportResponse{Report: report, OnHold: onHold}, "comment report")
+}
+
+// @Summary 		Get moderation queue
+// @Description	Get comments waiting for review and comments with open reports. Held comments go first,
+// @Description	then most reported ones
+// @Tags        moderation, admin
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Param       limit   	query	int 	false  "Max comments amount, 50 by default, 500 at most"
+// @Success     200		{object}	reqmodel.ModerationQueueResponse
+// @Failure     400  	{object}  map[string]string
+// @Failure     401  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /moderation/comment [get]
+func (ho *HandlerObj) GetModerationQueueHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	if !userTokenData.IsAdmin {
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	limit := moderationQueueLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			http.Error(rw, "limit should be positive number", http.StatusBadRequest)
+			return
+		}
+		limit = min(limit, moderationQueueMaxSize)
+	}
+
+	queue, err := crudl.GetModerationQueue(ctx, ho.QuerierDB, int32(limit))
+	if err != nil {
+		ho.Logger.Printf("proceed getting moderation queue: %v", err)
+		http.Error(rw, "Can't get moderation queue", http.StatusNotFound)
+		return
+	}
+	writeResponseBody(rw, reqmodel.ModerationQueueResponse{CommentList: queue}, "moderation queue")
+}
+
+// @Summary 		Get comment reports
+// @Description	Get open reports of comment
+// @Tags        moderation, admin
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Param       comment_id   path		string	true	"Comment ID"
+// @Success     200		{object}	reqmodel.CommentReportListResponse
+// @Failure     400  	{object}  map[string]string
+// @Failure     401  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /moderation/comment/{comment_id}/report [get]
+func (ho *HandlerObj) GetCommentReportListHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var commentID pgtype.UUID
+	if err := commentID.Scan(r.PathValue("comment_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested comment id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	if !userTokenData.IsAdmin {
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	reportList, err := crudl.GetOpenCommentReportList(ctx, ho.QuerierDB, commentID)
+	if err != nil {
+		ho.Logger.Printf("proceed getting comment reports: %v", err)
+		http.Error(rw, "Can't get comment reports", http.StatusNotFound)
+		return
+	}
+	writeResponseBody(rw, reqmodel.CommentReportListResponse{CommentID: commentID, ReportList: reportList}, "comment report list")
+}
+
+// @Summary 		Get comment edit history
+// @Description	Get comment with every text it had before edits, including text of deleted comment kept as tombstone
+// @Tags        comment, moderation, admin
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Param       comment_id   path		string	true	"Comment ID"
+// @Success     200		{object}	reqmodel.CommentHistoryResponse
+// @Failure     400  	{object}  map[string]string
+// @Failure     401  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /comment/{comment_id}/history [get]
+func (ho *HandlerObj) GetCommentHistoryHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var commentID pgtype.UUID
+	if err := commentID.Scan(r.PathValue("comment_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested comment id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	if !userTokenData.IsAdmin {
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	comment, err := crudl.GetComment(ctx, ho.QuerierDB, commentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(rw, "comment not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		ho.Logger.Printf("searching comment by id - %v: %v", commentID, err)
+		http.Error(rw, "Can't get comment history", http.StatusInternalServerError)
+		return
+	}
+	revisionList, err := crudl.GetCommentRevisionList(ctx, ho.QuerierDB, commentID)
+	if err != nil {
+		ho.Logger.Printf("proceed getting comment revisions: %v", err)
+		http.Error(rw, "Can't get comment history", http.StatusNotFound)
+		return
+	}
+	writeResponseBody(rw, reqmodel.CommentHistoryResponse{Comment: comment, RevisionList: revisionList}, "comment history")
+}
+
+// @Summary 		Moderate comment
+// @Description	Resolve open reports of comment with action. hide removes comment from listings, delete
+// @Description	deletes it like its author would, warn hides comment and issues warning to author,
+// @Description	dismiss makes comment visible again
+// @Tags        moderation, admin
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Param       comment_id   path		string	true	"Comment ID"
+// @Param       request   body		reqmodel.ModerationActionRequest	true	"Moderation action"
+// @Success     200		{object}	reqmodel.ModerationActionResponse
+// @Failure     400  	{object}  map[string]string
+// @Failure     401  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /moderation/comment/{comment_id} [post]
+func (ho *HandlerObj) ModerateCommentHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var commentID pgtype.UUID
+	if err := commentID.Scan(r.PathValue("comment_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested comment id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var actionReq reqmodel.ModerationActionRequest
+	err := decoder.Decode(&actionReq)
+	if err != nil && err != io.EOF {
+		ho.Logger.Printf("proceed body request: %v", err)
+		http.Error(rw, "Can't proceed body request", http.StatusBadRequest)
+		return
+	}
+	actionReq.Reason = strings.TrimSpace(actionReq.Reason)
+	switch actionReq.Action {
+	case ModerationHide, ModerationDelete, ModerationDismiss:
+	case ModerationWarn:
+		if actionReq.Reason == "" {
+			http.Error(rw, "reason is required for warning", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(rw, "action should be one of hide, delete, warn, dismiss", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	if !userTokenData.IsAdmin {
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	comment, err := crudl.GetComment(ctx, ho.QuerierDB, commentID)
+	if errors.Is(err, pgx.ErrNoRows) || err == nil && comment.IsDeleted {
+		http.Error(rw, "comment not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		ho.Logger.Printf("searching comment by id - %v: %v", commentID, err)
+		http.Error(rw, "Can't moderate comment", http.StatusInternalServerError)
+		return
+	}
+
+	stateSet := sqlc.SetCommentStateParams{ID: commentID}
+	var warningCreate *sqlc.CreateUserWarningParams
+	switch actionReq.Action {
+	case ModerationDismiss:
+		stateSet.State = CommentStateVisible
+	case ModerationHide:
+		stateSet.State = CommentStateHidden
+	case ModerationWarn:
+		stateSet.State = CommentStateHidden
+		warningCreate = &sqlc.CreateUserWarningParams{
+			UserID:      comment.UserID,
+			CommentID:   commentID,
+			ModeratorID: userTokenData.UserID,
+			Reason:      actionReq.Reason,
+		}
+	case ModerationDelete:
+		// Empty state deletes comment
+	}
+
+	moderation, err := crudl.ModerateComment(ctx, ho.DBPool, stateSet, actionReq.Action, warningCreate)
+	if err != nil {
+		ho.Logger.Printf("proceed moderating comment: %v", err)
+		http.Error(rw, "Can't moderate comment", http.StatusInternalServerError)
+		return
+	}
+	actionResp := reqmodel.ModerationActionResponse{
+		CommentID:       commentID,
+		Action:          actionReq.Action,
+		State:           moderation.Comment.State,
+		ResolvedReports: moderation.ResolvedReports,
+		Warning:         moderation.Warning,
+	}
+	writeResponseBody(rw, actionResp, "moderation action")
+}
+
+// @Summary 		Get my warnings
+// @Description	Get warnings moderators issued to current user
+// @Tags        moderation, user
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Success     200		{object}	reqmodel.UserWarningListResponse
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /user/my/warning [get]
+func (ho *HandlerObj) GetMyUserWarningListHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	warningList, err := crudl.GetUserWarningList(ctx, ho.QuerierDB, userTokenData.UserID)
+	if err != nil {
+		ho.Logger.Printf("proceed getting user warnings: %v", err)
+		http.Error(rw, "Can't get user warnings", http.StatusNotFound)
+		return
+	}
+	writeResponseBody(rw, reqmodel.UserWarningListResponse{UserID: userTokenData.UserID, WarningList: warningList}, "user warning list")
+}
+
+// @Summary 		Get user warnings
+// @Description	Get warnings issued to certain user
+// @Tags        moderation, admin
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Param       user_id   path		string	true	"User ID"
+// @Success     200		{object}	reqmodel.UserWarningListResponse
+// @Failure     400  	{object}  map[string]string
+// @Failure     401  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /moderation/user/{user_id}/warning [get]
+func (ho *HandlerObj) GetUserWarningListHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var userID pgtype.UUID
+	if err := userID.Scan(r.PathValue("user_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested user id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	if !userTokenData.IsAdmin {
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	warningList, err := crudl.GetUserWarningList(ctx, ho.QuerierDB, userID)
+	if err != nil {
+		ho.Logger.Printf("proceed getting user warnings: %v", err)
+		http.Error(rw, "Can't get user warnings", http.StatusNotFound)
+		return
+	}
+	writeResponseBody(rw, reqmodel.UserWarningListResponse{UserID: userID, WarningList: warningList}, "user warning list")
+}
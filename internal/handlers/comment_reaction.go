@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/crudl"
+	"movie_backend_go/internal/handlers/reqmodel"
+	"movie_backend_go/pkg/auth"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Same set is checked by comment_reaction table
+var commentReactions = map[string]bool{
+	"like":    true,
+	"dislike": true,
+	"love":    true,
+	"laugh":   true,
+	"wow":     true,
+	"sad":     true,
+}
+
+// @Summary 		Set comment reaction
+// @Description	React to comment with like, dislike or emoji. User has one reaction per comment, new one replaces previous
+// @Tags        comment
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Param       comment_id   path		string	true	"Comment ID"
+// @Param       request   body		reqmodel.CommentReactionRequest	true	"Reaction"
+// @Success     200		{object}	sqlc.CommentReaction
+// @Failure     400  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /comment/{comment_id}/reaction [put]
+func (ho *HandlerObj) SetCommentReactionHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var commentID pgtype.UUID
+	if err := commentID.Scan(r.PathValue("comment_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested comment id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var reactionReq reqmodel.CommentReactionRequest
+	err := decoder.Decode(&reactionReq)
+	if err != nil && err != io.EOF {
+		ho.Logger.Printf("proceed body request: %v", err)
+		http.Error(rw, "Can't proceed body request", http.StatusBadRequest)
+		return
+	}
+	if !commentReactions[reactionReq.Reaction] {
+		http.Error(rw, "reaction should be one of like, dislike, love, laugh, wow, sad", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	comment, err := crudl.GetComment(ctx, ho.QuerierDB, commentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(rw, "comment not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		ho.Logger.Printf("searching comment by id - %v: %v", commentID, err)
+		http.Error(rw, "Can't set comment reaction", http.StatusInternalServerError)
+		return
+	}
+	if comment.IsDeleted || comment.State != CommentStateVisible {
+		http.Error(rw, "Deleted or hidden comment can't be reacted to", http.StatusBadRequest)
+		return
+	}
+
+	reactionSet := sqlc.SetCommentReactionParams{UserID: userTokenData.UserID, CommentID: commentID, Reaction: reactionReq.Reaction}
+	reaction, err := crudl.SetCommentReaction(ctx, ho.QuerierDB, reactionSet)
+	if err != nil {
+		ho.Logger.Printf("proceed setting comment reaction: %v", err)
+		http.Error(rw, "Can't set comment reaction", http.StatusInternalServerError)
+		return
+	}
+	writeResponseBody(rw, reaction, "comment reaction")
+}
+
+// @Summary 		Delete comment reaction
+// @Description	Remove current user reaction to comment
+// @Tags        comment
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Param       comment_id   path		string	true	"Comment ID"
+// @Success     204
+// @Failure     400  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /comment/{comment_id}/reaction [delete]
+func (ho *HandlerObj) DeleteCommentReactionHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var commentID pgtype.UUID
+	if err := commentID.Scan(r.PathValue("comment_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested comment id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	reactionDelete := sqlc.DeleteCommentReactionParams{UserID: userTokenData.UserID, CommentID: commentID}
+	err = crudl.DeleteCommentReaction(ctx, ho.QuerierDB, reactionDelete)
+	if errors.Is(err, crudl.ErrEmptyDeletion) {
+		http.Error(rw, "comment reaction not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		ho.Logger.Printf("proceed deleting comment reaction: %v", err)
+		http.Error(rw, "Can't delete comment reaction", http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"mime"
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/crudl"
+	"movie_backend_go/internal/handlers/reqmodel"
+	"movie_backend_go/internal/media"
+	"movie_backend_go/pkg/auth"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// Enough to start download before the flight and resume it at the airport
+	DownloadGrantTTL          = 48 * time.Hour
+	DownloadGrantMaxDownloads = 1
+	downloadReportPeriod      = 30 * 24 * time.Hour
+	downloadReportDateLayout  = "2006-01-02"
+	downloadTokenCookie       = "download_token"
+	downloadTokenParam        = "token"
+)
+
+// downloadResumed tell whether request carries token of download already started with grant,
+// in URL the client was redirected to or in cookie
+func downloadResumed(r *http.Request, grant sqlc.DownloadGrant) bool {
+	if grant.DownloadToken == nil {
+		return false
+	}
+	token := r.URL.Query().Get(downloadTokenParam)
+	if cookie, err := r.Cookie(downloadTokenCookie); token == "" && err == nil {
+		token = cookie.Value
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(*grant.DownloadToken)) == 1
+}
+
+// downloadFileName build attachment name from movie title, path separators and quotes are dropped
+func downloadFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\"`, r) || r < 0x20 {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(title))
+	if name == "" {
+		name = "movie"
+	}
+	return name + ".mp4"
+}
+
+// @Summary     Create download grant
+// @Description Issue one-time URL to download movie for offline watching. URL expires in 48 hours,
+// @Description interrupted download can be resumed with Range requests by the same client until then
+// @Tags        download, movie
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       movie_id   	path	string 	true  "Movie ID"
+// @Success     200  {object}  reqmodel.DownloadGrantResponse
+// @Failure     400  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /movie/{movie_id}/download [post]
+func (ho *HandlerObj) CreateDownloadGrantHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	movie, err := crudl.GetMovie(ctx, ho.QuerierDB, movieID)
+	if err != nil {
+		ho.Logger.Printf("get movie for download grant: %v", err)
+		http.Error(rw, "movie not found", http.StatusNotFound)
+		return
+	}
+	if movie.MoviePath == nil {
+		http.Error(rw, "movie file wasn't uploaded", http.StatusNotFound)
+		return
+	}
+
+	grantCreate := sqlc.CreateDownloadGrantParams{
+		UserID:       userTokenData.UserID,
+		MovieID:      movieID,
+		MaxDownloads: DownloadGrantMaxDownloads,
+		TtlSeconds:   int32(DownloadGrantTTL.Seconds()),
+	}
+	grant, err := crudl.CreateDownloadGrant(ctx, ho.QuerierDB, grantCreate)
+	if err != nil {
+		ho.Logger.Printf("proceed creating download grant: %v", err)
+		http.Error(rw, "Can't create download grant", http.StatusInternalServerError)
+		return
+	}
+
+	grantResp := reqmodel.DownloadGrantResponse{
+		ID:           grant.ID,
+		URL:          "/download/" + media.UUIDString(grant.ID),
+		MaxDownloads: grant.MaxDownloads,
+		ExpiresAt:    grant.ExpiresAt.Time,
+	}
+	writeResponseBody(rw, grantResp, "download grant")
+}
+
+// @Summary     Download movie
+// @Description Serve movie file as attachment. The first GET request uses one download of grant and redirects
+// @Description to URL with download token, requests with the token resume already started download or download
+// @Description it with parallel ranges. HEAD requests never use grant
+// @Tags        download
+// @Produce     video/mp4
+// @Param       grant_id 	path	string  true 	"Download grant ID"
+// @Param       token 		query	string  false 	"Download token from redirect"
+// @Param 		Range 		header 	string 	false 	"Byte ranges to resume download, e.g. bytes=1048576-"
+// @Header 		200,206  	{string} 	Content-Disposition 	"attachment; filename=\"Movie.mp4\""
+// @Success 	200  	{object} 	[]byte
+// @Success 	206  	{object} 	[]byte
+// @Success 	302
+// @Failure 	400  	{object} 	map[string]string
+// @Failure 	404  	{object} 	map[string]string
+// @Failure 	410  	{object} 	map[string]string
+// @Failure 	416  	{object} 	map[string]string
+// @Failure 	500  	{object} 	map[string]string
+// @Router     /download/{grant_id} [get]
+// @Router     /download/{grant_id} [head]
+func (ho *HandlerObj) DownloadMovieHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var grantID pgtype.UUID
+	if err := grantID.Scan(r.PathValue("grant_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested grant id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	grant, err := crudl.GetActiveDownloadGrant(ctx, ho.QuerierDB, grantID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(rw, "download grant not found or expired", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		ho.Logger.Printf("proceed getting download grant: %v", err)
+		http.Error(rw, "Can't get download grant", http.StatusInternalServerError)
+		return
+	}
+
+	movie, err := crudl.GetMovie(ctx, ho.QuerierDB, grant.MovieID)
+	if err != nil {
+		http.Error(rw, "movie not found", http.StatusNotFound)
+		return
+	}
+	file, err := os.Open(media.MovieFilePath(media.UUIDString(grant.MovieID)))
+	if err != nil {
+		http.Error(rw, "video not found", http.StatusNotFound)
+		return
+	}
+	defer file.Close()
+
+	// GET without token of started download uses grant, client is redirected to URL with token,
+	// so it resumes download even without cookies
+	if r.Method == http.MethodGet && !downloadResumed(r, grant) {
+		downloadToken := rand.Text()
+		grantUse := sqlc.UseDownloadGrantParams{DownloadToken: &downloadToken, ID: grantID}
+		_, err := crudl.UseDownloadGrant(ctx, ho.QuerierDB, grantUse)
+		if errors.Is(err, crudl.ErrDownloadGrantUsed) {
+			http.Error(rw, err.Error(), http.StatusGone)
+			return
+		}
+		if err != nil {
+			ho.Logger.Printf("proceed using download grant: %v", err)
+			http.Error(rw, "Can't use download grant", http.StatusInternalServerError)
+			return
+		}
+		http.SetCookie(rw, &http.Cookie{
+			Name:     downloadTokenCookie,
+			Value:    downloadToken,
+			Path:     "/download/" + media.UUIDString(grantID),
+			Expires:  grant.ExpiresAt.Time,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		query := url.Values{downloadTokenParam: {downloadToken}}
+		http.Redirect(rw, r, "/download/"+media.UUIDString(grantID)+"?"+query.Encode(), http.StatusFound)
+		return
+	}
+
+	rw.Header().Set("Content-Disposition",
+		mime.FormatMediaType("attachment", map[string]string{"filename": downloadFileName(movie.Title)}))
+	// Downloads are throttled with rate of the user grant was issued for
+	grantUserData := auth.UserTokenData{UserID: grant.UserID}
+	if grantUser, err := crudl.GetUser(ctx, ho.QuerierDB, grant.UserID); err == nil {
+		grantUserData.IsAdmin = grantUser.IsAdmin
+	} else {
+		ho.Logger.Printf("get download grant user: %v", err)
+	}
+	r = r.WithContext(auth.SetTokenDataContext(r.Context(), grantUserData))
+	ho.serveMedia(rw, r, file, "video/mp4")
+}
+
+// @Summary     Get download report
+// @Description Get downloads per user and movie for licensing reports. Period is last 30 days by default
+// @Tags        download, admin
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       from   	query	string 	false  "Period start date, e.g. 2024-01-01"
+// @Param       to   	query	string 	false  "Period end date, exclusive"
+// @Success     200  {object}  reqmodel.DownloadReportResponse
+// @Failure     400  {object}  map[string]string
+// @Failure     401  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /download/report [get]
+func (ho *HandlerObj) GetDownloadReportHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	if !userTokenData.IsAdmin {
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	to := time.Now()
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		to, err = time.Parse(downloadReportDateLayout, toStr)
+		if err != nil {
+			http.Error(rw, "to should be date like 2024-01-31", http.StatusBadRequest)
+			return
+		}
+	}
+	from := to.Add(-downloadReportPeriod)
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		from, err = time.Parse(downloadReportDateLayout, fromStr)
+		if err != nil {
+			http.Error(rw, "from should be date like 2024-01-01", http.StatusBadRequest)
+			return
+		}
+	}
+	if !from.Before(to) {
+		http.Error(rw, "from should be before to", http.StatusBadRequest)
+		return
+	}
+
+	reportGet := sqlc.GetDownloadReportParams{
+		FromTime: pgtype.Timestamp{Time: from, Valid: true},
+		ToTime:   pgtype.Timestamp{Time: to, Valid: true},
+	}
+	report, err := crudl.GetDownloadReport(ctx, ho.QuerierDB, reportGet)
+	if err != nil {
+		ho.Logger.Printf("proceed getting download report: %v", err)
+		http.Error(rw, "Can't get download report", http.StatusNotFound)
+		return
+	}
+	reportResp := reqmodel.DownloadReportResponse{From: from, To: to, Entries: report}
+	writeResponseBody(rw, reportResp, "download report")
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"movie_backend_go/internal/crudl"
+	"movie_backend_go/internal/handlers/reqmodel"
+	"movie_backend_go/pkg/auth"
+	"net/http"
+	"strconv"
+)
+
+const (
+	ingestLogLimit   = 100
+	ingestLogMaxSize = 1000
+)
+
+// @Summary     Get ingest log
+// @Description Get outcomes of watch folder ingestion, most recent first
+// @Tags        video-manager, admin
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       limit   	query	int 	false  "Max entries amount, 100 by default, 1000 at most"
+// @Success     200  {object}  reqmodel.IngestLogListResponse
+// @Failure     400  {object}  map[string]string
+// @Failure     401  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /ingest/log [get]
+func (ho *HandlerObj) GetIngestLogListHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	if !userTokenData.IsAdmin {
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	limit := ingestLogLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			http.Error(rw, "limit should be positive number", http.StatusBadRequest)
+			return
+		}
+		limit = min(limit, ingestLogMaxSize)
+	}
+
+	ingestLogList, err := crudl.GetIngestLogList(ctx, ho.QuerierDB, int32(limit))
+	if err != nil {
+		ho.Logger.Printf("proceed getting ingest log: %v", err)
+		http.Error(rw, "Can't get ingest log", http.StatusNotFound)
+		return
+	}
+	writeResponseBody(rw, reqmodel.IngestLogListResponse{IngestLogList: ingestLogList}, "ingest log")
+}
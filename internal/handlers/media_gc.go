@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"movie_backend_go/internal/mediagc"
+	"movie_backend_go/pkg/auth"
+	"net/http"
+)
+
+// @Summary     Get media GC report
+// @Description Get report of the latest media storage reconciliation: orphaned files and movies with missing files
+// @Tags        video-manager, admin
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Success     200  {object}  mediagc.Report
+// @Failure     401  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /media/gc [get]
+func (ho *HandlerObj) GetMediaGCReportHandler(rw http.ResponseWriter, r *http.Request) {
+	userTokenData, err := auth.GetTokenDataContext(r.Context())
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	if !userTokenData.IsAdmin {
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	if ho.MediaGC == nil {
+		http.Error(rw, "media GC is disabled", http.StatusNotFound)
+		return
+	}
+	report, ok := ho.MediaGC.LastReport()
+	if !ok {
+		http.Error(rw, "media GC didn't run yet", http.StatusNotFound)
+		return
+	}
+	writeResponseBody(rw, report, "media gc report")
+}
+
+// @Summary     Run media GC
+// @Description Reconcile media storage with database now. Orphans older than grace period are deleted
+// @Description only when server runs with MEDIA_GC_DELETE enabled, otherwise they are reported
+// @Tags        video-manager, admin
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Success     200  {object}  mediagc.Report
+// @Failure     401  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     409  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /media/gc [post]
+func (ho *HandlerObj) RunMediaGCHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	if !userTokenData.IsAdmin {
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	if ho.MediaGC == nil {
+		http.Error(rw, "media GC is disabled", http.StatusNotFound)
+		return
+	}
+	report, err := ho.MediaGC.Run(ctx)
+	if errors.Is(err, mediagc.ErrRunning) {
+		http.Error(rw, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		ho.Logger.Printf("proceed media gc run: %v", err)
+		http.Error(rw, "Can't run media GC", http.StatusInternalServerError)
+		return
+	}
+	writeResponseBody(rw, report, "media gc report")
+}
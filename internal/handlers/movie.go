@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"slices"
 
 	"movie_backend_go/db/sqlc"
 	"movie_backend_go/internal/crudl"
@@ -14,12 +15,24 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const (
+	MovieSortTitle    = "title"
+	MovieSortNew      = "new"
+	MovieSortRating   = "rating"
+	MovieSortWeighted = "weighted"
+	MovieSortVotes    = "votes"
+)
+
+var movieSortList = []string{MovieSortTitle, MovieSortNew, MovieSortRating, MovieSortWeighted, MovieSortVotes}
+
 // @Summary      Get movie list
-// @Description  Get all movie list
+// @Description  Get all movie list with rating. Weighted rating is Bayesian average pulling movies with few votes to global rating
 // @Tags         movie
 // @Accept       json
 // @Produce      json
+// @Param        sort   	query	string 	false  "Movie order, title by default" Enums(title, new, rating, weighted, votes)
 // @Success      200  {object}  reqmodel.MovieListResponse
+// @Failure      400  {object}	map[string]string
 // @Failure      404  {object}	map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /movie [get]
@@ -27,24 +40,34 @@ func (ho *HandlerObj) GetMovieListHandler(rw http.ResponseWriter, r *http.Reques
 	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
 	defer close()
 
-	movieList, err := crudl.GetMovieList(ctx, ho.QuerierDB)
+	sort := r.URL.Query().Get("sort")
+	if sort == "" {
+		sort = MovieSortTitle
+	}
+	if !slices.Contains(movieSortList, sort) {
+		http.Error(rw, "sort should be title, new, rating, weighted or votes", http.StatusBadRequest)
+		return
+	}
+
+	movieListGet := sqlc.GetMovieListParams{MinVotes: int32(ho.RatingMinVotes), Sort: sort}
+	movieList, err := crudl.GetMovieList(ctx, ho.QuerierDB, movieListGet)
 	if err != nil {
 		ho.Logger.Println(err)
 		http.Error(rw, "Can't get movie list", http.StatusBadRequest)
 		return
 	}
 
-	movieListResponse := reqmodel.MovieListResponse{MovieList: movieList}
+	movieListResponse := reqmodel.MovieListResponse{Sort: sort, MinVotes: movieListGet.MinVotes, MovieList: movieList}
 	writeResponseBody(rw, movieListResponse, "movie")
 }
 
 // @Summary      Get movie
-// @Description  Get movie by id
+// @Description  Get movie by id with rating and technical metadata of uploaded file
 // @Tags         movie
 // @Accept       json
 // @Produce      json
 // @Param        movie_id   path      string  true  "Movie ID"
-// @Success      200  {object}  sqlc.Movie
+// @Success      200  {object}  sqlc.GetMovieRow
 // @Failure      404  {object}	map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /movie/{movie_id} [get]
@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/crudl"
+	"movie_backend_go/internal/handlers/reqmodel"
+	"movie_backend_go/internal/media"
+	"movie_backend_go/pkg/auth"
+	"movie_backend_go/pkg/mp4probe"
+	"movie_backend_go/pkg/subtitle"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var movieAssetKinds = map[string]bool{
+	"main":          true,
+	"trailer":       true,
+	"extra":         true,
+	"dub":           true,
+	"directors_cut": true,
+}
+
+// @Summary     Create movie asset
+// @Description Create asset record (main feature, trailer, extra, dubbed version, director's cut), file is uploaded separately
+// @Tags        video-manager, admin
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       movie_id   	path	string 	true  "Movie ID"
+// @Param       request   	body	reqmodel.MovieAssetCreateRequest	true	"Asset description"
+// @Success     200  {object}  sqlc.MovieAsset
+// @Failure     400  {object}  map[string]string
+// @Failure     401  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /movie/{movie_id}/asset [post]
+func (ho *HandlerObj) CreateMovieAssetHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+	// Verify
+	if !userTokenData.IsAdmin {
+		ho.Logger.Println("Unauthorized user")
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var assetReq reqmodel.MovieAssetCreateRequest
+	if err := decoder.Decode(&assetReq); err != nil {
+		ho.Logger.Printf("proceed body request: %v", err)
+		http.Error(rw, "Can't proceed body request", http.StatusBadRequest)
+		return
+	}
+	if !movieAssetKinds[assetReq.Kind] {
+		http.Error(rw, "kind should be main, trailer, extra, dub or directors_cut", http.StatusBadRequest)
+		return
+	}
+	if assetReq.Language != nil && !subtitle.ValidLanguage(*assetReq.Language) {
+		http.Error(rw, "language should be BCP 47 tag, e.g. en or pt-BR", http.StatusBadRequest)
+		return
+	}
+	// Dubbed version is distinguished by audio language only
+	if assetReq.Kind == "dub" && assetReq.Language == nil {
+		http.Error(rw, "dub asset requires language", http.StatusBadRequest)
+		return
+	}
+
+	assetCreate := sqlc.CreateMovieAssetParams{
+		MovieID:  movieID,
+		Kind:     assetReq.Kind,
+		Language: assetReq.Language,
+		Quality:  assetReq.Quality,
+		Title:    assetReq.Title,
+	}
+	asset, err := crudl.CreateMovieAsset(ctx, ho.QuerierDB, assetCreate)
+	if err != nil {
+		ho.Logger.Printf("proceed creating movie asset: %v", err)
+		http.Error(rw, "Can't create movie asset", http.StatusNotFound)
+		return
+	}
+	writeResponseBody(rw, asset, "movie asset")
+}
+
+// @Summary     Get movie assets list
+// @Description Get every asset of movie with kind, language, quality and technical metadata
+// @Tags        video-manager, movie
+// @Accept      json
+// @Produce     json
+// @Param       movie_id   	path	string 	true  "Movie ID"
+// @Success     200  {object}  reqmodel.MovieAssetListResponse
+// @Failure     400  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /movie/{movie_id}/asset [get]
+func (ho *HandlerObj) GetMovieAssetListHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	assetList, err := crudl.GetMovieAssetList(ctx, ho.QuerierDB, movieID)
+	if err != nil {
+		ho.Logger.Printf("proceed getting movie asset list: %v", err)
+		http.Error(rw, "Can't get movie asset list", http.StatusNotFound)
+		return
+	}
+	assetListResp := reqmodel.MovieAssetListResponse{MovieID: movieID, AssetList: assetList}
+	writeResponseBody(rw, assetListResp, "movie asset list")
+}
+
+// @Summary     Upload movie asset
+// @Description Upload faststart mp4 file of asset as []bytes stream. Previous file of asset is replaced
+// @Tags        video-manager, admin
+// @Accept 		octet-stream
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       asset_id   	path	string 	true  "Asset ID"
+// @Param       request		body	[]byte 	true  "Streaming Bytes"
+// @Success     200  {object}  sqlc.MovieAsset
+// @Failure     400  {object}  map[string]string
+// @Failure     401  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     422  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /upload/asset/{asset_id} [post]
+func (ho *HandlerObj) UploadMovieAssetHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var assetID pgtype.UUID
+	if err := assetID.Scan(r.PathValue("asset_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested asset id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+	// Verify
+	if !userTokenData.IsAdmin {
+		ho.Logger.Println("Unauthorized user")
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	asset, err := crudl.GetMovieAsset(ctx, ho.QuerierDB, assetID)
+	if err != nil {
+		ho.Logger.Printf("get movie asset: %v", err)
+		http.Error(rw, "asset not found", http.StatusNotFound)
+		return
+	}
+
+	assetPath := media.AssetFilePath(media.UUIDString(asset.MovieID), media.UUIDString(assetID))
+	if err := os.MkdirAll(filepath.Dir(assetPath), 0o755); err != nil {
+		ho.Logger.Printf("create asset directory: %v", err)
+		http.Error(rw, "Can't create asset file", http.StatusInternalServerError)
+		return
+	}
+
+	// Asset is written aside, so current file keeps streaming until upload is verified
+	uploadPath := assetPath + ".upload"
+	file, err := os.Create(uploadPath)
+	if err != nil {
+		ho.Logger.Printf("create asset file: %v", err)
+		http.Error(rw, "Can't create asset file", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, r.Body); err != nil {
+		ho.Logger.Printf("write uploaded asset %s: %v", uploadPath, err)
+		os.Remove(uploadPath)
+		http.Error(rw, "Can't write uploaded asset", http.StatusInternalServerError)
+		return
+	}
+
+	// Assets are served as is, without transcoding
+	assetInfo, err := mp4probe.ProbeFile(uploadPath)
+	if err != nil {
+		ho.Logger.Printf("probe uploaded asset %s: %v", uploadPath, err)
+		os.Remove(uploadPath)
+		http.Error(rw, "Uploaded file isn't valid mp4", http.StatusUnprocessableEntity)
+		return
+	}
+	if !assetInfo.FastStart {
+		os.Remove(uploadPath)
+		http.Error(rw, "Uploaded file has moov atom at the end, upload faststart file", http.StatusUnprocessableEntity)
+		return
+	}
+	if err := os.Rename(uploadPath, assetPath); err != nil {
+		ho.Logger.Printf("move uploaded asset in place: %v", err)
+		os.Remove(uploadPath)
+		http.Error(rw, "Can't save asset file", http.StatusInternalServerError)
+		return
+	}
+
+	durationMs := assetInfo.Duration.Milliseconds()
+	width := int32(assetInfo.Width)
+	height := int32(assetInfo.Height)
+	assetFile := sqlc.SetMovieAssetFileParams{
+		ID:         assetID,
+		AssetPath:  &assetPath,
+		DurationMs: &durationMs,
+		Width:      &width,
+		Height:     &height,
+		VideoCodec: &assetInfo.VideoCodec,
+		AudioCodec: &assetInfo.AudioCodec,
+		Bitrate:    &assetInfo.Bitrate,
+	}
+	if err := crudl.SetMovieAssetFile(ctx, ho.QuerierDB, assetFile); err != nil {
+		ho.Logger.Printf("save movie asset file: %v", err)
+		http.Error(rw, "Can't save asset file", http.StatusNotFound)
+		return
+	}
+
+	asset, err = crudl.GetMovieAsset(ctx, ho.QuerierDB, assetID)
+	if err != nil {
+		ho.Logger.Printf("get movie asset: %v", err)
+		http.Error(rw, "asset not found", http.StatusNotFound)
+		return
+	}
+	writeResponseBody(rw, asset, "movie asset")
+}
+
+// @Summary     Stream movie asset
+// @Description Progressive stream of asset with the same range and conditional requests support as movie stream.
+// @Description Use the same signed query as movie stream URL
+// @Tags        video-manager
+// @Produce     video/mp4
+// @Produce     multipart/byteranges
+// @Param       movie_id 			path	string  true 	"Movie ID"
+// @Param       asset_id 			path	string  true 	"Asset ID"
+// @Param 		Range 				header 	string 	false 	"Byte ranges, e.g. bytes=0-499,-500"
+// @Param       user_id 			query	string  true 	"User ID from signed URL"
+// @Param       expires 			query	int  	true 	"Unix expiration time from signed URL"
+// @Param       session 			query	string  true 	"Playback session from signed URL"
+// @Param       signature 			query	string  true 	"Signature from signed URL"
+// @Success 	200  	{object} 	[]byte
+// @Success 	206  	{object} 	[]byte
+// @Success 	304
+// @Failure 	400  	{object} 	map[string]string
+// @Failure 	403  	{object} 	map[string]string
+// @Failure 	404  	{object} 	map[string]string
+// @Failure 	409  	{object} 	map[string]string
+// @Failure 	416  	{object} 	map[string]string
+// @Failure 	429  	{object} 	reqmodel.StreamLimitErrorResponse
+// @Failure 	500  	{object} 	map[string]string
+// @Router     /stream/movie/{movie_id}/asset/{asset_id} [get]
+// @Router     /stream/movie/{movie_id}/asset/{asset_id} [head]
+func (ho *HandlerObj) StreamMovieAsset(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID, assetID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+	if err := assetID.Scan(r.PathValue("asset_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested asset id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	// Signature is verified by middleware before any lookup, so unsigned
+	// requests can't probe which assets exist
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	asset, err := crudl.GetMovieAsset(ctx, ho.QuerierDB, assetID)
+	if err != nil || asset.MovieID != movieID {
+		http.Error(rw, "asset not found", http.StatusNotFound)
+		return
+	}
+	if asset.AssetPath == nil {
+		http.Error(rw, "asset file wasn't uploaded", http.StatusNotFound)
+		return
+	}
+
+	file, err := os.Open(*asset.AssetPath)
+	if err != nil {
+		http.Error(rw, "video not found", http.StatusNotFound)
+		return
+	}
+	defer file.Close()
+
+	// Trailers and extras are previews, they don't take concurrent stream slot
+	if asset.Kind != "trailer" && asset.Kind != "extra" {
+		if !ho.acquireStreamLease(rw, r, userTokenData.UserID, asset.MovieID) {
+			return
+		}
+	}
+	ho.serveMedia(rw, r, file, "video/mp4")
+}
+
+// @Summary     Delete movie asset
+// @Tags        video-manager, admin
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       asset_id   	path	string 	true  "Asset ID"
+// @Success     204
+// @Failure     400  {object}  map[string]string
+// @Failure     401  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /asset/{asset_id} [delete]
+func (ho *HandlerObj) DeleteMovieAssetHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var assetID pgtype.UUID
+	if err := assetID.Scan(r.PathValue("asset_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested asset id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+	// Verify
+	if !userTokenData.IsAdmin {
+		ho.Logger.Println("Unauthorized user")
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	assetPath, err := crudl.DeleteMovieAsset(ctx, ho.QuerierDB, assetID)
+	if err != nil {
+		ho.Logger.Printf("proceed delete movie asset request: %v", err)
+		http.Error(rw, "Can't delete asset", http.StatusNotFound)
+		return
+	}
+
+	// Main asset produced by transcoding shares file with movie stream, keep it
+	if assetPath != nil && filepath.Base(*assetPath) == media.UUIDString(assetID)+".mp4" {
+		if err := os.Remove(*assetPath); err != nil {
+			ho.Logger.Printf("remove movie asset file %s: %v", *assetPath, err)
+		}
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"io"
 	"net/http"
@@ -108,6 +110,81 @@ func (ho *HandlerObj) GetMovieRatingListHandler(rw http.ResponseWriter, r *http.
 	writeResponseBody(rw, ratedMovieListResponse, "movie rating list")
 }
 
+// @Summary			 Get movie rating stats
+// @Description  Get movie rating histogram, median, standard deviation and Bayesian weighted rating
+// @Tags         rating, movie
+// @Accept       json
+// @Produce      json
+// @Param        movie_id 	path	string  true  "Movie ID"
+// @Success      200  {object}  reqmodel.MovieRatingStatsResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /movie/{movie_id}/rating/stats [get]
+func (ho *HandlerObj) GetMovieRatingStatsHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	ratingStatsGet := sqlc.GetMovieRatingStatsParams{MinVotes: int32(ho.RatingMinVotes), MovieID: movieID}
+	ratingStats, err := crudl.GetMovieRatingStats(ctx, ho.QuerierDB, ratingStatsGet)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(rw, "movie not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		ho.Logger.Printf("get movie %v rating stats: %v", movieID, err)
+		http.Error(rw, "Can't get movie rating stats", http.StatusInternalServerError)
+		return
+	}
+
+	histogram := make([]reqmodel.RatingHistogramBucket, len(ratingStats.Histogram))
+	for i, count := range ratingStats.Histogram {
+		histogram[i] = reqmodel.RatingHistogramBucket{Rating: int16(i + 1), Count: count}
+	}
+	ratingStatsResponse := reqmodel.MovieRatingStatsResponse{
+		MovieID:        movieID,
+		AmountRates:    ratingStats.AmountRates,
+		Average:        ratingStats.Rating,
+		Median:         histogramMedian(ratingStats.Histogram, ratingStats.AmountRates),
+		StdDev:         ratingStats.Stddev,
+		Histogram:      histogram,
+		WeightedRating: ratingStats.WeightedRating,
+		GlobalRating:   ratingStats.GlobalRating,
+		MinVotes:       ratingStatsGet.MinVotes,
+		UpdatedAt:      ratingStats.UpdatedAt,
+	}
+	writeResponseBody(rw, ratingStatsResponse, "movie rating stats")
+}
+
+// histogramMedian return median rating, histogram element i is amount of rating i + 1
+func histogramMedian(histogram []int64, amountRates int64) float64 {
+	if amountRates == 0 {
+		return 0
+	}
+	// Ratings at positions lower and upper are averaged, they are equal for odd amount
+	lower, upper := (amountRates+1)/2, amountRates/2+1
+	var seen int64
+	var lowerRating float64
+	for i, count := range histogram {
+		rating := float64(i + 1)
+		if seen < lower && seen+count >= lower {
+			lowerRating = rating
+		}
+		seen += count
+		if seen >= upper {
+			return (lowerRating + rating) / 2
+		}
+	}
+	return lowerRating
+}
+
 // @Summary			 Get rating
 // @Tags         rating
 // @Accept       json
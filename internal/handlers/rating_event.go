@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/crudl"
+	"movie_backend_go/internal/handlers/reqmodel"
+	"movie_backend_go/pkg/auth"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	RatingTrendDay  = "day"
+	RatingTrendWeek = "week"
+
+	ratingTrendDays    = 90
+	ratingTrendMaxDays = 3650
+
+	ratingHistoryLimit   = 50
+	ratingHistoryMaxSize = 500
+)
+
+// @Summary 		Get movie rating trend
+// @Description	Get movie rating changes grouped by day or week. Buckets without changes are skipped,
+// @Description	amount_rates and rating are totals at bucket end, bucket_rating is average of ratings given within bucket.
+// @Description	Ratings given before rating history was kept count in totals only
+// @Tags        rating, movie
+// @Accept      json
+// @Produce     json
+// @Param       movie_id   path		string	true	"Movie ID"
+// @Param       bucket   	query	string 	false  "Bucket size, day by default" Enums(day, week)
+// @Param       days   	query	int 	false  "Days to look back, 90 by default, 3650 at most"
+// @Success     200		{object}	reqmodel.MovieRatingTrendResponse
+// @Failure     400  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /movie/{movie_id}/rating/trend [get]
+func (ho *HandlerObj) GetMovieRatingTrendHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	bucket := r.URL.Query().Get("bucket")
+	if bucket == "" {
+		bucket = RatingTrendDay
+	}
+	if bucket != RatingTrendDay && bucket != RatingTrendWeek {
+		http.Error(rw, "bucket should be day or week", http.StatusBadRequest)
+		return
+	}
+	var err error
+	days := ratingTrendDays
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		days, err = strconv.Atoi(daysStr)
+		if err != nil || days <= 0 {
+			http.Error(rw, "days should be positive number", http.StatusBadRequest)
+			return
+		}
+		days = min(days, ratingTrendMaxDays)
+	}
+
+	trendGet := sqlc.GetMovieRatingTrendParams{
+		BucketSize: bucket,
+		MovieID:    movieID,
+		Since:      pgtype.Timestamp{Time: time.Now().AddDate(0, 0, -days), Valid: true},
+	}
+	trend, err := crudl.GetMovieRatingTrend(ctx, ho.QuerierDB, trendGet)
+	if err != nil {
+		ho.Logger.Printf("proceed movie %v rating trend: %v", movieID, err)
+		http.Error(rw, "Can't get movie rating trend", http.StatusNotFound)
+		return
+	}
+	if trend == nil {
+		trend = []sqlc.GetMovieRatingTrendRow{}
+	}
+
+	trendResponse := reqmodel.MovieRatingTrendResponse{MovieID: movieID, Bucket: bucket, Since: trendGet.Since, Trend: trend}
+	writeResponseBody(rw, trendResponse, "movie rating trend")
+}
+
+// @Summary 		Get user rating history
+// @Description	Get creations and changes of current user ratings, newest first. Deleted ratings are left out,
+// @Description	whole history is available to user only
+// @Tags        rating, user
+// @Accept      json
+// @Produce     json
+// @Param       user_id   path		string	true	"User ID"
+// @Param       limit   	query	int 	false  "Events amount, 50 by default, 500 at most"
+// @Param       offset   	query	int 	false  "Events to skip"
+// @Success     200		{object}	reqmodel.UserRatingHistoryResponse
+// @Failure     400  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /user/{user_id}/rating/history [get]
+func (ho *HandlerObj) GetUserRatingHistoryHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var userID pgtype.UUID
+	if err := userID.Scan(r.PathValue("user_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested user id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+	ho.writeUserRatingHistory(ctx, rw, r, userID, true)
+}
+
+// @Summary 		Get my rating history
+// @Description	Get current user rating creations, changes and deletions, newest first
+// @Tags        rating, user
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Param       limit   	query	int 	false  "Events amount, 50 by default, 500 at most"
+// @Param       offset   	query	int 	false  "Events to skip"
+// @Success     200		{object}	reqmodel.UserRatingHistoryResponse
+// @Failure     400  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /user/my/rating/history [get]
+func (ho *HandlerObj) GetMyRatingHistoryHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	// Extract token
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+	ho.writeUserRatingHistory(ctx, rw, r, userTokenData.UserID, false)
+}
+
+// writeUserRatingHistory write page of user rating events, currentOnly leaves out deleted ratings
+func (ho *HandlerObj) writeUserRatingHistory(ctx context.Context, rw http.ResponseWriter, r *http.Request, userID pgtype.UUID, currentOnly bool) {
+	var err error
+	limit := ratingHistoryLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			http.Error(rw, "limit should be positive number", http.StatusBadRequest)
+			return
+		}
+		limit = min(limit, ratingHistoryMaxSize)
+	}
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			http.Error(rw, "offset should be non negative number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	eventListGet := sqlc.GetUserRatingEventListParams{
+		UserID:      userID,
+		CurrentOnly: currentOnly,
+		PageLimit:   int32(limit),
+		PageOffset:  int32(offset),
+	}
+	eventList, err := crudl.GetUserRatingEventList(ctx, ho.QuerierDB, eventListGet)
+	if err != nil {
+		ho.Logger.Printf("proceed user %v rating history: %v", userID, err)
+		http.Error(rw, "Can't get user rating history", http.StatusNotFound)
+		return
+	}
+	eventsCount := sqlc.CountUserRatingEventsParams{UserID: userID, CurrentOnly: currentOnly}
+	total, err := crudl.CountUserRatingEvents(ctx, ho.QuerierDB, eventsCount)
+	if err != nil {
+		ho.Logger.Printf("proceed counting user %v rating events: %v", userID, err)
+		http.Error(rw, "Can't get user rating history", http.StatusNotFound)
+		return
+	}
+	if eventList == nil {
+		eventList = []sqlc.GetUserRatingEventListRow{}
+	}
+
+	historyResponse := reqmodel.UserRatingHistoryResponse{
+		UserID:          userID,
+		Total:           total,
+		Limit:           limit,
+		Offset:          offset,
+		RatingEventList: eventList,
+	}
+	writeResponseBody(rw, historyResponse, "user rating history")
+}
@@ -2,17 +2,27 @@ package reqmodel
 
 import (
 	"movie_backend_go/db/sqlc"
+	"movie_backend_go/pkg/spoiler"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type CommentCreateRequest struct {
 	MovieID pgtype.UUID `json:"movie_id"`
-	Text    string      `json:"text"`
+	// Set to reply to comment of the same movie
+	ParentID pgtype.UUID `json:"parent_id"`
+	// Text may mark inline spoilers with double bars: "the killer is ||the butler||"
+	Text string `json:"text"`
+	// Whole comment is spoiler
+	IsSpoiler bool `json:"is_spoiler"`
+	// BCP 47 tag of text language, it picks banned words list. Default list is used when omitted
+	Language *string `json:"language"`
 }
 
 type CommentUpdateRequest struct {
 	Text string `json:"text"`
+	// Omitted keeps current flag
+	IsSpoiler *bool `json:"is_spoiler"`
 }
 
 type MovieCommentRequest struct {
@@ -25,12 +35,70 @@ type UserCommentRequest struct {
 	Text   string      `json:"text"`
 }
 
+// UserComment is comment outside of its thread, spoilers are hidden the same way as in thread
+type UserComment struct {
+	sqlc.GetUserCommentListRow
+	Segments      []spoiler.Segment `json:"segments"`
+	SpoilerHidden bool              `json:"spoiler_hidden"`
+}
+
 type UserCommentListResponse struct {
-	UserID          pgtype.UUID                  `json:"user_id"`
-	UserCommentList []sqlc.GetUserCommentListRow `json:"user_comment_list"`
+	UserID          pgtype.UUID   `json:"user_id"`
+	SpoilersHidden  bool          `json:"spoilers_hidden"`
+	UserCommentList []UserComment `json:"user_comment_list"`
+}
+
+// CommentResponse is single comment, spoilers are hidden the same way as in thread
+type CommentResponse struct {
+	sqlc.Comment
+	Segments      []spoiler.Segment `json:"segments"`
+	SpoilerHidden bool              `json:"spoiler_hidden"`
+}
+
+// CommentNode is comment with its replies. Tombstones of deleted comments and placeholders
+// of comments hidden by moderator or held for review keep only position in thread, their author
+// and text are hidden
+type CommentNode struct {
+	ID       pgtype.UUID `json:"id"`
+	UserID   pgtype.UUID `json:"user_id"`
+	ParentID pgtype.UUID `json:"parent_id"`
+	Depth    int16       `json:"depth"`
+	Text     string      `json:"text"`
+	// Text split into plain and spoiler parts
+	Segments  []spoiler.Segment `json:"segments"`
+	IsSpoiler bool              `json:"is_spoiler"`
+	// Set when text of spoiler comment or inline spoilers was removed from response
+	SpoilerHidden bool `json:"spoiler_hidden"`
+	IsDeleted     bool `json:"is_deleted"`
+	// Set for placeholder of hidden or held comment
+	IsHidden   bool             `json:"is_hidden"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	EditedAt   pgtype.Timestamp `json:"edited_at"`
+	ReplyCount int64            `json:"reply_count"`
+	// Amount of every reaction, score is reactions except dislikes minus dislikes
+	Reactions map[string]int64 `json:"reactions"`
+	Score     int64            `json:"score"`
+	Replies   []*CommentNode   `json:"replies"`
 }
 
 type MovieCommentListResponse struct {
-	MovieID          pgtype.UUID                   `json:"movie_id"`
-	MovieCommentList []sqlc.GetMovieCommentListRow `json:"movie_comment_list"`
+	MovieID pgtype.UUID `json:"movie_id"`
+	// Amount of top level comments
+	Total            int64          `json:"total"`
+	Limit            int            `json:"limit"`
+	Offset           int            `json:"offset"`
+	Sort             string         `json:"sort"`
+	SpoilersHidden   bool           `json:"spoilers_hidden"`
+	MovieCommentList []*CommentNode `json:"movie_comment_list"`
+}
+
+type CommentReactionRequest struct {
+	// One of like, dislike, love, laugh, wow, sad
+	Reaction string `json:"reaction"`
+}
+
+type CommentHistoryResponse struct {
+	Comment sqlc.Comment `json:"comment"`
+	// Previous texts, oldest first. Current text is in comment
+	RevisionList []sqlc.CommentRevision `json:"revision_list"`
 }
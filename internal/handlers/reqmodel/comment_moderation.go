@@ -0,0 +1,49 @@
+package reqmodel
+
+import (
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CommentReportRequest struct {
+	// One of spam, abuse, spoiler, off_topic, other
+	Reason  string  `json:"reason"`
+	Message *string `json:"message"`
+}
+
+type CommentReportResponse struct {
+	Report sqlc.CommentReport `json:"report"`
+	// Set when this report put comment on hold for review
+	OnHold bool `json:"on_hold"`
+}
+
+type ModerationQueueResponse struct {
+	CommentList []sqlc.GetModerationQueueRow `json:"comment_list"`
+}
+
+type CommentReportListResponse struct {
+	CommentID  pgtype.UUID          `json:"comment_id"`
+	ReportList []sqlc.CommentReport `json:"report_list"`
+}
+
+type ModerationActionRequest struct {
+	// One of hide, delete, warn, dismiss
+	Action string `json:"action"`
+	// Required for warn, shown to warned user
+	Reason string `json:"reason"`
+}
+
+type ModerationActionResponse struct {
+	CommentID pgtype.UUID `json:"comment_id"`
+	Action    string      `json:"action"`
+	// Comment state after action, empty when comment was deleted
+	State           string            `json:"state"`
+	ResolvedReports int64             `json:"resolved_reports"`
+	Warning         *sqlc.UserWarning `json:"warning"`
+}
+
+type UserWarningListResponse struct {
+	UserID      pgtype.UUID        `json:"user_id"`
+	WarningList []sqlc.UserWarning `json:"warning_list"`
+}
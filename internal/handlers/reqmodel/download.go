@@ -0,0 +1,21 @@
+package reqmodel
+
+import (
+	"movie_backend_go/db/sqlc"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type DownloadGrantResponse struct {
+	ID           pgtype.UUID `json:"id"`
+	URL          string      `json:"url"`
+	MaxDownloads int32       `json:"max_downloads"`
+	ExpiresAt    time.Time   `json:"expires_at"`
+}
+
+type DownloadReportResponse struct {
+	From    time.Time                   `json:"from"`
+	To      time.Time                   `json:"to"`
+	Entries []sqlc.GetDownloadReportRow `json:"entries"`
+}
@@ -0,0 +1,7 @@
+package reqmodel
+
+import "movie_backend_go/db/sqlc"
+
+type IngestLogListResponse struct {
+	IngestLogList []sqlc.IngestLog `json:"ingest_log_list"`
+}
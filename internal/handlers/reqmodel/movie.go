@@ -10,5 +10,7 @@ type MovieUpdateRequest struct {
 	Title *string `json:"title"`
 }
 type MovieListResponse struct {
-	MovieList []sqlc.Movie `json:"movie_list"`
+	Sort      string                 `json:"sort"`
+	MinVotes  int32                  `json:"min_votes"`
+	MovieList []sqlc.GetMovieListRow `json:"movie_list"`
 }
@@ -0,0 +1,20 @@
+package reqmodel
+
+import (
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type MovieAssetCreateRequest struct {
+	// main, trailer, extra, dub or directors_cut
+	Kind     string  `json:"kind"`
+	Language *string `json:"language"`
+	Quality  *string `json:"quality"`
+	Title    *string `json:"title"`
+}
+
+type MovieAssetListResponse struct {
+	MovieID   pgtype.UUID       `json:"movie_id"`
+	AssetList []sqlc.MovieAsset `json:"asset_list"`
+}
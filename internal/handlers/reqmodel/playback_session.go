@@ -0,0 +1,12 @@
+package reqmodel
+
+import (
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type WatchHistoryResponse struct {
+	UserID  pgtype.UUID                   `json:"user_id"`
+	History []sqlc.GetUserWatchHistoryRow `json:"history"`
+}
@@ -34,3 +34,23 @@ type MovieRatingListResponse struct {
 	MovieID         pgtype.UUID                  `json:"movie_id"`
 	MovieRatingList []sqlc.GetMovieRatingListRow `json:"movie_rating_list"`
 }
+
+type RatingHistogramBucket struct {
+	Rating int16 `json:"rating"`
+	Count  int64 `json:"count"`
+}
+
+type MovieRatingStatsResponse struct {
+	MovieID     pgtype.UUID             `json:"movie_id"`
+	AmountRates int64                   `json:"amount_rates"`
+	Average     float64                 `json:"average"`
+	Median      float64                 `json:"median"`
+	StdDev      float64                 `json:"std_dev"`
+	Histogram   []RatingHistogramBucket `json:"histogram"`
+	// Bayesian average pulling movies with few votes to global rating
+	WeightedRating float64 `json:"weighted_rating"`
+	GlobalRating   float64 `json:"global_rating"`
+	MinVotes       int32   `json:"min_votes"`
+	// Last rating change, null for movie without ratings
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
@@ -0,0 +1,22 @@
+package reqmodel
+
+import (
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type MovieRatingTrendResponse struct {
+	MovieID pgtype.UUID                   `json:"movie_id"`
+	Bucket  string                        `json:"bucket"`
+	Since   pgtype.Timestamp              `json:"since"`
+	Trend   []sqlc.GetMovieRatingTrendRow `json:"trend"`
+}
+
+type UserRatingHistoryResponse struct {
+	UserID          pgtype.UUID                      `json:"user_id"`
+	Total           int64                            `json:"total"`
+	Limit           int                              `json:"limit"`
+	Offset          int                              `json:"offset"`
+	RatingEventList []sqlc.GetUserRatingEventListRow `json:"rating_event_list"`
+}
@@ -0,0 +1,34 @@
+package reqmodel
+
+import (
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ReviewCreateRequest struct {
+	MovieID pgtype.UUID `json:"movie_id"`
+	Title   string      `json:"title"`
+	Body    string      `json:"body"`
+	// From 1 to 10, replaces existing rating of the movie
+	Rating int16 `json:"rating"`
+}
+
+type ReviewUpdateRequest struct {
+	Title  *string `json:"title"`
+	Body   *string `json:"body"`
+	Rating *int16  `json:"rating"`
+}
+
+type ReviewVoteRequest struct {
+	Helpful *bool `json:"helpful"`
+}
+
+type MovieReviewListResponse struct {
+	MovieID         pgtype.UUID                  `json:"movie_id"`
+	Total           int64                        `json:"total"`
+	Limit           int                          `json:"limit"`
+	Offset          int                          `json:"offset"`
+	Sort            string                       `json:"sort"`
+	MovieReviewList []sqlc.GetMovieReviewListRow `json:"movie_review_list"`
+}
@@ -0,0 +1,11 @@
+package reqmodel
+
+import "time"
+
+type StreamURLResponse struct {
+	URL     string `json:"url"`
+	DashURL string `json:"dash_url"`
+	// Seek bar previews, signed like media since frames show movie content
+	ThumbnailsURL string    `json:"thumbnails_url"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
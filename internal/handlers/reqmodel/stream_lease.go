@@ -0,0 +1,19 @@
+package reqmodel
+
+import (
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ActiveStreamListResponse struct {
+	UserID     pgtype.UUID                        `json:"user_id"`
+	StreamList []sqlc.GetActiveStreamLeaseListRow `json:"stream_list"`
+}
+
+// StreamLimitErrorResponse is returned when new stream would exceed concurrent streams limit
+type StreamLimitErrorResponse struct {
+	Error         string                             `json:"error"`
+	MaxStreams    int                                `json:"max_streams"`
+	ActiveStreams []sqlc.GetActiveStreamLeaseListRow `json:"active_streams"`
+}
@@ -0,0 +1,12 @@
+package reqmodel
+
+import (
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type MovieSubtitleListResponse struct {
+	MovieID      pgtype.UUID                    `json:"movie_id"`
+	SubtitleList []sqlc.GetMovieSubtitleListRow `json:"subtitle_list"`
+}
@@ -0,0 +1,9 @@
+package reqmodel
+
+import "movie_backend_go/pkg/throttle"
+
+type StreamThrottleStatsResponse struct {
+	throttle.Stats
+	StreamRate      int64 `json:"stream_rate"`
+	AdminStreamRate int64 `json:"admin_stream_rate"`
+}
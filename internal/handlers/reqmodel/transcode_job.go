@@ -0,0 +1,12 @@
+package reqmodel
+
+import (
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type MovieTranscodeJobListResponse struct {
+	MovieID pgtype.UUID         `json:"movie_id"`
+	JobList []sqlc.TranscodeJob `json:"job_list"`
+}
@@ -0,0 +1,6 @@
+package reqmodel
+
+type UserPreferenceUpdateRequest struct {
+	// Hide spoilers in comment lists unless requested otherwise
+	HideSpoilers *bool `json:"hide_spoilers"`
+}
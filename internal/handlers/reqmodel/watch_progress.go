@@ -0,0 +1,26 @@
+package reqmodel
+
+import (
+	"movie_backend_go/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type WatchProgressRequest struct {
+	PositionMs int64 `json:"position_ms"`
+	// Used only when movie duration isn't known yet
+	DurationMs *int64 `json:"duration_ms"`
+	// Movie is started over, watched mark follows position again
+	Rewatch bool `json:"rewatch"`
+}
+
+type WatchProgressSaveResponse struct {
+	// False when heartbeat came sooner than throttle period and was dropped
+	Saved   bool `json:"saved"`
+	Watched bool `json:"watched"`
+}
+
+type ContinueWatchingResponse struct {
+	UserID    pgtype.UUID                       `json:"user_id"`
+	MovieList []sqlc.GetContinueWatchingListRow `json:"movie_list"`
+}
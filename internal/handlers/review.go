@@ -0,0 +1,482 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/crudl"
+	"movie_backend_go/internal/handlers/reqmodel"
+	"movie_backend_go/pkg/auth"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	reviewPageLimit   = 20
+	reviewPageMaxSize = 100
+
+	ReviewSortHelpful = "helpful"
+	ReviewSortNew     = "new"
+)
+
+func validRating(rating int16) bool {
+	return rating >= 1 && rating <= 10
+}
+
+// @Summary 		Get movie reviews
+// @Description	Get page of movie reviews with ratings of their authors. Most helpful go first by default,
+// @Description	helpfulness is helpful votes minus unhelpful ones
+// @Tags        review, movie
+// @Accept      json
+// @Produce     json
+// @Param       movie_id   path		string	true	"Movie ID"
+// @Param       sort   	query	string 	false  "Review order, helpful by default" Enums(helpful, new)
+// @Param       limit   	query	int 	false  "Reviews amount, 20 by default, 100 at most"
+// @Param       offset   	query	int 	false  "Reviews to skip"
+// @Success     200		{object}	reqmodel.MovieReviewListResponse
+// @Failure     400  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /movie/{movie_id}/reviews [get]
+func (ho *HandlerObj) GetMovieReviewListHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	sort := r.URL.Query().Get("sort")
+	if sort == "" {
+		sort = ReviewSortHelpful
+	}
+	if sort != ReviewSortHelpful && sort != ReviewSortNew {
+		http.Error(rw, "sort should be helpful or new", http.StatusBadRequest)
+		return
+	}
+	var err error
+	limit := reviewPageLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			http.Error(rw, "limit should be positive number", http.StatusBadRequest)
+			return
+		}
+		limit = min(limit, reviewPageMaxSize)
+	}
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			http.Error(rw, "offset should be non negative number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	reviewListGet := sqlc.GetMovieReviewListParams{
+		MovieID:     movieID,
+		SortHelpful: sort == ReviewSortHelpful,
+		PageLimit:   int32(limit),
+		PageOffset:  int32(offset),
+	}
+	reviewList, err := crudl.GetMovieReviewList(ctx, ho.QuerierDB, reviewListGet)
+	if err != nil {
+		ho.Logger.Printf("proceed getting movie review list: %v", err)
+		http.Error(rw, "Can't get movie review list", http.StatusNotFound)
+		return
+	}
+	total, err := crudl.CountMovieReviews(ctx, ho.QuerierDB, movieID)
+	if err != nil {
+		ho.Logger.Printf("proceed counting movie reviews: %v", err)
+		http.Error(rw, "Can't get movie review list", http.StatusNotFound)
+		return
+	}
+	if reviewList == nil {
+		reviewList = []sqlc.GetMovieReviewListRow{}
+	}
+
+	reviewListResp := reqmodel.MovieReviewListResponse{
+		MovieID:         movieID,
+		Total:           total,
+		Limit:           limit,
+		Offset:          offset,
+		Sort:            sort,
+		MovieReviewList: reviewList,
+	}
+	writeResponseBody(rw, reviewListResp, "movie review list")
+}
+
+// @Summary 		Get review
+// @Tags        review
+// @Accept      json
+// @Produce     json
+// @Param       review_id   path		string	true	"Review ID"
+// @Success     200		{object}	sqlc.GetReviewRow
+// @Failure     400  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /review/{review_id} [get]
+func (ho *HandlerObj) GetReviewHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var reviewID pgtype.UUID
+	if err := reviewID.Scan(r.PathValue("review_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested review id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	review, err := crudl.GetReview(ctx, ho.QuerierDB, reviewID)
+	if err != nil {
+		ho.Logger.Printf("proceed getting review: %v", err)
+		http.Error(rw, "review not found", http.StatusNotFound)
+		return
+	}
+	writeResponseBody(rw, review, "review")
+}
+
+// @Summary 		Create review
+// @Description	Create review of movie, one per user and movie. Rating of review replaces user rating of the movie
+// @Tags        review
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Param       request   body		reqmodel.ReviewCreateRequest	true	"Review create data"
+// @Success     200		{object}	sqlc.GetReviewRow
+// @Failure     400  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     409  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /review [post]
+func (ho *HandlerObj) CreateReviewHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var reviewReq reqmodel.ReviewCreateRequest
+	err := decoder.Decode(&reviewReq)
+	if err != nil && err != io.EOF {
+		ho.Logger.Printf("proceed body request: %v", err)
+		http.Error(rw, "Can't proceed body request", http.StatusBadRequest)
+		return
+	}
+	reviewReq.Title = strings.TrimSpace(reviewReq.Title)
+	reviewReq.Body = strings.TrimSpace(reviewReq.Body)
+	if reviewReq.Title == "" || reviewReq.Body == "" {
+		http.Error(rw, "title and body are required", http.StatusBadRequest)
+		return
+	}
+	if !validRating(reviewReq.Rating) {
+		http.Error(rw, "rating should be between 1 and 10", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	if _, err := crudl.GetMovie(ctx, ho.QuerierDB, reviewReq.MovieID); err != nil {
+		http.Error(rw, "movie not found", http.StatusNotFound)
+		return
+	}
+
+	reviewCreate := sqlc.CreateReviewParams{
+		UserID:  userTokenData.UserID,
+		MovieID: reviewReq.MovieID,
+		Title:   reviewReq.Title,
+		Body:    reviewReq.Body,
+	}
+	created, err := crudl.CreateReview(ctx, ho.DBPool, reviewCreate, reviewReq.Rating)
+	if errors.Is(err, crudl.ErrReviewExists) {
+		http.Error(rw, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		ho.Logger.Printf("proceed creating review: %v", err)
+		http.Error(rw, "Can't create review", http.StatusInternalServerError)
+		return
+	}
+
+	review, err := crudl.GetReview(ctx, ho.QuerierDB, created.ID)
+	if err != nil {
+		ho.Logger.Printf("proceed getting created review: %v", err)
+		http.Error(rw, "Can't get created review", http.StatusInternalServerError)
+		return
+	}
+	writeResponseBody(rw, review, "review")
+}
+
+// @Summary 		Update review
+// @Description	Update own review, omitted fields keep their values. Rating updates user rating of the movie
+// @Tags        review
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Param       review_id   path		string	true	"Review ID"
+// @Param       request   body		reqmodel.ReviewUpdateRequest	true	"Review update data"
+// @Success     200		{object}	sqlc.GetReviewRow
+// @Failure     400  	{object}  map[string]string
+// @Failure     401  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /review/{review_id} [patch]
+func (ho *HandlerObj) UpdateReviewHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var reviewID pgtype.UUID
+	if err := reviewID.Scan(r.PathValue("review_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested review id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var reviewReq reqmodel.ReviewUpdateRequest
+	err := decoder.Decode(&reviewReq)
+	if err != nil && err != io.EOF {
+		ho.Logger.Printf("proceed body request: %v", err)
+		http.Error(rw, "Can't proceed body request", http.StatusBadRequest)
+		return
+	}
+	for _, field := range []*string{reviewReq.Title, reviewReq.Body} {
+		if field == nil {
+			continue
+		}
+		if *field = strings.TrimSpace(*field); *field == "" {
+			http.Error(rw, "title and body can't be empty", http.StatusBadRequest)
+			return
+		}
+	}
+	if reviewReq.Rating != nil && !validRating(*reviewReq.Rating) {
+		http.Error(rw, "rating should be between 1 and 10", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	review, err := crudl.GetReview(ctx, ho.QuerierDB, reviewID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(rw, "review not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		ho.Logger.Printf("searching review by id - %v: %v", reviewID, err)
+		http.Error(rw, "Can't update review", http.StatusInternalServerError)
+		return
+	}
+	if review.UserID != userTokenData.UserID {
+		ho.Logger.Println("Unauthorized user")
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	reviewUpdate := sqlc.UpdateReviewParams{ID: reviewID, Title: reviewReq.Title, Body: reviewReq.Body, Rating: reviewReq.Rating}
+	if _, err := crudl.UpdateReview(ctx, ho.QuerierDB, reviewUpdate); err != nil {
+		ho.Logger.Printf("proceed updating review: %v", err)
+		http.Error(rw, "Can't update review", http.StatusNotFound)
+		return
+	}
+
+	review, err = crudl.GetReview(ctx, ho.QuerierDB, reviewID)
+	if err != nil {
+		ho.Logger.Printf("proceed getting updated review: %v", err)
+		http.Error(rw, "Can't get updated review", http.StatusInternalServerError)
+		return
+	}
+	writeResponseBody(rw, review, "review")
+}
+
+// @Summary      Delete review
+// @Description  Delete review of author or by admin, rating it was linked to is kept
+// @Tags         review, admin, user
+// @Accept       json
+// @Produce      json
+// @Security 		 OAuth2Password
+// @Param        review_id 	path	string 	true	"Review ID"
+// @Success      204
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /review/{review_id} [delete]
+func (ho *HandlerObj) DeleteReviewHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var reviewID pgtype.UUID
+	if err := reviewID.Scan(r.PathValue("review_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested review id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify token user or admin is owner
+	if !userTokenData.IsAdmin {
+		review, err := crudl.GetReview(ctx, ho.QuerierDB, reviewID)
+		if err != nil {
+			ho.Logger.Printf("searching review by id - %v: %v", reviewID, err)
+			http.Error(rw, "Can't find review with current id", http.StatusNotFound)
+			return
+		}
+		if review.UserID != userTokenData.UserID {
+			ho.Logger.Println("Unauthorized user")
+			http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	if err := crudl.DeleteReview(ctx, ho.QuerierDB, reviewID); err != nil {
+		ho.Logger.Printf("proceed deleting review: %v", err)
+		http.Error(rw, "Can't delete review", http.StatusNotFound)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary 		Vote for review
+// @Description	Mark review of another user helpful or not, new vote replaces previous one
+// @Tags        review
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Param       review_id   path		string	true	"Review ID"
+// @Param       request   body		reqmodel.ReviewVoteRequest	true	"Vote"
+// @Success     200		{object}	sqlc.ReviewVote
+// @Failure     400  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /review/{review_id}/vote [put]
+func (ho *HandlerObj) SetReviewVoteHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var reviewID pgtype.UUID
+	if err := reviewID.Scan(r.PathValue("review_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested review id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var voteReq reqmodel.ReviewVoteRequest
+	err := decoder.Decode(&voteReq)
+	if err != nil && err != io.EOF {
+		ho.Logger.Printf("proceed body request: %v", err)
+		http.Error(rw, "Can't proceed body request", http.StatusBadRequest)
+		return
+	}
+	if voteReq.Helpful == nil {
+		http.Error(rw, "helpful is required", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	review, err := crudl.GetReview(ctx, ho.QuerierDB, reviewID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(rw, "review not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		ho.Logger.Printf("searching review by id - %v: %v", reviewID, err)
+		http.Error(rw, "Can't vote for review", http.StatusInternalServerError)
+		return
+	}
+	if review.UserID == userTokenData.UserID {
+		http.Error(rw, "Own review can't be voted for", http.StatusBadRequest)
+		return
+	}
+
+	voteSet := sqlc.SetReviewVoteParams{ReviewID: reviewID, UserID: userTokenData.UserID, Helpful: *voteReq.Helpful}
+	vote, err := crudl.SetReviewVote(ctx, ho.QuerierDB, voteSet)
+	if err != nil {
+		ho.Logger.Printf("proceed setting review vote: %v", err)
+		http.Error(rw, "Can't vote for review", http.StatusInternalServerError)
+		return
+	}
+	writeResponseBody(rw, vote, "review vote")
+}
+
+// @Summary 		Delete review vote
+// @Description	Remove current user vote for review
+// @Tags        review
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Param       review_id   path		string	true	"Review ID"
+// @Success     204
+// @Failure     400  	{object}  map[string]string
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /review/{review_id}/vote [delete]
+func (ho *HandlerObj) DeleteReviewVoteHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var reviewID pgtype.UUID
+	if err := reviewID.Scan(r.PathValue("review_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested review id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	voteDelete := sqlc.DeleteReviewVoteParams{ReviewID: reviewID, UserID: userTokenData.UserID}
+	err = crudl.DeleteReviewVote(ctx, ho.QuerierDB, voteDelete)
+	if errors.Is(err, crudl.ErrEmptyDeletion) {
+		http.Error(rw, "review vote not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		ho.Logger.Printf("proceed deleting review vote: %v", err)
+		http.Error(rw, "Can't delete review vote", http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/crudl"
+	"movie_backend_go/internal/handlers/reqmodel"
+	"movie_backend_go/pkg/auth"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	DefaultMaxConcurrentStreams = 3
+	// Player should send heartbeat more often than lease expires, once per 30 seconds is enough
+	StreamLeaseTTL        = 2 * time.Minute
+	streamLeaseTimeout    = 5 * time.Second
+	maxDeviceNameLength   = 200
+	unknownDeviceName     = "unknown device"
+	streamDeviceParamName = "device"
+	streamDeviceCookie    = "stream_device"
+	streamDeviceCookieAge = 365 * 24 * time.Hour
+	maxDeviceIDLength     = 64
+)
+
+// deviceName take player provided device name, fallback to User-Agent
+func deviceName(r *http.Request) string {
+	device := r.URL.Query().Get(streamDeviceParamName)
+	if device == "" {
+		device = r.UserAgent()
+	}
+	if device == "" {
+		return unknownDeviceName
+	}
+	if len(device) > maxDeviceNameLength {
+		device = device[:maxDeviceNameLength]
+	}
+	return device
+}
+
+// streamDeviceID return id of player device from cookie, empty when player doesn't send one.
+// Cookie isn't shared together with stream URL, so it tells devices playing the same URL apart
+func streamDeviceID(r *http.Request) string {
+	if cookie, err := r.Cookie(streamDeviceCookie); err == nil && len(cookie.Value) <= maxDeviceIDLength {
+		return cookie.Value
+	}
+	return ""
+}
+
+// setStreamDeviceCookie give device id to client signing stream URL once. Native players and
+// cross-origin requests without credentials never send it back, their leases aren't bound to device
+func setStreamDeviceCookie(rw http.ResponseWriter, r *http.Request) {
+	if streamDeviceID(r) != "" {
+		return
+	}
+	deviceID := rand.Text()
+	http.SetCookie(rw, &http.Cookie{
+		Name:     streamDeviceCookie,
+		Value:    deviceID,
+		Path:     "/stream/",
+		MaxAge:   int(streamDeviceCookieAge.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// acquireStreamLease start or renew lease of stream session, every media request of playback calls it.
+// Session is bound to device cookie while lease is active, other devices sending cookie get 409.
+// Over the limit it writes 429 listing active devices. Return false when response is written.
+// Streams aren't interrupted when lease can't be saved because of database failure
+func (ho *HandlerObj) acquireStreamLease(rw http.ResponseWriter, r *http.Request, userID, movieID pgtype.UUID) bool {
+	if ho.MaxConcurrentStreams <= 0 {
+		return true
+	}
+
+	ctx, close := context.WithTimeout(r.Context(), streamLeaseTimeout)
+	defer close()
+
+	leaseSave := sqlc.SaveStreamLeaseParams{
+		UserID:     userID,
+		MovieID:    movieID,
+		SessionKey: r.URL.Query().Get(auth.StreamSessionParam),
+		Device:     deviceName(r),
+		DeviceID:   streamDeviceID(r),
+		TtlSeconds: int32(StreamLeaseTTL.Seconds()),
+	}
+	_, err := crudl.AcquireStreamLease(ctx, ho.DBPool, leaseSave, ho.MaxConcurrentStreams)
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, crudl.ErrStreamSessionTaken) {
+		http.Error(rw, "Stream URL is played on another device, request new one", http.StatusConflict)
+		return false
+	}
+	if !errors.Is(err, crudl.ErrStreamLimitReached) {
+		ho.Logger.Printf("acquire stream lease: %v", err)
+		return true
+	}
+
+	activeStreams, err := crudl.GetActiveStreamLeaseList(ctx, ho.QuerierDB, userID)
+	if err != nil {
+		ho.Logger.Printf("get active stream list: %v", err)
+	}
+	limitErr := reqmodel.StreamLimitErrorResponse{
+		Error:         "Concurrent streams limit reached, stop playback on one of active devices",
+		MaxStreams:    ho.MaxConcurrentStreams,
+		ActiveStreams: activeStreams,
+	}
+	writeResponseBodyStatus(rw, limitErr, "stream limit error", http.StatusTooManyRequests)
+	return false
+}
+
+// @Summary     Stream heartbeat
+// @Description Renew stream lease of playback session. Stream leases expire in 2 minutes without heartbeats
+// @Tags        video-manager
+// @Produce     json
+// @Param       movie_id 	path	string  true 	"Movie ID"
+// @Param       user_id 	query	string  true 	"User ID from signed URL"
+// @Param       expires 	query	int  	true 	"Unix expiration time from signed URL"
+// @Param       session 	query	string  true 	"Playback session from signed URL"
+// @Param       signature 	query	string  true 	"Signature from signed URL"
+// @Param       device 		query	string  false 	"Device name shown in active streams list, User-Agent by default"
+// @Success     204
+// @Failure     403  {object}  map[string]string
+// @Failure     409  {object}  map[string]string
+// @Failure     429  {object}  reqmodel.StreamLimitErrorResponse
+// @Router      /stream/movie/{movie_id}/heartbeat [post]
+func (ho *HandlerObj) StreamHeartbeatHandler(rw http.ResponseWriter, r *http.Request) {
+	var movieID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(r.Context())
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	if !ho.acquireStreamLease(rw, r, userTokenData.UserID, movieID) {
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary     Release stream lease
+// @Description Free concurrent stream slot when playback is stopped
+// @Tags        video-manager
+// @Produce     json
+// @Param       movie_id 	path	string  true 	"Movie ID"
+// @Param       user_id 	query	string  true 	"User ID from signed URL"
+// @Param       expires 	query	int  	true 	"Unix expiration time from signed URL"
+// @Param       session 	query	string  true 	"Playback session from signed URL"
+// @Param       signature 	query	string  true 	"Signature from signed URL"
+// @Success     204
+// @Failure     403  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Router      /stream/movie/{movie_id}/lease [delete]
+func (ho *HandlerObj) ReleaseStreamLeaseHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	leaseRelease := sqlc.ReleaseStreamLeaseParams{
+		UserID:     userTokenData.UserID,
+		SessionKey: r.URL.Query().Get(auth.StreamSessionParam),
+	}
+	if err := crudl.ReleaseStreamLease(ctx, ho.QuerierDB, leaseRelease); err != nil {
+		ho.Logger.Printf("proceed release stream lease request: %v", err)
+		http.Error(rw, "stream lease not found", http.StatusNotFound)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary     Get my active streams
+// @Description Get devices currently streaming with account
+// @Tags        video-manager, user
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Success     200  {object}  reqmodel.ActiveStreamListResponse
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /user/my/streams [get]
+func (ho *HandlerObj) GetMyActiveStreamListHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	streamList, err := crudl.GetActiveStreamLeaseList(ctx, ho.QuerierDB, userTokenData.UserID)
+	if err != nil {
+		ho.Logger.Printf("proceed getting active stream list: %v", err)
+		http.Error(rw, "Can't get active stream list", http.StatusNotFound)
+		return
+	}
+	streamListResp := reqmodel.ActiveStreamListResponse{UserID: userTokenData.UserID, StreamList: streamList}
+	writeResponseBody(rw, streamListResp, "active stream list")
+}
+
+// @Summary     Stop my stream
+// @Description Drop lease of stream on another device, so its slot can be taken
+// @Tags        video-manager, user
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       lease_id   	path	string 	true  "Stream lease ID"
+// @Success     204
+// @Failure     400  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /user/my/streams/{lease_id} [delete]
+func (ho *HandlerObj) DeleteMyStreamLeaseHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var leaseID pgtype.UUID
+	if err := leaseID.Scan(r.PathValue("lease_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested lease id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	leaseDelete := sqlc.DeleteUserStreamLeaseParams{ID: leaseID, UserID: userTokenData.UserID}
+	if err := crudl.DeleteUserStreamLease(ctx, ho.QuerierDB, leaseDelete); err != nil {
+		ho.Logger.Printf("proceed delete stream lease request: %v", err)
+		http.Error(rw, "Can't stop stream", http.StatusNotFound)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/crudl"
+	"movie_backend_go/internal/handlers/reqmodel"
+	"movie_backend_go/pkg/auth"
+	"movie_backend_go/pkg/subtitle"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	maxSubtitleSize = 5 * 1024 * 1024 // 5MB
+	// Cues may slightly overrun movie end because of rounding in authoring tools
+	subtitleDurationTolerance = 5 * time.Second
+)
+
+// @Summary     Upload movie subtitle
+// @Description Upload SRT or WebVTT track as raw body. SRT is converted to WebVTT, cue timing is validated.
+// @Description Track with the same language and kind is replaced
+// @Tags        subtitle, admin
+// @Accept      plain
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       movie_id   	path	string 	true  	"Movie ID"
+// @Param       language   	query	string 	true  	"BCP 47 language tag, e.g. en or pt-BR"
+// @Param       label   	query	string 	false  	"Track label shown by player"
+// @Param       kind   		query	string 	false  	"subtitles (default) or captions"
+// @Param       request		body	string 	true  	"SRT or WebVTT file"
+// @Success     200  {object}  sqlc.CreateSubtitleRow
+// @Failure     400  {object}  map[string]string
+// @Failure     401  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     413  {object}  map[string]string
+// @Failure     422  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /movie/{movie_id}/subtitle [post]
+func (ho *HandlerObj) UploadSubtitleHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+	// Verify
+	if !userTokenData.IsAdmin {
+		ho.Logger.Println("Unauthorized user")
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	language := query.Get("language")
+	if !subtitle.ValidLanguage(language) {
+		http.Error(rw, "language should be BCP 47 tag, e.g. en or pt-BR", http.StatusBadRequest)
+		return
+	}
+	kind := query.Get("kind")
+	if kind == "" {
+		kind = "subtitles"
+	}
+	if kind != "subtitles" && kind != "captions" {
+		http.Error(rw, "kind should be subtitles or captions", http.StatusBadRequest)
+		return
+	}
+	var label *string
+	if labelStr := query.Get("label"); labelStr != "" {
+		label = &labelStr
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(rw, r.Body, maxSubtitleSize))
+	if err != nil {
+		ho.Logger.Printf("read uploaded subtitle: %v", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(rw, "subtitle file is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(rw, "Can't read subtitle file", http.StatusBadRequest)
+		return
+	}
+
+	track, format, err := subtitle.Parse(data)
+	if err != nil {
+		ho.Logger.Printf("parse uploaded subtitle: %v", err)
+		http.Error(rw, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	movie, err := crudl.GetMovie(ctx, ho.QuerierDB, movieID)
+	if err != nil {
+		ho.Logger.Printf("get movie for subtitle: %v", err)
+		http.Error(rw, "movie not found", http.StatusNotFound)
+		return
+	}
+	// Duration is known only after movie processing
+	if movie.DurationMs != nil {
+		movieDuration := time.Duration(*movie.DurationMs) * time.Millisecond
+		if track.Duration() > movieDuration+subtitleDurationTolerance {
+			http.Error(rw, "subtitle cues run past the end of movie", http.StatusUnprocessableEntity)
+			return
+		}
+	}
+
+	subtitleCreate := sqlc.CreateSubtitleParams{
+		MovieID:      movieID,
+		Language:     language,
+		Label:        label,
+		Kind:         kind,
+		SourceFormat: format,
+		Content:      string(track.WebVTT()),
+	}
+	subtitleRow, err := crudl.CreateSubtitle(ctx, ho.QuerierDB, subtitleCreate)
+	if err != nil {
+		ho.Logger.Printf("proceed creating subtitle: %v", err)
+		http.Error(rw, "Can't save subtitle", http.StatusInternalServerError)
+		return
+	}
+	writeResponseBody(rw, subtitleRow, "subtitle")
+}
+
+// @Summary     Get movie subtitles list
+// @Description Get subtitle and caption tracks available for movie
+// @Tags        subtitle, movie
+// @Accept      json
+// @Produce     json
+// @Param       movie_id   	path	string 	true  "Movie ID"
+// @Success     200  {object}  reqmodel.MovieSubtitleListResponse
+// @Failure     400  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /movie/{movie_id}/subtitle [get]
+func (ho *HandlerObj) GetMovieSubtitleListHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Printf("proceed path parameter: %v", err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	subtitleList, err := crudl.GetMovieSubtitleList(ctx, ho.QuerierDB, movieID)
+	if err != nil {
+		ho.Logger.Printf("proceed getting movie subtitle list: %v", err)
+		http.Error(rw, "Can't get movie subtitle list", http.StatusNotFound)
+		return
+	}
+	subtitleListResp := reqmodel.MovieSubtitleListResponse{MovieID: movieID, SubtitleList: subtitleList}
+	writeResponseBody(rw, subtitleListResp, "movie subtitle list")
+}
+
+// @Summary     Stream movie subtitle
+// @Description Serve WebVTT track for player. Use the same signed query as movie stream URL
+// @Tags        subtitle, video-manager
+// @Produce     text/vtt
+// @Param       movie_id 		path	string  true 	"Movie ID"
+// @Param       subtitle_id 	path	string  true 	"Subtitle ID"
+// @Param       user_id 		query	string  true 	"User ID from signed URL"
+// @Param       expires 		query	int  	true 	"Unix expiration time from signed URL"
+// @Param       session 		query	string  true 	"Playback session from signed URL"
+// @Param       signature 		query	string  true 	"Signature from signed URL"
+// @Success 	200  	{string} 	string
+// @Failure 	400  	{object} 	map[string]string
+// @Failure 	403  	{object} 	map[string]string
+// @Failure 	404  	{object} 	map[string]string
+// @Router     /stream/movie/{movie_id}/subtitle/{subtitle_id} [get]
+func (ho *HandlerObj) StreamSubtitleHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID, subtitleID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+	if err := subtitleID.Scan(r.PathValue("subtitle_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested subtitle id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	subtitleGet := sqlc.GetMovieSubtitleParams{ID: subtitleID, MovieID: movieID}
+	subtitleTrack, err := crudl.GetMovieSubtitle(ctx, ho.QuerierDB, subtitleGet)
+	if err != nil {
+		ho.Logger.Printf("proceed getting subtitle: %v", err)
+		http.Error(rw, "subtitle not found", http.StatusNotFound)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "text/vtt; charset=utf-8")
+	rw.Header().Set("Content-Length", strconv.Itoa(len(subtitleTrack.Content)))
+	if _, err := io.WriteString(rw, subtitleTrack.Content); err != nil {
+		ho.Logger.Printf("write subtitle: %v", err)
+	}
+}
+
+// @Summary     Delete movie subtitle
+// @Tags        subtitle, admin
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       movie_id 		path	string  true 	"Movie ID"
+// @Param       subtitle_id 	path	string  true 	"Subtitle ID"
+// @Success     204
+// @Failure     400  {object}  map[string]string
+// @Failure     401  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /movie/{movie_id}/subtitle/{subtitle_id} [delete]
+func (ho *HandlerObj) DeleteSubtitleHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID, subtitleID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+	if err := subtitleID.Scan(r.PathValue("subtitle_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested subtitle id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+	// Verify
+	if !userTokenData.IsAdmin {
+		ho.Logger.Println("Unauthorized user")
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	subtitleDelete := sqlc.DeleteSubtitleParams{ID: subtitleID, MovieID: movieID}
+	if err := crudl.DeleteSubtitle(ctx, ho.QuerierDB, subtitleDelete); err != nil {
+		ho.Logger.Printf("proceed delete subtitle request: %v", err)
+		http.Error(rw, "Can't delete subtitle", http.StatusNotFound)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"io"
+	"movie_backend_go/internal/handlers/reqmodel"
+	"movie_backend_go/pkg/auth"
+	"net/http"
+)
+
+const (
+	// 4 MiB/s is above bitrate of 4K stream, so playback never stalls but bulk downloads are slowed
+	DefaultStreamRate = 4 << 20
+	// Admins check uploaded media, their connections get more
+	DefaultAdminStreamRate = 16 << 20
+	// Global limit is off by default, it depends on uplink of deployment
+	DefaultGlobalStreamRate = 0
+)
+
+// streamWriter wrap response body writer with connection and global rate limits.
+// Admin rate applies to streams signed for admins. Returned func releases connection
+func (ho *HandlerObj) streamWriter(rw http.ResponseWriter, r *http.Request) (io.Writer, func()) {
+	if ho.StreamLimiter == nil {
+		return rw, func() {}
+	}
+
+	rate := ho.StreamRate
+	if userTokenData, err := auth.GetTokenDataContext(r.Context()); err == nil && userTokenData.IsAdmin {
+		rate = ho.AdminStreamRate
+	}
+	writer := ho.StreamLimiter.Writer(r.Context(), rw, rate)
+	return writer, func() { writer.Close() }
+}
+
+// @Summary     Get stream throttling stats
+// @Description Get counters of streamed and throttled bytes since server start
+// @Tags        video-manager, admin
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Success     200  {object}  reqmodel.StreamThrottleStatsResponse
+// @Failure     401  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /stream/stats [get]
+func (ho *HandlerObj) GetStreamThrottleStatsHandler(rw http.ResponseWriter, r *http.Request) {
+	userTokenData, err := auth.GetTokenDataContext(r.Context())
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	// Verify
+	if !userTokenData.IsAdmin {
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	statsResp := reqmodel.StreamThrottleStatsResponse{
+		StreamRate:      ho.StreamRate,
+		AdminStreamRate: ho.AdminStreamRate,
+	}
+	if ho.StreamLimiter != nil {
+		statsResp.Stats = ho.StreamLimiter.Stats()
+	}
+	writeResponseBody(rw, statsResp, "stream throttle stats")
+}
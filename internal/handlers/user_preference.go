@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/crudl"
+	"movie_backend_go/internal/handlers/reqmodel"
+	"movie_backend_go/pkg/auth"
+	"net/http"
+)
+
+// @Summary 		Get my preferences
+// @Tags        user
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Success     200		{object}	sqlc.UserPreference
+// @Failure     404  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /user/my/preference [get]
+func (ho *HandlerObj) GetMyUserPreferenceHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	preference, err := crudl.GetUserPreference(ctx, ho.QuerierDB, userTokenData.UserID)
+	if err != nil {
+		ho.Logger.Printf("proceed getting user preference: %v", err)
+		http.Error(rw, "Can't get user preference", http.StatusNotFound)
+		return
+	}
+	writeResponseBody(rw, preference, "user preference")
+}
+
+// @Summary 		Update my preferences
+// @Description	Update preferences, omitted fields keep their values
+// @Tags        user
+// @Accept      json
+// @Produce     json
+// @Security 		OAuth2Password
+// @Param       request   body		reqmodel.UserPreferenceUpdateRequest	true	"Preference update data"
+// @Success     200		{object}	sqlc.UserPreference
+// @Failure     400  	{object}  map[string]string
+// @Failure     500  	{object}  map[string]string
+// @Router      /user/my/preference [patch]
+func (ho *HandlerObj) UpdateMyUserPreferenceHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var preferenceReq reqmodel.UserPreferenceUpdateRequest
+	err := decoder.Decode(&preferenceReq)
+	if err != nil && err != io.EOF {
+		ho.Logger.Printf("proceed body request: %v", err)
+		http.Error(rw, "Can't proceed body request", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	preferenceSet := sqlc.SetUserPreferenceParams{UserID: userTokenData.UserID, HideSpoilers: preferenceReq.HideSpoilers}
+	preference, err := crudl.SetUserPreference(ctx, ho.QuerierDB, preferenceSet)
+	if err != nil {
+		ho.Logger.Printf("proceed updating user preference: %v", err)
+		http.Error(rw, "Can't update user preference", http.StatusInternalServerError)
+		return
+	}
+	writeResponseBody(rw, preference, "user preference")
+}
@@ -2,39 +2,46 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/crudl"
+	"movie_backend_go/internal/handlers/reqmodel"
+	"movie_backend_go/internal/media"
+	"movie_backend_go/internal/transcode"
 	"movie_backend_go/pkg/auth"
+	"movie_backend_go/pkg/httprange"
+	"movie_backend_go/pkg/mp4probe"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
-	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-// TODO: make proper pathing
-// Get this path from volume
-
 const (
-	chunkSize     = 10 * 1024 * 1024 // 10MB
-	MOVIES_PREFIX = "/movie-data"
+	chunkSize          = 10 * 1024 * 1024 // 10MB
+	PackageTimeContext = 2 * time.Hour
 )
 
 // NOTE: DownloadMove expect middleware that will handle installation info saving somewhere. We need to know about saving this data
 
 // @Summary     Upload movie
-// @Description Upload movie data as []bytes stream
+// @Description Upload mp4 movie data as []bytes stream. Uploaded file is queued for processing.
+// @Description Files with moov atom at the end are rejected unless remux is requested
 // @Tags        video-manager, admin
 // @Accept 		octet-stream
 // @Produce     json
 // @Security	OAuth2Password
 // @Param       movie_id   	path	string 	true  "Movie ID"
+// @Param       remux   	query	bool 	false  "Accept file with moov atom at the end, processing move it to the beginning"
 // @Param       tequest		body	[]byte 	true  "Streaming Bytes"
-// @Success     204
+// @Success     202  {object}  sqlc.TranscodeJob
 // @Failure     404  {object}  map[string]string
+// @Failure     422  {object}  map[string]string
 // @Failure     500  {object}  map[string]string
 // @Router      /upload/movie/{movie_id} [post]
 func (ho *HandlerObj) UploadMovie(rw http.ResponseWriter, r *http.Request) {
@@ -63,44 +70,351 @@ func (ho *HandlerObj) UploadMovie(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	moviePath := filepath.Join(MOVIES_PREFIX, movieIDStr+".mp4")
+	uploadPath := media.UploadFilePath(movieIDStr)
+	if err := os.MkdirAll(filepath.Dir(uploadPath), 0o755); err != nil {
+		ho.Logger.Printf("create upload directory: %v", err)
+		http.Error(rw, "Can't create movie file", http.StatusInternalServerError)
+		return
+	}
 
-	file, err := os.Open(moviePath)
+	file, err := os.Create(uploadPath)
 	if err != nil {
-		http.Error(rw, "video not found", http.StatusNotFound)
+		ho.Logger.Printf("create movie file: %v", err)
+		http.Error(rw, "Can't create movie file", http.StatusInternalServerError)
 		return
 	}
 	defer file.Close()
 
-	// TODO: make transaction and write movie_path before operation itself, submit operation after success???
-	io.Copy(file, r.Body)
+	if _, err := io.Copy(file, r.Body); err != nil {
+		ho.Logger.Printf("write uploaded movie %s: %v", movieIDStr, err)
+		os.Remove(uploadPath)
+		http.Error(rw, "Can't write uploaded movie", http.StatusInternalServerError)
+		return
+	}
 
-	moviePathAdd := sqlc.AddMoviePathParams{ID: movieID, MoviePath: &moviePath}
-	_, err = ho.QuerierDB.AddMoviePath(ctx, moviePathAdd)
+	// Verify uploaded file layout
+	uploadInfo, err := mp4probe.ProbeFile(uploadPath)
 	if err != nil {
-		ho.Logger.Printf("Can't write downloaded function path into movie table. Delete downloaded file: %v", moviePathAdd)
-		err = os.Remove(moviePath)
-		if err != nil {
-			ho.Logger.Printf("!!CAN't delete downloaded video %v", moviePathAdd)
+		ho.Logger.Printf("probe uploaded movie %s: %v", movieIDStr, err)
+		os.Remove(uploadPath)
+		http.Error(rw, "Uploaded file isn't valid mp4", http.StatusUnprocessableEntity)
+		return
+	}
+	if !uploadInfo.FastStart && r.URL.Query().Get("remux") != "true" {
+		ho.Logger.Printf("uploaded movie %s has moov atom at the end", movieIDStr)
+		os.Remove(uploadPath)
+		http.Error(rw, "Uploaded file has moov atom at the end, upload faststart file or set remux=true", http.StatusUnprocessableEntity)
+		return
+	}
+
+	// Movie path is saved by transcode worker after processing
+	jobCreate := sqlc.CreateTranscodeJobParams{MovieID: movieID, Kind: transcode.KindTranscode}
+	job, err := crudl.CreateTranscodeJob(ctx, ho.QuerierDB, jobCreate)
+	if err != nil {
+		ho.Logger.Printf("enqueue transcode job for movie %s: %v", movieIDStr, err)
+		if err := os.Remove(uploadPath); err != nil {
+			ho.Logger.Printf("!!CAN't delete uploaded video %s", uploadPath)
 		}
+		http.Error(rw, "Can't enqueue movie processing", http.StatusInternalServerError)
+		return
+	}
+
+	writeResponseBodyStatus(rw, job, "transcode job", http.StatusAccepted)
+}
+
+// @Summary     Get movie processing jobs
+// @Description Get transcoding jobs of movie with status and progress
+// @Tags        video-manager, admin
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       movie_id   	path	string 	true  "Movie ID"
+// @Success     200  {object}  reqmodel.MovieTranscodeJobListResponse
+// @Failure     401  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /movie/{movie_id}/jobs [get]
+func (ho *HandlerObj) GetMovieTranscodeJobListHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+	// Verify
+	if !userTokenData.IsAdmin {
+		ho.Logger.Println("Unauthorized user")
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	jobList, err := crudl.GetMovieTranscodeJobList(ctx, ho.QuerierDB, movieID)
+	if err != nil {
+		ho.Logger.Printf("get movie transcode job list: %v", err)
+		http.Error(rw, "Can't get movie job list", http.StatusNotFound)
+		return
+	}
+	jobListResp := reqmodel.MovieTranscodeJobListResponse{MovieID: movieID, JobList: jobList}
+	writeResponseBody(rw, jobListResp, "movie job list")
+}
+
+// packageDash build DASH package next to the progressive file. Run it in background, packaging is long
+func (ho *HandlerObj) packageDash(movieIDStr string) {
+	ctx, close := context.WithTimeout(context.Background(), PackageTimeContext)
+	defer close()
+
+	if err := media.PackageDash(ctx, media.MovieFilePath(movieIDStr), media.DashDir(movieIDStr)); err != nil {
+		ho.Logger.Printf("package dash for movie %s: %v", movieIDStr, err)
+		return
+	}
+	ho.Logger.Printf("dash package for movie %s is ready", movieIDStr)
+}
+
+// @Summary     Package movie into DASH
+// @Description Rebuild DASH manifest and segments from uploaded movie in background
+// @Tags        video-manager, admin
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       movie_id   	path	string 	true  "Movie ID"
+// @Success     202
+// @Failure     401  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /movie/{movie_id}/dash [post]
+func (ho *HandlerObj) PackageMovieDashHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	movieIDStr := r.PathValue("movie_id")
+	var movieID pgtype.UUID
+	if err := movieID.Scan(movieIDStr); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+	// Verify
+	if !userTokenData.IsAdmin {
+		ho.Logger.Println("Unauthorized user")
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
+		return
+	}
+
+	if _, err := os.Stat(media.MovieFilePath(movieIDStr)); err != nil {
+		ho.Logger.Printf("stat movie %s: %v", movieIDStr, err)
+		http.Error(rw, "video not found", http.StatusNotFound)
+		return
+	}
+
+	go ho.packageDash(movieIDStr)
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+// @Summary     Sign movie stream URL
+// @Description Mint time-limited stream URLs bound to user and movie. Players can't send Authorization header,
+// @Description so stream endpoints verify URL signature instead. Every URL is new playback session taking
+// @Description one concurrent stream slot. Response sets device cookie, session is played by one device sending it at a time
+// @Tags        video-manager
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       movie_id   	path	string 	true  "Movie ID"
+// @Success     200  {object}  reqmodel.StreamURLResponse
+// @Failure     400  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /stream/movie/{movie_id}/sign [post]
+func (ho *HandlerObj) SignStreamURLHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	movieIDStr := r.PathValue("movie_id")
+	var movieID pgtype.UUID
+	if err := movieID.Scan(movieIDStr); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := crudl.GetMovie(ctx, ho.QuerierDB, movieID); err != nil {
+		ho.Logger.Printf("get movie %s for stream signing: %v", movieIDStr, err)
+		http.Error(rw, "movie not found", http.StatusNotFound)
+		return
+	}
+
+	setStreamDeviceCookie(rw, r)
+	query, expiresAt := auth.StreamQuery(userTokenData.UserID, movieID, userTokenData.IsAdmin)
+	streamPath := "/stream/movie/" + movieIDStr
+	streamURL := reqmodel.StreamURLResponse{
+		URL:           streamPath + "?" + query.Encode(),
+		DashURL:       streamPath + "/dash/" + media.DASH_MANIFEST + "?" + query.Encode(),
+		ThumbnailsURL: "/movie/" + movieIDStr + "/thumbnails/" + media.THUMBNAILS_VTT + "?" + query.Encode(),
+		ExpiresAt:     expiresAt,
 	}
-	rw.WriteHeader(http.StatusNoContent)
+	writeResponseBody(rw, streamURL, "stream url")
+}
+
+// @Summary     Stream movie DASH manifest
+// @Tags        video-manager
+// @Accept      json
+// @Produce     application/dash+xml
+// @Description Segment references of manifest carry the same signed query
+// @Param       movie_id 	path	string  true 	"Movie ID"
+// @Param       user_id 	query	string  true 	"User ID from signed URL"
+// @Param       expires 	query	int  	true 	"Unix expiration time from signed URL"
+// @Param       session 	query	string  true 	"Playback session from signed URL"
+// @Param       signature 	query	string  true 	"Signature from signed URL"
+// @Success 	200  	{string} 	string
+// @Failure 	403  	{object} 	map[string]string
+// @Failure 	404  	{object} 	map[string]string
+// @Failure 	409  	{object} 	map[string]string
+// @Failure 	429  	{object} 	reqmodel.StreamLimitErrorResponse
+// @Failure 	500  	{object} 	map[string]string
+// @Router     /stream/movie/{movie_id}/dash/manifest.mpd [get]
+func (ho *HandlerObj) StreamMovieDashManifest(rw http.ResponseWriter, r *http.Request) {
+	movieIDStr := r.PathValue("movie_id")
+	var movieID pgtype.UUID
+	if err := movieID.Scan(movieIDStr); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	manifestPath := filepath.Join(media.DashDir(movieIDStr), media.DASH_MANIFEST)
+	manifest, err := os.ReadFile(manifestPath)
+	if err != nil {
+		http.Error(rw, "dash manifest not found", http.StatusNotFound)
+		return
+	}
+	userTokenData, err := auth.GetTokenDataContext(r.Context())
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+	if !ho.acquireStreamLease(rw, r, userTokenData.UserID, movieID) {
+		return
+	}
+	// Manifest is fetched once per playback
+	ho.recordPlayback(r, movieID)
+
+	manifest = media.SignDashManifest(manifest, r.URL.RawQuery)
+
+	rw.Header().Set("Content-Type", "application/dash+xml")
+	rw.Header().Set("Content-Length", strconv.Itoa(len(manifest)))
+	if _, err := rw.Write(manifest); err != nil {
+		ho.Logger.Printf("write dash manifest: %v", err)
+	}
+}
+
+// @Summary     Stream movie DASH segment
+// @Tags        video-manager
+// @Accept      json
+// @Produce     video/iso.segment
+// @Param       movie_id 	path	string  true 	"Movie ID"
+// @Param       segment 	path	string  true 	"Segment name from manifest"
+// @Param       user_id 	query	string  true 	"User ID from signed URL"
+// @Param       expires 	query	int  	true 	"Unix expiration time from signed URL"
+// @Param       session 	query	string  true 	"Playback session from signed URL"
+// @Param       signature 	query	string  true 	"Signature from signed URL"
+// @Success 	200  	{object} 	[]byte
+// @Failure 	403  	{object} 	map[string]string
+// @Failure 	404  	{object} 	map[string]string
+// @Failure 	409  	{object} 	map[string]string
+// @Failure 	429  	{object} 	reqmodel.StreamLimitErrorResponse
+// @Failure 	500  	{object} 	map[string]string
+// @Router     /stream/movie/{movie_id}/dash/{segment} [get]
+func (ho *HandlerObj) StreamMovieDashSegment(rw http.ResponseWriter, r *http.Request) {
+	movieIDStr := r.PathValue("movie_id")
+	var movieID pgtype.UUID
+	if err := movieID.Scan(movieIDStr); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	segment := r.PathValue("segment")
+	if !media.IsDashSegmentName(segment) {
+		http.Error(rw, "segment not found", http.StatusNotFound)
+		return
+	}
+
+	file, err := os.Open(filepath.Join(media.DashDir(movieIDStr), segment))
+	if err != nil {
+		http.Error(rw, "segment not found", http.StatusNotFound)
+		return
+	}
+	defer file.Close()
+
+	userTokenData, err := auth.GetTokenDataContext(r.Context())
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+	if !ho.acquireStreamLease(rw, r, userTokenData.UserID, movieID) {
+		return
+	}
+
+	ho.serveMedia(rw, r, file, "video/iso.segment")
 }
 
 // @Summary     Stream movie
+// @Description Progressive stream with RFC 7233 ranges (suffix and multiple ranges) and conditional requests.
+// @Description Request starting playback is recorded into watch history
 // @Tags        video-manager
 // @Accept      json
 // @Produce     video/mp4
-// @Param       movie_id 	path	string  true 	"Movie ID"
-// @Param 		Range 		header 	string 	false 	"Byte range"
-// @Header 		200  	{string} 	Accept-Ranges 	"bytes"
-// @Header 		200  	{string} 	Content-Type 	"video/mp4"
-// @Header 		200  	{int} 		Content-Lenght 	200
-// @Header 		200  	{string} 	Content-Range 	"bytes 1024-10112"
+// @Produce     multipart/byteranges
+// @Param       movie_id 			path	string  true 	"Movie ID"
+// @Param 		Range 				header 	string 	false 	"Byte ranges, e.g. bytes=0-499,-500"
+// @Param 		If-Range 			header 	string 	false 	"ETag or date, Range is ignored when representation changed"
+// @Param 		If-None-Match 		header 	string 	false 	"ETag list"
+// @Param 		If-Modified-Since 	header 	string 	false 	"HTTP date"
+// @Param       user_id 			query	string  true 	"User ID from signed URL"
+// @Param       expires 			query	int  	true 	"Unix expiration time from signed URL"
+// @Param       session 			query	string  true 	"Playback session from signed URL"
+// @Param       signature 			query	string  true 	"Signature from signed URL"
+// @Header 		200,206  	{string} 	Accept-Ranges 	"bytes"
+// @Header 		200,206  	{string} 	ETag 			"Strong validator"
+// @Header 		200,206  	{string} 	Last-Modified 	"HTTP date"
+// @Header 		200,206  	{int} 		Content-Length 	200
+// @Header 		206  		{string} 	Content-Range 	"bytes 1024-10112/20000"
 // @Success 	200  	{object} 	[]byte
+// @Success 	206  	{object} 	[]byte
+// @Success 	304
+// @Failure 	403  	{object} 	map[string]string
 // @Failure 	404  	{object} 	map[string]string
+// @Failure 	409  	{object} 	map[string]string
+// @Failure 	416  	{object} 	map[string]string
+// @Failure 	429  	{object} 	reqmodel.StreamLimitErrorResponse
 // @Failure 	500  	{object} 	map[string]string
 // @Router     /stream/movie/{movie_id} [get]
+// @Router     /stream/movie/{movie_id} [head]
 func (ho *HandlerObj) StreamMovie(rw http.ResponseWriter, r *http.Request) {
 	movieIDStr := r.PathValue("movie_id")
 	var movieID pgtype.UUID
@@ -111,7 +425,7 @@ func (ho *HandlerObj) StreamMovie(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	moviePath := filepath.Join(MOVIES_PREFIX, movieIDStr+".mp4")
+	moviePath := media.MovieFilePath(movieIDStr)
 
 	file, err := os.Open(moviePath)
 	if err != nil {
@@ -120,83 +434,240 @@ func (ho *HandlerObj) StreamMovie(rw http.ResponseWriter, r *http.Request) {
 	}
 	defer file.Close()
 
+	userTokenData, err := auth.GetTokenDataContext(r.Context())
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+	// Every request renews lease, so resumed playback and requests after lease lapse are counted too
+	if !ho.acquireStreamLease(rw, r, userTokenData.UserID, movieID) {
+		return
+	}
+	// Session is recorded by its first request whatever range it asks, so resumed playback is counted too
+	if r.Method == http.MethodGet {
+		ho.recordPlayback(r, movieID)
+	}
+	ho.serveMedia(rw, r, file, "video/mp4")
+}
+
+// serveMedia write file honoring conditional and range requests, HEAD requests get headers only
+func (ho *HandlerObj) serveMedia(rw http.ResponseWriter, r *http.Request, file *os.File, contentType string) {
 	stat, err := file.Stat()
 	if err != nil {
 		http.Error(rw, "cannot stat file", http.StatusInternalServerError)
 		return
 	}
 	size := stat.Size()
+	modTime := stat.ModTime()
+	etag := httprange.ETag(size, modTime)
 
 	rw.Header().Set("Accept-Ranges", "bytes")
-	rw.Header().Set("Content-Type", "video/mp4")
+	rw.Header().Set("ETag", etag)
+	rw.Header().Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
 
+	if httprange.NotModified(r, etag, modTime) {
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	var ranges []httprange.Range
 	rangeHdr := r.Header.Get("Range")
-	if rangeHdr == "" {
-		// TODO: handle videos >30s. in my case they are videos with 30+minutes
+	if rangeHdr != "" && httprange.RangeApplies(r, etag, modTime) {
+		ranges, err = httprange.Parse(rangeHdr, size)
+		// Invalid header and unknown range unit are ignored by RFC 7233, whole file is served
+		if errors.Is(err, httprange.ErrInvalid) {
+			ranges = nil
+		} else if err != nil {
+			rw.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+			http.Error(rw, "range not satisfiable", http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+	}
+
+	body, closeBody := ho.streamWriter(rw, r)
+	defer closeBody()
+
+	switch len(ranges) {
+	case 0:
 		// No Range header: serve the whole file (200 OK)
+		rw.Header().Set("Content-Type", contentType)
 		rw.Header().Set("Content-Length", strconv.FormatInt(size, 10))
-		if _, err := io.Copy(rw, file); err != nil {
+		rw.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if _, err := io.Copy(body, file); err != nil {
 			ho.Logger.Printf("copy full file: %v", err)
 		}
+	case 1:
+		rng := ranges[0]
+		// No end provided: mimic the Python version's behavior (chunked)
+		if rng.OpenEnded && rng.Length > chunkSize {
+			rng.Length = chunkSize
+		}
+
+		// Prepare headers for 206 Partial Content
+		rw.Header().Set("Content-Type", contentType)
+		rw.Header().Set("Content-Range", rng.ContentRange(size))
+		rw.Header().Set("Content-Length", strconv.FormatInt(rng.Length, 10))
+		rw.WriteHeader(http.StatusPartialContent)
+		if r.Method == http.MethodHead {
+			return
+		}
+
+		// Seek and write exactly the requested bytes
+		if _, err := file.Seek(rng.Start, io.SeekStart); err != nil {
+			ho.Logger.Printf("seek error: %v", err)
+			return
+		}
+		if _, err := io.CopyN(body, file, rng.Length); err != nil {
+			// Client may cancel early; just log
+			ho.Logger.Printf("copyN error: %v", err)
+		}
+	default:
+		multipartBody := httprange.NewMultipart(ranges, contentType, size)
+		rw.Header().Set("Content-Type", multipartBody.ContentType())
+		rw.Header().Set("Content-Length", strconv.FormatInt(multipartBody.Length(), 10))
+		rw.WriteHeader(http.StatusPartialContent)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if _, err := multipartBody.WriteTo(body, file); err != nil {
+			ho.Logger.Printf("write multipart ranges: %v", err)
+		}
+	}
+}
+
+// @Summary     Generate movie thumbnails
+// @Description Queue extraction of seek bar preview sprites and thumbnails track. Movie should be processed already
+// @Tags        video-manager, admin
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       movie_id   	path	string 	true  "Movie ID"
+// @Success     202  {object}  sqlc.TranscodeJob
+// @Failure     400  {object}  map[string]string
+// @Failure     401  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /movie/{movie_id}/thumbnails [post]
+func (ho *HandlerObj) GenerateMovieThumbnailsHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	movieIDStr := r.PathValue("movie_id")
+	var movieID pgtype.UUID
+	if err := movieID.Scan(movieIDStr); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
 		return
 	}
 
-	// Expect formats like: "bytes=START-" or "bytes=START-END"
-	if !strings.HasPrefix(strings.ToLower(rangeHdr), "bytes=") {
-		http.Error(rw, "invalid range", http.StatusRequestedRangeNotSatisfiable)
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
 		return
 	}
-	rangeSpec := strings.TrimPrefix(rangeHdr, "bytes=")
-	parts := strings.Split(rangeSpec, "-")
-	if len(parts) != 2 || parts[0] == "" {
-		http.Error(rw, "invalid range", http.StatusRequestedRangeNotSatisfiable)
+	// Verify
+	if !userTokenData.IsAdmin {
+		ho.Logger.Println("Unauthorized user")
+		http.Error(rw, "Unauthorized user", http.StatusUnauthorized)
 		return
 	}
 
-	start, err := strconv.ParseInt(parts[0], 10, 64)
-	if err != nil || start < 0 {
-		http.Error(rw, "invalid range start", http.StatusRequestedRangeNotSatisfiable)
+	if _, err := os.Stat(media.MovieFilePath(movieIDStr)); err != nil {
+		ho.Logger.Printf("stat movie %s: %v", movieIDStr, err)
+		http.Error(rw, "video not found", http.StatusNotFound)
 		return
 	}
 
-	// Compute end (inclusive)
-	var end int64
-	if parts[1] == "" {
-		// No end provided: mimic the Python version's behavior (chunked)
-		end = start + chunkSize - 1
-	} else {
-		end, err = strconv.ParseInt(parts[1], 10, 64)
-		if err != nil || end < start {
-			http.Error(rw, "invalid range end", http.StatusRequestedRangeNotSatisfiable)
-			return
-		}
+	jobCreate := sqlc.CreateTranscodeJobParams{MovieID: movieID, Kind: transcode.KindThumbnails}
+	job, err := crudl.CreateTranscodeJob(ctx, ho.QuerierDB, jobCreate)
+	if err != nil {
+		ho.Logger.Printf("enqueue thumbnails job for movie %s: %v", movieIDStr, err)
+		http.Error(rw, "Can't enqueue thumbnails generation", http.StatusInternalServerError)
+		return
 	}
 
-	// Clamp to file size - 1
-	if start >= size {
-		// Invalid range: start beyond EOF
-		rw.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", size))
-		http.Error(rw, "range not satisfiable", http.StatusRequestedRangeNotSatisfiable)
+	writeResponseBodyStatus(rw, job, "thumbnails job", http.StatusAccepted)
+}
+
+// @Summary     Get movie thumbnails track
+// @Description WebVTT track with seek bar previews. Each cue points to frame inside sprite as sprite-001.jpg#xywh=x,y,w,h,
+// @Description sprite references carry the same signed query as track
+// @Tags        video-manager, movie
+// @Produce     text/vtt
+// @Param       movie_id 	path	string  true 	"Movie ID"
+// @Param       user_id 	query	string  true 	"User ID from signed URL"
+// @Param       expires 	query	int  	true 	"Unix expiration time from signed URL"
+// @Param       session 	query	string  true 	"Playback session from signed URL"
+// @Param       signature 	query	string  true 	"Signature from signed URL"
+// @Success 	200  	{string} 	string
+// @Failure 	400  	{object} 	map[string]string
+// @Failure 	403  	{object} 	map[string]string
+// @Failure 	404  	{object} 	map[string]string
+// @Router     /movie/{movie_id}/thumbnails/thumbnails.vtt [get]
+func (ho *HandlerObj) GetMovieThumbnailsTrack(rw http.ResponseWriter, r *http.Request) {
+	movieIDStr := r.PathValue("movie_id")
+	var movieID pgtype.UUID
+	if err := movieID.Scan(movieIDStr); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
 		return
 	}
-	if end >= size {
-		end = size - 1
+
+	vtt, err := os.ReadFile(filepath.Join(media.ThumbnailsDir(movieIDStr), media.THUMBNAILS_VTT))
+	if err != nil {
+		http.Error(rw, "thumbnails not found", http.StatusNotFound)
+		return
 	}
+	vtt = media.SignThumbnailsVTT(vtt, r.URL.RawQuery)
 
-	length := end - start + 1
+	rw.Header().Set("Content-Type", "text/vtt; charset=utf-8")
+	rw.Header().Set("Content-Length", strconv.Itoa(len(vtt)))
+	if _, err := rw.Write(vtt); err != nil {
+		ho.Logger.Printf("write thumbnails track: %v", err)
+	}
+}
 
-	// Prepare headers for 206 Partial Content
-	rw.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, size))
-	rw.Header().Set("Content-Length", strconv.FormatInt(length, 10))
-	rw.WriteHeader(http.StatusPartialContent)
+// @Summary     Get movie thumbnails sprite
+// @Tags        video-manager, movie
+// @Produce     image/jpeg
+// @Param       movie_id 	path	string  true 	"Movie ID"
+// @Param       sprite 		path	string  true 	"Sprite name from thumbnails track"
+// @Param       user_id 	query	string  true 	"User ID from signed URL"
+// @Param       expires 	query	int  	true 	"Unix expiration time from signed URL"
+// @Param       session 	query	string  true 	"Playback session from signed URL"
+// @Param       signature 	query	string  true 	"Signature from signed URL"
+// @Success 	200  	{object} 	[]byte
+// @Success 	304
+// @Failure 	400  	{object} 	map[string]string
+// @Failure 	403  	{object} 	map[string]string
+// @Failure 	404  	{object} 	map[string]string
+// @Router     /movie/{movie_id}/thumbnails/{sprite} [get]
+func (ho *HandlerObj) GetMovieThumbnailsSprite(rw http.ResponseWriter, r *http.Request) {
+	movieIDStr := r.PathValue("movie_id")
+	var movieID pgtype.UUID
+	if err := movieID.Scan(movieIDStr); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
 
-	// Seek and write exactly the requested bytes
-	if _, err := file.Seek(start, io.SeekStart); err != nil {
-		ho.Logger.Printf("seek error: %v", err)
+	sprite := r.PathValue("sprite")
+	if !media.IsThumbnailSpriteName(sprite) {
+		http.Error(rw, "sprite not found", http.StatusNotFound)
 		return
 	}
-	if _, err := io.CopyN(rw, file, length); err != nil {
-		// Client may cancel early; just log
-		ho.Logger.Printf("copyN error: %v", err)
+
+	file, err := os.Open(filepath.Join(media.ThumbnailsDir(movieIDStr), sprite))
+	if err != nil {
+		http.Error(rw, "sprite not found", http.StatusNotFound)
+		return
 	}
+	defer file.Close()
+
+	ho.serveMedia(rw, r, file, "image/jpeg")
 }
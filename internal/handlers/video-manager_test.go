@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestServeMediaRange(t *testing.T) {
+	const content = "0123456789"
+	path := filepath.Join(t.TempDir(), "movie.mp4")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ho := &HandlerObj{Logger: log.New(io.Discard, "", 0)}
+
+	tests := []struct {
+		rangeHdr string
+		status   int
+		body     string
+	}{
+		{"", http.StatusOK, content},
+		{"bytes=2-4", http.StatusPartialContent, "234"},
+		{"bytes=20-", http.StatusRequestedRangeNotSatisfiable, ""},
+		// Unknown unit and malformed header are ignored
+		{"items=0-1", http.StatusOK, content},
+		{"bytes=4-2", http.StatusOK, content},
+		{"bytes=a-b", http.StatusOK, content},
+	}
+	for _, tt := range tests {
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest(http.MethodGet, "/stream/movie", nil)
+		if tt.rangeHdr != "" {
+			r.Header.Set("Range", tt.rangeHdr)
+		}
+		rw := httptest.NewRecorder()
+		ho.serveMedia(rw, r, file, "video/mp4")
+		file.Close()
+
+		if rw.Code != tt.status {
+			t.Errorf("Range %q: status = %d, want %d", tt.rangeHdr, rw.Code, tt.status)
+			continue
+		}
+		if tt.body != "" && rw.Body.String() != tt.body {
+			t.Errorf("Range %q: body = %q, want %q", tt.rangeHdr, rw.Body.String(), tt.body)
+		}
+	}
+}
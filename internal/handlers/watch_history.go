@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/crudl"
+	"movie_backend_go/internal/handlers/reqmodel"
+	"movie_backend_go/pkg/auth"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// Requests of the same playback session within window are counted as one view
+	PlaybackSessionWindow = 30 * time.Minute
+	recordPlaybackTimeout = 5 * time.Second
+	watchHistoryLimit     = 50
+	watchHistoryMaxSize   = 500
+)
+
+// recordPlayback save playback session of signed stream request. Session of signed URL is minted
+// per playback, so it is used as session key. Further requests of session only touch it.
+// Stream is served even when recording fails
+func (ho *HandlerObj) recordPlayback(r *http.Request, movieID pgtype.UUID) {
+	userTokenData, err := auth.GetTokenDataContext(r.Context())
+	if err != nil {
+		ho.Logger.Println(err)
+		return
+	}
+
+	ctx, close := context.WithTimeout(r.Context(), recordPlaybackTimeout)
+	defer close()
+
+	sessionRecord := sqlc.RecordPlaybackSessionParams{
+		UserID:        userTokenData.UserID,
+		MovieID:       movieID,
+		SessionKey:    r.URL.Query().Get(auth.StreamSessionParam),
+		WindowSeconds: int32(PlaybackSessionWindow.Seconds()),
+	}
+	if err := crudl.RecordPlaybackSession(ctx, ho.DBPool, sessionRecord); err != nil {
+		ho.Logger.Printf("record playback session: %v", err)
+	}
+}
+
+// @Summary     Get my watch history
+// @Description Get playback sessions of current user, most recent first
+// @Tags        watch-history, user
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       limit   	query	int 	false  "Max entries amount, 50 by default, 500 at most"
+// @Success     200  {object}  reqmodel.WatchHistoryResponse
+// @Failure     400  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /user/my/history [get]
+func (ho *HandlerObj) GetMyWatchHistoryHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	limit := watchHistoryLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			http.Error(rw, "limit should be positive number", http.StatusBadRequest)
+			return
+		}
+		limit = min(limit, watchHistoryMaxSize)
+	}
+
+	historyGet := sqlc.GetUserWatchHistoryParams{UserID: userTokenData.UserID, Limit: int32(limit)}
+	history, err := crudl.GetUserWatchHistory(ctx, ho.QuerierDB, historyGet)
+	if err != nil {
+		ho.Logger.Printf("proceed getting watch history: %v", err)
+		http.Error(rw, "Can't get watch history", http.StatusNotFound)
+		return
+	}
+	historyResp := reqmodel.WatchHistoryResponse{UserID: userTokenData.UserID, History: history}
+	writeResponseBody(rw, historyResp, "watch history")
+}
+
+// @Summary     Delete my watch history entry
+// @Description Remove playback session from history. View stays counted anonymously
+// @Tags        watch-history, user
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       session_id   	path	string 	true  "Playback session ID"
+// @Success     204
+// @Failure     400  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /user/my/history/{session_id} [delete]
+func (ho *HandlerObj) DeleteMyWatchHistoryEntryHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var sessionID pgtype.UUID
+	if err := sessionID.Scan(r.PathValue("session_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested session id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	entryRemove := sqlc.RemoveUserWatchHistoryEntryParams{ID: sessionID, UserID: userTokenData.UserID}
+	if err := crudl.RemoveUserWatchHistoryEntry(ctx, ho.QuerierDB, entryRemove); err != nil {
+		ho.Logger.Printf("proceed delete watch history entry request: %v", err)
+		http.Error(rw, "Can't delete watch history entry", http.StatusNotFound)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary     Clear my watch history
+// @Description Remove every playback session from history. Views stay counted anonymously
+// @Tags        watch-history, user
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Success     204
+// @Failure     500  {object}  map[string]string
+// @Router      /user/my/history [delete]
+func (ho *HandlerObj) ClearMyWatchHistoryHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	if err := crudl.ClearUserWatchHistory(ctx, ho.QuerierDB, userTokenData.UserID); err != nil {
+		ho.Logger.Printf("proceed clear watch history request: %v", err)
+		http.Error(rw, "Can't clear watch history", http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
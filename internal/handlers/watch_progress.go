@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/crudl"
+	"movie_backend_go/internal/handlers/reqmodel"
+	"movie_backend_go/pkg/auth"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// Part of movie after which it is marked watched, end credits are usually skipped
+	WatchedThreshold = 0.9
+	// Players report position every few seconds, storing each heartbeat is useless
+	WatchProgressThrottle   = 10 * time.Second
+	continueWatchingLimit   = 20
+	continueWatchingMaxSize = 100
+)
+
+// @Summary     Save watch progress
+// @Description Report playback position heartbeat. Heartbeats sooner than 10 seconds after the saved one are dropped.
+// @Description Movie is marked watched once position is past 90% of duration, later heartbeats keep the mark.
+// @Description Rewatch heartbeat clears the mark when position is before 90%. Heartbeats setting the mark and rewatch ones are never dropped
+// @Tags        watch-progress, movie
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       movie_id   	path	string 	true  "Movie ID"
+// @Param       request   	body	reqmodel.WatchProgressRequest	true	"Playback position"
+// @Success     200  {object}  reqmodel.WatchProgressSaveResponse
+// @Failure     400  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /movie/{movie_id}/progress [put]
+func (ho *HandlerObj) SaveWatchProgressHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var progressReq reqmodel.WatchProgressRequest
+	if err := decoder.Decode(&progressReq); err != nil {
+		ho.Logger.Printf("proceed body request: %v", err)
+		http.Error(rw, "Can't proceed body request", http.StatusBadRequest)
+		return
+	}
+	if progressReq.PositionMs < 0 {
+		http.Error(rw, "position_ms can't be negative", http.StatusBadRequest)
+		return
+	}
+
+	movie, err := crudl.GetMovie(ctx, ho.QuerierDB, movieID)
+	if err != nil {
+		ho.Logger.Printf("get movie for watch progress: %v", err)
+		http.Error(rw, "movie not found", http.StatusNotFound)
+		return
+	}
+	// Probed duration is trusted over the one reported by player
+	durationMs := movie.DurationMs
+	if durationMs == nil {
+		durationMs = progressReq.DurationMs
+	}
+	// Mark sticks once set, late heartbeat from earlier position doesn't clear it unless movie is rewatched
+	watched := durationMs != nil && *durationMs > 0 &&
+		float64(progressReq.PositionMs) >= WatchedThreshold*float64(*durationMs)
+
+	progressSave := sqlc.SaveWatchProgressParams{
+		UserID:          userTokenData.UserID,
+		MovieID:         movieID,
+		PositionMs:      progressReq.PositionMs,
+		DurationMs:      durationMs,
+		Watched:         watched,
+		Rewatch:         progressReq.Rewatch,
+		ThrottleSeconds: int32(WatchProgressThrottle.Seconds()),
+	}
+	saved, watched, err := crudl.SaveWatchProgress(ctx, ho.QuerierDB, progressSave)
+	if err != nil {
+		ho.Logger.Printf("proceed saving watch progress: %v", err)
+		http.Error(rw, "Can't save watch progress", http.StatusInternalServerError)
+		return
+	}
+	writeResponseBody(rw, reqmodel.WatchProgressSaveResponse{Saved: saved, Watched: watched}, "watch progress")
+}
+
+// @Summary     Get my watch progress
+// @Description Get saved playback position of current user to resume movie
+// @Tags        watch-progress, movie
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       movie_id   	path	string 	true  "Movie ID"
+// @Success     200  {object}  sqlc.WatchProgress
+// @Failure     400  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /movie/{movie_id}/progress [get]
+func (ho *HandlerObj) GetMyWatchProgressHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	progressGet := sqlc.GetWatchProgressParams{UserID: userTokenData.UserID, MovieID: movieID}
+	progress, err := crudl.GetWatchProgress(ctx, ho.QuerierDB, progressGet)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(rw, "movie wasn't watched yet", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		ho.Logger.Printf("proceed getting watch progress: %v", err)
+		http.Error(rw, "Can't get watch progress", http.StatusInternalServerError)
+		return
+	}
+	writeResponseBody(rw, progress, "watch progress")
+}
+
+// @Summary     Delete my watch progress
+// @Description Forget playback position, movie disappears from continue watching list
+// @Tags        watch-progress, movie
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       movie_id   	path	string 	true  "Movie ID"
+// @Success     204
+// @Failure     400  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /movie/{movie_id}/progress [delete]
+func (ho *HandlerObj) DeleteMyWatchProgressHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	var movieID pgtype.UUID
+	if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+		return
+	}
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	progressDelete := sqlc.DeleteWatchProgressParams{UserID: userTokenData.UserID, MovieID: movieID}
+	if err := crudl.DeleteWatchProgress(ctx, ho.QuerierDB, progressDelete); err != nil {
+		ho.Logger.Printf("proceed delete watch progress request: %v", err)
+		http.Error(rw, "Can't delete watch progress", http.StatusNotFound)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary     Get my continue watching list
+// @Description Get started but not watched movies of current user, most recent first
+// @Tags        watch-progress, user
+// @Accept      json
+// @Produce     json
+// @Security	OAuth2Password
+// @Param       limit   	query	int 	false  "Max movies amount, 20 by default, 100 at most"
+// @Success     200  {object}  reqmodel.ContinueWatchingResponse
+// @Failure     400  {object}  map[string]string
+// @Failure     404  {object}  map[string]string
+// @Failure     500  {object}  map[string]string
+// @Router      /user/my/continue-watching [get]
+func (ho *HandlerObj) GetMyContinueWatchingHandler(rw http.ResponseWriter, r *http.Request) {
+	ctx, close := context.WithTimeout(r.Context(), OpTimeContext)
+	defer close()
+
+	userTokenData, err := auth.GetTokenDataContext(ctx)
+	if err != nil {
+		ho.Logger.Println(err)
+		http.Error(rw, "Wrong tokend extractor middleware", http.StatusInternalServerError)
+		return
+	}
+
+	limit := continueWatchingLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			http.Error(rw, "limit should be positive number", http.StatusBadRequest)
+			return
+		}
+		limit = min(limit, continueWatchingMaxSize)
+	}
+
+	continueWatchingGet := sqlc.GetContinueWatchingListParams{UserID: userTokenData.UserID, Limit: int32(limit)}
+	movieList, err := crudl.GetContinueWatchingList(ctx, ho.QuerierDB, continueWatchingGet)
+	if err != nil {
+		ho.Logger.Printf("proceed getting continue watching list: %v", err)
+		http.Error(rw, "Can't get continue watching list", http.StatusNotFound)
+		return
+	}
+	continueWatchingResp := reqmodel.ContinueWatchingResponse{UserID: userTokenData.UserID, MovieList: movieList}
+	writeResponseBody(rw, continueWatchingResp, "continue watching list")
+}
@@ -0,0 +1,333 @@
+// Package ingest pick up video files dropped into watch folder, match them to movies
+// and hand them to transcode workers the same way uploads are
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/media"
+	"movie_backend_go/internal/transcode"
+	"movie_backend_go/pkg/mp4probe"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	DefaultScanInterval = 30 * time.Second
+	scanTimeout         = 5 * time.Minute
+	// Rejected files are moved here so editors see them and they aren't retried every scan
+	FailedDirName = "failed"
+	sidecarExt    = ".json"
+
+	StatusIngested = "ingested"
+	StatusFailed   = "failed"
+	StatusDryRun   = "dry_run"
+
+	ActionCreated = "created"
+	ActionMatched = "matched"
+)
+
+// Transcode worker accepts ISO BMFF files only
+var videoExts = map[string]bool{".mp4": true, ".m4v": true, ".mov": true}
+
+// Sidecar is optional <file name>.json placed next to video, movie_id takes precedence over title
+type Sidecar struct {
+	MovieID *string `json:"movie_id"`
+	Title   *string `json:"title"`
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// Watcher scan watch folder periodically. File is ingested once its size and modification
+// time stay the same between two scans, so files still being copied are left alone
+type Watcher struct {
+	Querier  sqlc.Querier
+	Logger   *log.Logger
+	Dir      string
+	Interval time.Duration
+	// Dry run logs what would be done without touching files and database rows except the log
+	DryRun bool
+
+	pending map[string]fileState
+	// Dry run leaves files in place, they are logged once per version
+	reported map[string]fileState
+}
+
+func NewWatcher(querier sqlc.Querier, logger *log.Logger, dir string, interval time.Duration, dryRun bool) *Watcher {
+	return &Watcher{
+		Querier:  querier,
+		Logger:   logger,
+		Dir:      dir,
+		Interval: interval,
+		DryRun:   dryRun,
+		pending:  map[string]fileState{},
+		reported: map[string]fileState{},
+	}
+}
+
+// Run scan watch folder until context is done
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		scanCtx, close := context.WithTimeout(ctx, scanTimeout)
+		if err := w.Scan(scanCtx); err != nil {
+			w.Logger.Printf("scan watch folder: %v", err)
+		}
+		close()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan ingest every video file which was stable since previous scan
+func (w *Watcher) Scan(ctx context.Context) error {
+	entries, err := os.ReadDir(w.Dir)
+	if err != nil {
+		return fmt.Errorf("read watch folder: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !videoExts[strings.ToLower(filepath.Ext(name))] {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		seen[name] = true
+
+		state := fileState{size: info.Size(), modTime: info.ModTime()}
+		if prev, ok := w.pending[name]; !ok || prev != state {
+			w.pending[name] = state
+			continue
+		}
+		if w.DryRun && w.reported[name] == state {
+			continue
+		}
+
+		w.ingest(ctx, name, info.Size())
+		delete(w.pending, name)
+		if w.DryRun {
+			w.reported[name] = state
+		}
+	}
+
+	// Forget files removed from folder
+	for name := range w.pending {
+		if !seen[name] {
+			delete(w.pending, name)
+		}
+	}
+	for name := range w.reported {
+		if !seen[name] {
+			delete(w.reported, name)
+		}
+	}
+	return nil
+}
+
+// ingest process single stable file and write its outcome into ingest log
+func (w *Watcher) ingest(ctx context.Context, name string, size int64) {
+	srcPath := filepath.Join(w.Dir, name)
+	logCreate := sqlc.CreateIngestLogParams{FileName: name, FileSize: size, Title: titleFromFileName(name)}
+
+	movieID, action, err := w.process(ctx, srcPath, &logCreate)
+	logCreate.MovieID = movieID
+	if action != "" {
+		logCreate.Action = &action
+	}
+	switch {
+	case err != nil:
+		logCreate.Status = StatusFailed
+		message := err.Error()
+		logCreate.Message = &message
+		if !w.DryRun {
+			w.moveToFailed(name)
+		}
+	case w.DryRun:
+		logCreate.Status = StatusDryRun
+	default:
+		logCreate.Status = StatusIngested
+	}
+
+	if _, err := w.Querier.CreateIngestLog(ctx, logCreate); err != nil {
+		w.Logger.Printf("write ingest log of %s: %v", name, err)
+	}
+	w.Logger.Printf("ingest %s: %s, movie %s", name, logCreate.Status, action)
+}
+
+// process validate file, resolve movie and enqueue transcoding. Return movie and whether it was created or matched
+func (w *Watcher) process(ctx context.Context, srcPath string, logCreate *sqlc.CreateIngestLogParams) (pgtype.UUID, string, error) {
+	sidecar, err := readSidecar(srcPath)
+	if err != nil {
+		return pgtype.UUID{}, "", err
+	}
+	if sidecar.Title != nil && strings.TrimSpace(*sidecar.Title) != "" {
+		logCreate.Title = strings.TrimSpace(*sidecar.Title)
+	}
+
+	if _, err := mp4probe.ProbeFile(srcPath); err != nil {
+		return pgtype.UUID{}, "", fmt.Errorf("file isn't valid mp4: %w", err)
+	}
+
+	movieID, action, err := w.resolveMovie(ctx, sidecar, logCreate.Title)
+	if err != nil {
+		return movieID, action, err
+	}
+	if !movieID.Valid {
+		// Dry run of new movie, nothing to check further
+		return movieID, action, nil
+	}
+
+	jobList, err := w.Querier.GetMovieTranscodeJobList(ctx, movieID)
+	if err != nil {
+		return movieID, action, fmt.Errorf("get movie jobs: %w", err)
+	}
+	for _, job := range jobList {
+		if job.Kind == transcode.KindTranscode && (job.Status == "queued" || job.Status == "running") {
+			return movieID, action, errors.New("movie is being processed already")
+		}
+	}
+	if w.DryRun {
+		return movieID, action, nil
+	}
+
+	uploadPath := media.UploadFilePath(media.UUIDString(movieID))
+	if err := moveFile(srcPath, uploadPath); err != nil {
+		return movieID, action, fmt.Errorf("move file into storage: %w", err)
+	}
+	jobCreate := sqlc.CreateTranscodeJobParams{MovieID: movieID, Kind: transcode.KindTranscode}
+	if _, err := w.Querier.CreateTranscodeJob(ctx, jobCreate); err != nil {
+		// File is back for the next attempt
+		if err := moveFile(uploadPath, srcPath); err != nil {
+			w.Logger.Printf("!!CAN't return %s into watch folder: %v", uploadPath, err)
+		}
+		return movieID, action, fmt.Errorf("enqueue transcode job: %w", err)
+	}
+	os.Remove(srcPath + sidecarExt)
+	return movieID, action, nil
+}
+
+// resolveMovie find movie by sidecar id or title, unknown title creates new movie unless dry run
+func (w *Watcher) resolveMovie(ctx context.Context, sidecar Sidecar, title string) (pgtype.UUID, string, error) {
+	if sidecar.MovieID != nil {
+		var movieID pgtype.UUID
+		if err := movieID.Scan(*sidecar.MovieID); err != nil {
+			return pgtype.UUID{}, "", fmt.Errorf("sidecar movie_id should contain uuid style")
+		}
+		if _, err := w.Querier.GetMovie(ctx, movieID); err != nil {
+			return pgtype.UUID{}, "", fmt.Errorf("sidecar movie %s not found: %w", *sidecar.MovieID, err)
+		}
+		return movieID, ActionMatched, nil
+	}
+
+	movie, err := w.Querier.GetMovieByTitle(ctx, title)
+	if err == nil {
+		return movie.ID, ActionMatched, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return pgtype.UUID{}, "", fmt.Errorf("match movie by title: %w", err)
+	}
+	if w.DryRun {
+		return pgtype.UUID{}, ActionCreated, nil
+	}
+
+	created, err := w.Querier.CreateMovie(ctx, title)
+	if err != nil {
+		return pgtype.UUID{}, "", fmt.Errorf("create movie: %w", err)
+	}
+	return created.ID, ActionCreated, nil
+}
+
+func (w *Watcher) moveToFailed(name string) {
+	failedDir := filepath.Join(w.Dir, FailedDirName)
+	if err := os.MkdirAll(failedDir, 0o755); err != nil {
+		w.Logger.Printf("create failed ingest directory: %v", err)
+		return
+	}
+	for _, fileName := range []string{name, name + sidecarExt} {
+		err := os.Rename(filepath.Join(w.Dir, fileName), filepath.Join(failedDir, fileName))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			w.Logger.Printf("move %s to failed ingest directory: %v", fileName, err)
+		}
+	}
+}
+
+func readSidecar(videoPath string) (Sidecar, error) {
+	var sidecar Sidecar
+	data, err := os.ReadFile(videoPath + sidecarExt)
+	if errors.Is(err, os.ErrNotExist) {
+		return sidecar, nil
+	}
+	if err != nil {
+		return sidecar, fmt.Errorf("read sidecar: %w", err)
+	}
+	if err := json.Unmarshal(data, &sidecar); err != nil {
+		return sidecar, fmt.Errorf("parse sidecar: %w", err)
+	}
+	return sidecar, nil
+}
+
+// titleFromFileName turn "The_Movie.Name.mp4" into "The Movie Name"
+func titleFromFileName(name string) string {
+	title := strings.TrimSuffix(name, filepath.Ext(name))
+	title = strings.NewReplacer("_", " ", ".", " ").Replace(title)
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// moveFile rename file, watch folder is usually another volume so copy is used as fallback
+func moveFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmpPath := dst + ".tmp"
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, dst); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Remove(src)
+}
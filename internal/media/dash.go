@@ -0,0 +1,76 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+const (
+	DASH_MANIFEST      = "manifest.mpd"
+	DashSegmentSeconds = 4
+)
+
+var (
+	dashSegmentRegexp    = regexp.MustCompile(`^(init|chunk)-[0-9]+(-[0-9]+)?\.m4s$`)
+	dashSegmentRefRegexp = regexp.MustCompile(`\.m4s"`)
+)
+
+// Segment names are produced by PackageDash only, anything else is rejected to avoid path traversal
+func IsDashSegmentName(name string) bool {
+	return dashSegmentRegexp.MatchString(name)
+}
+
+// SignDashManifest append query to segment references of manifest,
+// so player requests segments with the same signed query as manifest itself
+func SignDashManifest(manifest []byte, rawQuery string) []byte {
+	if rawQuery == "" {
+		return manifest
+	}
+	ref := []byte(".m4s?" + html.EscapeString(rawQuery) + `"`)
+	return dashSegmentRefRegexp.ReplaceAllLiteral(manifest, ref)
+}
+
+// PackageDash split progressive mp4 into DASH segments without re-encoding,
+// so DASH and progressive stream share the same renditions.
+// Package is built in temporary directory and swapped in place after success.
+func PackageDash(ctx context.Context, srcPath, dstDir string) error {
+	tmpDir := dstDir + ".tmp"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return fmt.Errorf("clean temporary dash directory: %w", err)
+	}
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return fmt.Errorf("create temporary dash directory: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-i", srcPath,
+		"-map", "0:v:0?", "-map", "0:a?",
+		"-c", "copy",
+		"-f", "dash",
+		"-seg_duration", strconv.Itoa(DashSegmentSeconds),
+		"-use_template", "1",
+		"-use_timeline", "1",
+		"-init_seg_name", "init-$RepresentationID$.m4s",
+		"-media_seg_name", "chunk-$RepresentationID$-$Number%05d$.m4s",
+		filepath.Join(tmpDir, DASH_MANIFEST),
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.RemoveAll(tmpDir)
+		return fmt.Errorf("run ffmpeg dash packaging: %w: %s", err, output)
+	}
+
+	if err := os.RemoveAll(dstDir); err != nil {
+		return fmt.Errorf("remove previous dash package: %w", err)
+	}
+	if err := os.Rename(tmpDir, dstDir); err != nil {
+		return fmt.Errorf("move dash package in place: %w", err)
+	}
+	return nil
+}
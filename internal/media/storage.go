@@ -0,0 +1,42 @@
+package media
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TODO: make proper pathing
+// Get this path from volume
+const MOVIES_PREFIX = "/movie-data"
+
+// Progressive stream served as is by StreamMovie
+func MovieFilePath(movieID string) string {
+	return filepath.Join(MOVIES_PREFIX, movieID+".mp4")
+}
+
+// Raw uploaded file waiting for processing
+func UploadFilePath(movieID string) string {
+	return filepath.Join(MOVIES_PREFIX, "upload", movieID)
+}
+
+// Directory with every derived file of the movie (dash packages, etc.)
+func MovieDir(movieID string) string {
+	return filepath.Join(MOVIES_PREFIX, movieID)
+}
+
+func DashDir(movieID string) string {
+	return filepath.Join(MovieDir(movieID), "dash")
+}
+
+// Additional movie asset (trailer, dub, extra...) served by StreamMovieAsset
+func AssetFilePath(movieID, assetID string) string {
+	return filepath.Join(MovieDir(movieID), "asset", assetID+".mp4")
+}
+
+// UUIDString format id the same way as it comes in request path
+func UUIDString(id pgtype.UUID) string {
+	b := id.Bytes
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
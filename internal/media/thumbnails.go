@@ -0,0 +1,126 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	THUMBNAILS_VTT     = "thumbnails.vtt"
+	ThumbnailInterval  = 10 * time.Second
+	ThumbnailWidth     = 160
+	ThumbnailColumns   = 10
+	ThumbnailRows      = 10
+	thumbnailSpriteFmt = "sprite-%03d.jpg"
+)
+
+var (
+	thumbnailSpriteRegexp    = regexp.MustCompile(`^sprite-[0-9]{3,}\.jpg$`)
+	thumbnailSpriteRefRegexp = regexp.MustCompile(`\.jpg#`)
+)
+
+// Sprite names are produced by GenerateThumbnails only, anything else is rejected to avoid path traversal
+func IsThumbnailSpriteName(name string) bool {
+	return thumbnailSpriteRegexp.MatchString(name)
+}
+
+// SignThumbnailsVTT append query to sprite references of track,
+// so player requests sprites with the same signed query as track itself
+func SignThumbnailsVTT(vtt []byte, rawQuery string) []byte {
+	if rawQuery == "" {
+		return vtt
+	}
+	return thumbnailSpriteRefRegexp.ReplaceAllLiteral(vtt, []byte(".jpg?"+rawQuery+"#"))
+}
+
+func ThumbnailsDir(movieID string) string {
+	return filepath.Join(MovieDir(movieID), "thumbnails")
+}
+
+// ThumbnailHeight keep aspect ratio of video for ThumbnailWidth, rounded to even for encoder
+func ThumbnailHeight(width, height int) int {
+	if width <= 0 || height <= 0 {
+		return ThumbnailWidth * 9 / 16
+	}
+	thumbHeight := (ThumbnailWidth*height/width + 1) &^ 1
+	return max(thumbHeight, 2)
+}
+
+// GenerateThumbnails extract frame every ThumbnailInterval, tile them into sprite sheets
+// and write WebVTT track pointing to frame coordinates inside sprites for seek bar previews.
+// Package is built in temporary directory and swapped in place after success.
+func GenerateThumbnails(ctx context.Context, srcPath, dstDir string, duration time.Duration, width, height int, onProgress func(time.Duration)) error {
+	if duration <= 0 {
+		return fmt.Errorf("unknown video duration")
+	}
+	thumbHeight := ThumbnailHeight(width, height)
+
+	tmpDir := dstDir + ".tmp"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return fmt.Errorf("clean temporary thumbnails directory: %w", err)
+	}
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return fmt.Errorf("create temporary thumbnails directory: %w", err)
+	}
+
+	filter := fmt.Sprintf("fps=1/%d,scale=%d:%d,tile=%dx%d",
+		int(ThumbnailInterval.Seconds()), ThumbnailWidth, thumbHeight, ThumbnailColumns, ThumbnailRows)
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-i", srcPath,
+		"-map", "0:v:0",
+		"-vf", filter,
+		"-q:v", "5",
+		"-start_number", "1",
+		"-progress", "pipe:1", "-nostats",
+		filepath.Join(tmpDir, thumbnailSpriteFmt),
+	)
+	if err := runWithProgress(cmd, onProgress); err != nil {
+		os.RemoveAll(tmpDir)
+		return fmt.Errorf("run ffmpeg thumbnails extraction: %w", err)
+	}
+
+	vtt := thumbnailsVTT(duration, thumbHeight)
+	if err := os.WriteFile(filepath.Join(tmpDir, THUMBNAILS_VTT), vtt, 0o644); err != nil {
+		os.RemoveAll(tmpDir)
+		return fmt.Errorf("write thumbnails track: %w", err)
+	}
+
+	if err := os.RemoveAll(dstDir); err != nil {
+		return fmt.Errorf("remove previous thumbnails: %w", err)
+	}
+	if err := os.Rename(tmpDir, dstDir); err != nil {
+		return fmt.Errorf("move thumbnails in place: %w", err)
+	}
+	return nil
+}
+
+// thumbnailsVTT build cue per extracted frame. Sprite references are relative to track URL
+func thumbnailsVTT(duration time.Duration, thumbHeight int) []byte {
+	const perSprite = ThumbnailColumns * ThumbnailRows
+
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+	for i := 0; time.Duration(i)*ThumbnailInterval < duration; i++ {
+		start := time.Duration(i) * ThumbnailInterval
+		end := min(start+ThumbnailInterval, duration)
+		sprite := fmt.Sprintf(thumbnailSpriteFmt, i/perSprite+1)
+		x := (i % perSprite % ThumbnailColumns) * ThumbnailWidth
+		y := (i % perSprite / ThumbnailColumns) * thumbHeight
+
+		fmt.Fprintf(&b, "\n%s --> %s\n%s#xywh=%d,%d,%d,%d\n",
+			vttTimestamp(start), vttTimestamp(end), sprite, x, y, ThumbnailWidth, thumbHeight)
+	}
+	return []byte(b.String())
+}
+
+func vttTimestamp(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
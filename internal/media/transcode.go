@@ -0,0 +1,69 @@
+package media
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transcode re-encode source into single h264/aac rendition with moov atom at the beginning.
+// onProgress receive already encoded media time.
+func Transcode(ctx context.Context, srcPath, dstPath string, onProgress func(time.Duration)) error {
+	tmpPath := dstPath + ".tmp"
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-i", srcPath,
+		"-map", "0:v:0?", "-map", "0:a?",
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "23",
+		"-c:a", "aac", "-b:a", "128k",
+		"-movflags", "+faststart",
+		"-f", "mp4",
+		"-progress", "pipe:1", "-nostats",
+		tmpPath,
+	)
+	if err := runWithProgress(cmd, onProgress); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("run ffmpeg transcoding: %w", err)
+	}
+	if err := os.Rename(tmpPath, dstPath); err != nil {
+		return fmt.Errorf("move transcoded movie in place: %w", err)
+	}
+	return nil
+}
+
+// runWithProgress run ffmpeg started with "-progress pipe:1" and report processed media time
+func runWithProgress(cmd *exec.Cmd, onProgress func(time.Duration)) error {
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("pipe ffmpeg progress: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start ffmpeg: %w", err)
+	}
+
+	// Progress output is key=value lines, out_time_us is encoded position
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok || key != "out_time_us" {
+			continue
+		}
+		us, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		onProgress(time.Duration(us) * time.Microsecond)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("%w: %s", err, stderr.String())
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+// Package mediagc reconcile media storage with database: files nobody references are reported
+// and optionally deleted, rows referencing missing files are flagged
+package mediagc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/media"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// Uploads and transcodes in progress aren't referenced yet, fresh files are never orphans
+	DefaultGracePeriod = 72 * time.Hour
+	uploadDirName      = "upload"
+	assetDirName       = "asset"
+)
+
+var ErrRunning = errors.New("media garbage collection is already running")
+
+type Orphan struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Deleted bool      `json:"deleted"`
+}
+
+type MissingFile struct {
+	MovieID pgtype.UUID `json:"movie_id"`
+	// Set for movie assets only
+	AssetID pgtype.UUID `json:"asset_id"`
+	Path    string      `json:"path"`
+}
+
+type Report struct {
+	StartedAt    time.Time     `json:"started_at"`
+	FinishedAt   time.Time     `json:"finished_at"`
+	DeleteMode   bool          `json:"delete_mode"`
+	Orphans      []Orphan      `json:"orphans"`
+	OrphanBytes  int64         `json:"orphan_bytes"`
+	MissingFiles []MissingFile `json:"missing_files"`
+	Errors       []string      `json:"errors"`
+}
+
+// Collector is safe to share between scheduler and handlers, runs don't overlap
+type Collector struct {
+	Querier     sqlc.Querier
+	Root        string
+	GracePeriod time.Duration
+	// Orphans are only reported unless Delete is set
+	Delete bool
+
+	running sync.Mutex
+	mu      sync.Mutex
+	last    *Report
+}
+
+func NewCollector(querier sqlc.Querier, gracePeriod time.Duration, delete bool) *Collector {
+	return &Collector{Querier: querier, Root: media.MOVIES_PREFIX, GracePeriod: gracePeriod, Delete: delete}
+}
+
+// LastReport return report of the latest finished run
+func (c *Collector) LastReport() (Report, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.last == nil {
+		return Report{}, false
+	}
+	return *c.last, true
+}
+
+// references is everything database knows about storage
+type references struct {
+	movies         map[string]bool
+	paths          map[string]bool
+	pendingUploads map[string]bool
+}
+
+func (c *Collector) loadReferences(ctx context.Context) (references, []sqlc.GetMovieFileListRow, []sqlc.GetMovieAssetFileListRow, error) {
+	refs := references{movies: map[string]bool{}, paths: map[string]bool{}, pendingUploads: map[string]bool{}}
+
+	movieList, err := c.Querier.GetMovieFileList(ctx)
+	if err != nil {
+		return refs, nil, nil, fmt.Errorf("get movie file list: %w", err)
+	}
+	for _, movie := range movieList {
+		refs.movies[media.UUIDString(movie.ID)] = true
+		if movie.MoviePath != nil {
+			refs.paths[filepath.Clean(*movie.MoviePath)] = true
+		}
+	}
+
+	assetList, err := c.Querier.GetMovieAssetFileList(ctx)
+	if err != nil {
+		return refs, nil, nil, fmt.Errorf("get movie asset file list: %w", err)
+	}
+	for _, asset := range assetList {
+		if asset.AssetPath != nil {
+			refs.paths[filepath.Clean(*asset.AssetPath)] = true
+		}
+	}
+
+	pendingList, err := c.Querier.GetPendingUploadMovieIDList(ctx)
+	if err != nil {
+		return refs, nil, nil, fmt.Errorf("get pending upload list: %w", err)
+	}
+	for _, movieID := range pendingList {
+		refs.pendingUploads[media.UUIDString(movieID)] = true
+	}
+	return refs, movieList, assetList, nil
+}
+
+// Run scan storage once. Storage is read after database, so files created in between are covered by grace period
+func (c *Collector) Run(ctx context.Context) (Report, error) {
+	if !c.running.TryLock() {
+		return Report{}, ErrRunning
+	}
+	defer c.running.Unlock()
+
+	report := Report{StartedAt: time.Now(), DeleteMode: c.Delete, Orphans: []Orphan{}, MissingFiles: []MissingFile{}, Errors: []string{}}
+	// Unmounted volume would flag every movie missing
+	if _, err := os.Stat(c.Root); err != nil {
+		return report, fmt.Errorf("storage isn't available: %w", err)
+	}
+	refs, movieList, assetList, err := c.loadReferences(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	c.flagMissing(ctx, &report, movieList, assetList)
+	for _, candidate := range c.findOrphans(&report, refs) {
+		if time.Since(candidate.ModTime) < c.GracePeriod {
+			continue
+		}
+		if c.Delete {
+			if err := os.RemoveAll(candidate.Path); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("delete %s: %v", candidate.Path, err))
+			} else {
+				candidate.Deleted = true
+			}
+		}
+		report.Orphans = append(report.Orphans, candidate)
+		report.OrphanBytes += candidate.Size
+	}
+	report.FinishedAt = time.Now()
+
+	c.mu.Lock()
+	c.last = &report
+	c.mu.Unlock()
+	return report, nil
+}
+
+// flagMissing stat every referenced file and update flags which changed
+func (c *Collector) flagMissing(ctx context.Context, report *Report, movieList []sqlc.GetMovieFileListRow, assetList []sqlc.GetMovieAssetFileListRow) {
+	for _, movie := range movieList {
+		if movie.MoviePath == nil {
+			continue
+		}
+		missing := !fileExists(*movie.MoviePath)
+		if missing {
+			report.MissingFiles = append(report.MissingFiles, MissingFile{MovieID: movie.ID, Path: *movie.MoviePath})
+		}
+		if missing == movie.FileMissing {
+			continue
+		}
+		flagSet := sqlc.SetMovieFileMissingParams{ID: movie.ID, FileMissing: missing}
+		if err := c.Querier.SetMovieFileMissing(ctx, flagSet); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("flag movie %s: %v", media.UUIDString(movie.ID), err))
+		}
+	}
+
+	for _, asset := range assetList {
+		if asset.AssetPath == nil {
+			continue
+		}
+		missing := !fileExists(*asset.AssetPath)
+		if missing {
+			report.MissingFiles = append(report.MissingFiles, MissingFile{MovieID: asset.MovieID, AssetID: asset.ID, Path: *asset.AssetPath})
+		}
+		if missing == asset.FileMissing {
+			continue
+		}
+		flagSet := sqlc.SetMovieAssetFileMissingParams{ID: asset.ID, FileMissing: missing}
+		if err := c.Querier.SetMovieAssetFileMissing(ctx, flagSet); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("flag movie asset %s: %v", media.UUIDString(asset.ID), err))
+		}
+	}
+}
+
+// findOrphans walk storage layout described in media package. Only entries named after
+// movie ids are considered, anything else in the volume is left alone
+func (c *Collector) findOrphans(report *Report, refs references) []Orphan {
+	var orphans []Orphan
+	entries, err := os.ReadDir(c.Root)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("read storage: %v", err))
+		return nil
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(c.Root, entry.Name())
+		switch {
+		case entry.IsDir() && entry.Name() == uploadDirName:
+			// Raw uploads are kept while transcode job is waiting for them
+			orphans = append(orphans, c.scanDir(report, path, func(name string) bool {
+				return refs.movies[name] && refs.pendingUploads[name]
+			})...)
+		case entry.IsDir():
+			movieID := entry.Name()
+			if !isUUID(movieID) {
+				continue
+			}
+			if !refs.movies[movieID] {
+				orphans = appendOrphan(report, orphans, path)
+				continue
+			}
+			// Derived packages are rebuilt in place, only asset files are referenced one by one
+			orphans = append(orphans, c.scanDir(report, filepath.Join(path, assetDirName), func(name string) bool {
+				return refs.paths[filepath.Join(path, assetDirName, name)]
+			})...)
+		default:
+			movieID, _, _ := strings.Cut(entry.Name(), ".")
+			if !isUUID(movieID) {
+				continue
+			}
+			// Temporary transcode output of existing movie isn't referenced until it is published
+			if refs.paths[path] || refs.movies[movieID] && refs.pendingUploads[movieID] {
+				continue
+			}
+			orphans = appendOrphan(report, orphans, path)
+		}
+	}
+	return orphans
+}
+
+// scanDir collect entries of dir which aren't kept. Missing dir has nothing to collect
+func (c *Collector) scanDir(report *Report, dir string, keep func(name string) bool) []Orphan {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("read %s: %v", dir, err))
+		return nil
+	}
+
+	var orphans []Orphan
+	for _, entry := range entries {
+		if keep(entry.Name()) {
+			continue
+		}
+		orphans = appendOrphan(report, orphans, filepath.Join(dir, entry.Name()))
+	}
+	return orphans
+}
+
+// appendOrphan measure path, directories are summed up and dated by their newest file
+func appendOrphan(report *Report, orphans []Orphan, path string) []Orphan {
+	orphan := Orphan{Path: path}
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			orphan.Size += info.Size()
+		}
+		if info.ModTime().After(orphan.ModTime) {
+			orphan.ModTime = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("stat %s: %v", path, err))
+		return orphans
+	}
+	return append(orphans, orphan)
+}
+
+// fileExists treat unreadable file as existing, only absent files are flagged
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+func isUUID(s string) bool {
+	var id pgtype.UUID
+	return id.Scan(s) == nil
+}
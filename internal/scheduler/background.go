@@ -5,25 +5,91 @@ import (
 	"log"
 	"time"
 
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/mediagc"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 const (
 	UpdateDBTimeout  = 30 * time.Minute
 	UpdateDBInterval = 4 * time.Hour
+
+	CleanStreamLeasesTimeout  = time.Minute
+	CleanStreamLeasesInterval = time.Hour
+
+	CleanDownloadGrantsTimeout  = time.Minute
+	CleanDownloadGrantsInterval = 24 * time.Hour
+
+	MediaGCTimeout  = time.Hour
+	MediaGCInterval = 24 * time.Hour
 )
 
 func UpdateDBScheduler(pool *pgxpool.Pool, logger *log.Logger) {
 	ticker := time.NewTicker(UpdateDBInterval)
 	defer ticker.Stop()
-	ctx, close := context.WithTimeout(context.Background(), UpdateDBTimeout)
-	defer close()
 
 	for {
 		<-ticker.C
+		// Refresh view count materialized view, rating stats are kept by trigger.
+		// Context is created per run, otherwise it expires before the first tick
+		ctx, close := context.WithTimeout(context.Background(), UpdateDBTimeout)
 		_, err := pool.Exec(ctx, "CALL refresh_mview()")
+		close()
+		if err != nil {
+			logger.Printf("Can't update database: %v", err)
+		}
+	}
+}
+
+// CleanStreamLeasesScheduler delete leases of streams which stopped sending heartbeats.
+// Expired leases don't count against the limit anyway, it only keeps table small
+func CleanStreamLeasesScheduler(querier sqlc.Querier, logger *log.Logger) {
+	ticker := time.NewTicker(CleanStreamLeasesInterval)
+	defer ticker.Stop()
+
+	for {
+		<-ticker.C
+		ctx, close := context.WithTimeout(context.Background(), CleanStreamLeasesTimeout)
+		_, err := querier.DeleteExpiredStreamLeases(ctx)
+		close()
+		if err != nil {
+			logger.Printf("Can't clean expired stream leases: %v", err)
+		}
+	}
+}
+
+// CleanDownloadGrantsScheduler delete expired download grants, download log keeps records of them
+func CleanDownloadGrantsScheduler(querier sqlc.Querier, logger *log.Logger) {
+	ticker := time.NewTicker(CleanDownloadGrantsInterval)
+	defer ticker.Stop()
+
+	for {
+		<-ticker.C
+		ctx, close := context.WithTimeout(context.Background(), CleanDownloadGrantsTimeout)
+		_, err := querier.DeleteExpiredDownloadGrants(ctx)
+		close()
+		if err != nil {
+			logger.Printf("Can't clean expired download grants: %v", err)
+		}
+	}
+}
+
+// MediaGCScheduler reconcile media storage with database and log what was found
+func MediaGCScheduler(collector *mediagc.Collector, logger *log.Logger) {
+	ticker := time.NewTicker(MediaGCInterval)
+	defer ticker.Stop()
+
+	for {
+		<-ticker.C
+		ctx, close := context.WithTimeout(context.Background(), MediaGCTimeout)
+		report, err := collector.Run(ctx)
+		close()
 		if err != nil {
-			logger.Println("Can't update database")
+			logger.Printf("Can't collect orphaned media: %v", err)
+			continue
 		}
+		logger.Printf("Media GC: %d orphans (%d bytes, delete mode %t), %d missing files, %d errors",
+			len(report.Orphans), report.OrphanBytes, report.DeleteMode, len(report.MissingFiles), len(report.Errors))
 	}
 }
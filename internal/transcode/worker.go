@@ -0,0 +1,325 @@
+package transcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"movie_backend_go/db/sqlc"
+	"movie_backend_go/internal/media"
+	"movie_backend_go/pkg/mp4probe"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	DefaultWorkers    = 2
+	PollInterval      = 5 * time.Second
+	JobTimeout        = 6 * time.Hour
+	StaleJobTimeout   = 15 * time.Minute
+	HeartbeatInterval = StaleJobTimeout / 5
+	RetryBaseDelay    = 30 * time.Second
+	ProgressTimeout   = 10 * time.Second
+	progressMinPeriod = 5 * time.Second
+)
+
+// Job kinds
+const (
+	KindTranscode  = "transcode"
+	KindThumbnails = "thumbnails"
+)
+
+// Job stages in processing order
+const (
+	StageProbe      = "probe"
+	StageTranscode  = "transcode"
+	StagePackage    = "package"
+	StageThumbnails = "thumbnails"
+)
+
+// Progress percentage reached when stage begins
+var stageProgress = map[string]int16{
+	StageProbe:      0,
+	StageTranscode:  5,
+	StagePackage:    90,
+	StageThumbnails: 5,
+}
+
+// errJobLost cancel job which was requeued or finished by someone else
+var errJobLost = errors.New("transcode job is no longer owned by this worker")
+
+// RunWorkers start queue workers and stale job watcher in background
+func RunWorkers(querier sqlc.Querier, logger *log.Logger, workers int) {
+	for range workers {
+		go worker(querier, logger)
+	}
+	go staleJobWatcher(querier, logger)
+}
+
+func worker(querier sqlc.Querier, logger *log.Logger) {
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain queue before going idle
+		for processNextJob(querier, logger) {
+		}
+		<-ticker.C
+	}
+}
+
+// staleJobWatcher requeue jobs of workers which stopped sending heartbeats (crash, restart)
+func staleJobWatcher(querier sqlc.Querier, logger *log.Logger) {
+	ticker := time.NewTicker(StaleJobTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		<-ticker.C
+		ctx, close := context.WithTimeout(context.Background(), ProgressTimeout)
+		numReset, err := querier.ResetStaleTranscodeJobs(ctx, int32(StaleJobTimeout.Seconds()))
+		close()
+		if err != nil {
+			logger.Printf("reset stale transcode jobs: %v", err)
+			continue
+		}
+		if numReset > 0 {
+			logger.Printf("%d stale transcode jobs were reset", numReset)
+		}
+	}
+}
+
+// processNextJob claim one queued job and run it. Return false when queue is empty
+func processNextJob(querier sqlc.Querier, logger *log.Logger) bool {
+	ctx, close := context.WithTimeout(context.Background(), JobTimeout)
+	defer close()
+
+	job, err := querier.ClaimTranscodeJob(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false
+	}
+	if err != nil {
+		logger.Printf("claim transcode job: %v", err)
+		return false
+	}
+
+	// Stages may run long without progress, heartbeat keep job from being treated as stale
+	jobCtx, cancelJob := context.WithCancelCause(ctx)
+	heartbeatDone := make(chan struct{})
+	go heartbeat(jobCtx, cancelJob, querier, logger, job, heartbeatDone)
+
+	var jobErr error
+	switch job.Kind {
+	case KindThumbnails:
+		jobErr = runThumbnailsJob(jobCtx, querier, logger, job)
+	default:
+		jobErr = runJob(jobCtx, querier, logger, job)
+	}
+	cancelJob(nil)
+	<-heartbeatDone
+
+	if errors.Is(context.Cause(jobCtx), errJobLost) {
+		logger.Printf("transcode job %v attempt %d abandoned: %v", job.ID, job.Attempts, errJobLost)
+		return true
+	}
+	if jobErr != nil {
+		finishFailedJob(querier, logger, job, jobErr)
+		return true
+	}
+	jobComplete := sqlc.CompleteTranscodeJobParams{ID: job.ID, Attempts: job.Attempts}
+	numComplete, err := querier.CompleteTranscodeJob(ctx, jobComplete)
+	if err != nil {
+		logger.Printf("complete transcode job %v: %v", job.ID, err)
+	} else if numComplete == 0 {
+		logger.Printf("complete transcode job %v attempt %d: %v", job.ID, job.Attempts, errJobLost)
+		return true
+	}
+
+	// Seek bar previews are built from the processed movie
+	if job.Kind == KindTranscode {
+		thumbnailsJob := sqlc.CreateTranscodeJobParams{MovieID: job.MovieID, Kind: KindThumbnails}
+		if _, err := querier.CreateTranscodeJob(ctx, thumbnailsJob); err != nil {
+			logger.Printf("enqueue thumbnails job for movie %v: %v", job.MovieID, err)
+		}
+	}
+	return true
+}
+
+// heartbeat touch running job until ctx is done, cancel job once it was taken away
+func heartbeat(ctx context.Context, cancel context.CancelCauseFunc, querier sqlc.Querier, logger *log.Logger, job sqlc.TranscodeJob, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(HeartbeatInterval)
+	defer ticker.Stop()
+
+	jobHeartbeat := sqlc.HeartbeatTranscodeJobParams{ID: job.ID, Attempts: job.Attempts}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		beatCtx, closeBeat := context.WithTimeout(ctx, ProgressTimeout)
+		numBeat, err := querier.HeartbeatTranscodeJob(beatCtx, jobHeartbeat)
+		closeBeat()
+		if err != nil {
+			logger.Printf("heartbeat transcode job %v: %v", job.ID, err)
+			continue
+		}
+		if numBeat == 0 {
+			cancel(errJobLost)
+			return
+		}
+	}
+}
+
+func finishFailedJob(querier sqlc.Querier, logger *log.Logger, job sqlc.TranscodeJob, jobErr error) {
+	ctx, close := context.WithTimeout(context.Background(), ProgressTimeout)
+	defer close()
+
+	errMsg := jobErr.Error()
+	logger.Printf("transcode job %v attempt %d failed: %s", job.ID, job.Attempts, errMsg)
+
+	if job.Attempts >= job.MaxAttempts {
+		failJob := sqlc.FailTranscodeJobParams{ID: job.ID, LastError: &errMsg, Attempts: job.Attempts}
+		numFail, err := querier.FailTranscodeJob(ctx, failJob)
+		if err != nil {
+			logger.Printf("mark transcode job %v failed: %v", job.ID, err)
+		} else if numFail == 0 {
+			logger.Printf("mark transcode job %v failed: %v", job.ID, errJobLost)
+		}
+		return
+	}
+
+	// Exponential backoff: 30s, 60s, 120s...
+	backoff := RetryBaseDelay << (job.Attempts - 1)
+	retryJob := sqlc.RetryTranscodeJobParams{ID: job.ID, LastError: &errMsg, BackoffSeconds: int32(backoff.Seconds()), Attempts: job.Attempts}
+	numRetry, err := querier.RetryTranscodeJob(ctx, retryJob)
+	if err != nil {
+		logger.Printf("requeue transcode job %v: %v", job.ID, err)
+	} else if numRetry == 0 {
+		logger.Printf("requeue transcode job %v: %v", job.ID, errJobLost)
+	}
+}
+
+// runJob probe, transcode and package uploaded file, then publish it as movie path
+func runJob(ctx context.Context, querier sqlc.Querier, logger *log.Logger, job sqlc.TranscodeJob) error {
+	movieIDStr := media.UUIDString(job.MovieID)
+	uploadPath := media.UploadFilePath(movieIDStr)
+	moviePath := media.MovieFilePath(movieIDStr)
+	reporter := progressReporter{querier: querier, logger: logger, jobID: job.ID, attempts: job.Attempts}
+
+	reporter.report(ctx, StageProbe, stageProgress[StageProbe], true)
+	uploadInfo, err := mp4probe.ProbeFile(uploadPath)
+	if err != nil {
+		return fmt.Errorf("probe: %w", err)
+	}
+	duration := uploadInfo.Duration
+
+	reporter.report(ctx, StageTranscode, stageProgress[StageTranscode], true)
+	transcodeSpan := stageProgress[StagePackage] - stageProgress[StageTranscode]
+	err = media.Transcode(ctx, uploadPath, moviePath, func(done time.Duration) {
+		if duration <= 0 {
+			return
+		}
+		part := min(float64(done)/float64(duration), 1)
+		reporter.report(ctx, StageTranscode, stageProgress[StageTranscode]+int16(part*float64(transcodeSpan)), false)
+	})
+	if err != nil {
+		return fmt.Errorf("transcode: %w", err)
+	}
+
+	reporter.report(ctx, StagePackage, stageProgress[StagePackage], true)
+	if err := media.PackageDash(ctx, moviePath, media.DashDir(movieIDStr)); err != nil {
+		return fmt.Errorf("package: %w", err)
+	}
+
+	// Technical metadata describe served file, not the upload
+	movieInfo, err := mp4probe.ProbeFile(moviePath)
+	if err != nil {
+		return fmt.Errorf("probe transcoded movie: %w", err)
+	}
+	if err := querier.SetMovieMediaInfo(ctx, mediaInfoParams(job.MovieID, movieInfo)); err != nil {
+		return fmt.Errorf("save movie media info: %w", err)
+	}
+
+	moviePathAdd := sqlc.AddMoviePathParams{ID: job.MovieID, MoviePath: &moviePath}
+	if _, err := querier.AddMoviePath(ctx, moviePathAdd); err != nil {
+		return fmt.Errorf("save movie path: %w", err)
+	}
+	// Processed movie is listed among movie assets as the main feature
+	if err := querier.SyncMovieMainAsset(ctx, job.MovieID); err != nil {
+		return fmt.Errorf("save movie main asset: %w", err)
+	}
+
+	if err := os.Remove(uploadPath); err != nil {
+		logger.Printf("remove processed upload %s: %v", uploadPath, err)
+	}
+	return nil
+}
+
+// runThumbnailsJob extract sprite sheets and thumbnails track from processed movie
+func runThumbnailsJob(ctx context.Context, querier sqlc.Querier, logger *log.Logger, job sqlc.TranscodeJob) error {
+	movieIDStr := media.UUIDString(job.MovieID)
+	moviePath := media.MovieFilePath(movieIDStr)
+	reporter := progressReporter{querier: querier, logger: logger, jobID: job.ID, attempts: job.Attempts}
+
+	reporter.report(ctx, StageProbe, stageProgress[StageProbe], true)
+	movieInfo, err := mp4probe.ProbeFile(moviePath)
+	if err != nil {
+		return fmt.Errorf("probe: %w", err)
+	}
+	duration := movieInfo.Duration
+
+	reporter.report(ctx, StageThumbnails, stageProgress[StageThumbnails], true)
+	thumbnailsSpan := 100 - stageProgress[StageThumbnails]
+	err = media.GenerateThumbnails(ctx, moviePath, media.ThumbnailsDir(movieIDStr), duration, movieInfo.Width, movieInfo.Height, func(done time.Duration) {
+		part := min(float64(done)/float64(duration), 1)
+		reporter.report(ctx, StageThumbnails, stageProgress[StageThumbnails]+int16(part*float64(thumbnailsSpan)), false)
+	})
+	if err != nil {
+		return fmt.Errorf("thumbnails: %w", err)
+	}
+	return nil
+}
+
+func mediaInfoParams(movieID pgtype.UUID, info mp4probe.Info) sqlc.SetMovieMediaInfoParams {
+	durationMs := info.Duration.Milliseconds()
+	width := int32(info.Width)
+	height := int32(info.Height)
+	return sqlc.SetMovieMediaInfoParams{
+		ID:         movieID,
+		DurationMs: &durationMs,
+		Width:      &width,
+		Height:     &height,
+		VideoCodec: &info.VideoCodec,
+		AudioCodec: &info.AudioCodec,
+		Bitrate:    &info.Bitrate,
+		Faststart:  &info.FastStart,
+	}
+}
+
+// progressReporter write job progress, skipping updates more frequent than progressMinPeriod
+type progressReporter struct {
+	querier      sqlc.Querier
+	logger       *log.Logger
+	jobID        pgtype.UUID
+	attempts     int32
+	lastProgress int16
+	lastReport   time.Time
+}
+
+func (pr *progressReporter) report(ctx context.Context, stage string, progress int16, force bool) {
+	if !force && (progress == pr.lastProgress || time.Since(pr.lastReport) < progressMinPeriod) {
+		return
+	}
+	pr.lastProgress = progress
+	pr.lastReport = time.Now()
+
+	progressUpdate := sqlc.UpdateTranscodeJobProgressParams{ID: pr.jobID, Stage: stage, Progress: progress, Attempts: pr.attempts}
+	if _, err := pr.querier.UpdateTranscodeJobProgress(ctx, progressUpdate); err != nil {
+		pr.logger.Printf("update transcode job progress: %v", err)
+	}
+}
@@ -8,3 +8,8 @@ var (
 	ErrExpiredToken        = errors.New("Token expired")
 	ErrWrongTokenExtractor = errors.New("CRITICAL: generated token type and expected one are different")
 )
+
+var (
+	ErrInvalidStreamSignature = errors.New("Stream URL signature is invalid")
+	ErrExpiredStreamSignature = errors.New("Stream URL expired")
+)
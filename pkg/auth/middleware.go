@@ -20,12 +20,28 @@ func TokenExtractionMiddleware(next http.Handler) http.Handler {
 			http.Error(rw, "Can't extract token data", http.StatusBadRequest)
 			return
 		}
-		ctx := r.Context()
-		ctx = context.WithValue(ctx, tokenContextKey, userTokenData)
+		ctx := SetTokenDataContext(r.Context(), userTokenData)
 		next.ServeHTTP(rw, r.WithContext(ctx))
 	})
 }
 
+// OptionalTokenExtractionMiddleware put token data into context when request has Authorization header,
+// anonymous requests pass through without it
+func OptionalTokenExtractionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(rw, r)
+			return
+		}
+		TokenExtractionMiddleware(next).ServeHTTP(rw, r)
+	})
+}
+
+// SetTokenDataContext put token data into context for handlers authenticating user themselves
+func SetTokenDataContext(ctx context.Context, userTokenData UserTokenData) context.Context {
+	return context.WithValue(ctx, tokenContextKey, userTokenData)
+}
+
 func GetTokenDataContext(ctx context.Context) (UserTokenData, error) {
 	// Extract token data from request context after middleware call
 	userTokenDataAny := ctx.Value(tokenContextKey)
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	StreamSignKey      = []byte("RandomStreamKeyNeedToChangeLater")
+	STREAM_EXPIRE_TIME = 6 * time.Hour
+)
+
+// Query parameters of signed stream URL
+const (
+	StreamUserParam      = "user_id"
+	StreamExpiresParam   = "expires"
+	StreamSignatureParam = "signature"
+	// Random id of playback minted with every URL, stream leases and watch history are kept per session
+	StreamSessionParam = "session"
+	// Present for admins only, they get higher streaming rate
+	StreamAdminParam = "admin"
+)
+
+// Video players can't send Authorization header, so stream URLs carry HMAC signature
+// bound to user, role, movie, playback session and expiration time instead of bearer token
+func StreamSignature(userID, movieID pgtype.UUID, isAdmin bool, session string, expires int64) string {
+	mac := hmac.New(sha256.New, StreamSignKey)
+	fmt.Fprintf(mac, "%x|%x|%t|%s|%d", userID.Bytes, movieID.Bytes, isAdmin, session, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// StreamQuery build query of signed stream URL. Return query and its expiration time
+func StreamQuery(userID, movieID pgtype.UUID, isAdmin bool) (url.Values, time.Time) {
+	expiresAt := time.Now().Add(STREAM_EXPIRE_TIME)
+	expires := expiresAt.Unix()
+	session := rand.Text()
+
+	query := url.Values{}
+	query.Set(StreamUserParam, uuidString(userID))
+	query.Set(StreamExpiresParam, strconv.FormatInt(expires, 10))
+	if isAdmin {
+		query.Set(StreamAdminParam, "1")
+	}
+	query.Set(StreamSessionParam, session)
+	query.Set(StreamSignatureParam, StreamSignature(userID, movieID, isAdmin, session, expires))
+	return query, expiresAt
+}
+
+// VerifyStreamQuery check signature and expiration of stream URL for movie. Return user URL was signed for
+func VerifyStreamQuery(query url.Values, movieID pgtype.UUID) (UserTokenData, error) {
+	var userID pgtype.UUID
+	if err := userID.Scan(query.Get(StreamUserParam)); err != nil {
+		return UserTokenData{}, ErrInvalidStreamSignature
+	}
+	expires, err := strconv.ParseInt(query.Get(StreamExpiresParam), 10, 64)
+	if err != nil {
+		return UserTokenData{}, ErrInvalidStreamSignature
+	}
+	signature, err := hex.DecodeString(query.Get(StreamSignatureParam))
+	if err != nil {
+		return UserTokenData{}, ErrInvalidStreamSignature
+	}
+	isAdmin := query.Get(StreamAdminParam) == "1"
+	session := query.Get(StreamSessionParam)
+	if session == "" {
+		return UserTokenData{}, ErrInvalidStreamSignature
+	}
+
+	expected, _ := hex.DecodeString(StreamSignature(userID, movieID, isAdmin, session, expires))
+	if !hmac.Equal(signature, expected) {
+		return UserTokenData{}, ErrInvalidStreamSignature
+	}
+	if time.Now().Unix() > expires {
+		return UserTokenData{}, ErrExpiredStreamSignature
+	}
+	return UserTokenData{UserID: userID, IsAdmin: isAdmin}, nil
+}
+
+// StreamSignatureMiddleware verify signed stream URL of {movie_id} route and put user into context
+// the same way as TokenExtractionMiddleware does
+func StreamSignatureMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var movieID pgtype.UUID
+		if err := movieID.Scan(r.PathValue("movie_id")); err != nil {
+			http.Error(rw, "Requested movie id should contain uuid style", http.StatusBadRequest)
+			return
+		}
+
+		userTokenData, err := VerifyStreamQuery(r.URL.Query(), movieID)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusForbidden)
+			return
+		}
+		ctx := SetTokenDataContext(r.Context(), userTokenData)
+		next.ServeHTTP(rw, r.WithContext(ctx))
+	})
+}
+
+func uuidString(id pgtype.UUID) string {
+	b := id.Bytes
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
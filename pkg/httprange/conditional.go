@@ -0,0 +1,66 @@
+package httprange
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETag build strong validator from file size and modification time
+func ETag(size int64, modTime time.Time) string {
+	return fmt.Sprintf(`"%x-%x"`, modTime.UnixNano(), size)
+}
+
+// NotModified evaluate If-None-Match and If-Modified-Since by RFC 7232.
+// If-Modified-Since is ignored when If-None-Match is present.
+func NotModified(r *http.Request, etag string, modTime time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagListMatch(inm, etag)
+	}
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || modTime.IsZero() {
+		return false
+	}
+	imsTime, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	// HTTP dates have second precision
+	return !modTime.Truncate(time.Second).After(imsTime)
+}
+
+// RangeApplies evaluate If-Range: Range is used only while client copy is still current,
+// otherwise whole representation must be sent
+func RangeApplies(r *http.Request, etag string, modTime time.Time) bool {
+	ifRange := r.Header.Get("If-Range")
+	if ifRange == "" {
+		return true
+	}
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, "W/") {
+		// If-Range require strong comparison
+		return !strings.HasPrefix(ifRange, "W/") && ifRange == etag
+	}
+	ifRangeTime, err := http.ParseTime(ifRange)
+	if err != nil {
+		return false
+	}
+	return modTime.Truncate(time.Second).Equal(ifRangeTime)
+}
+
+// etagListMatch check comma separated entity tags list with weak comparison, "*" match any
+func etagListMatch(list, etag string) bool {
+	for candidate := range strings.SplitSeq(list, ",") {
+		candidate = strings.Trim(candidate, " \t")
+		if candidate == "*" {
+			return true
+		}
+		if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
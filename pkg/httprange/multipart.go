@@ -0,0 +1,87 @@
+package httprange
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+)
+
+// Multipart is multipart/byteranges body for several ranges
+type Multipart struct {
+	ranges      []Range
+	contentType string
+	size        int64
+	boundary    string
+}
+
+func NewMultipart(ranges []Range, contentType string, size int64) *Multipart {
+	return &Multipart{
+		ranges:      ranges,
+		contentType: contentType,
+		size:        size,
+		boundary:    multipart.NewWriter(io.Discard).Boundary(),
+	}
+}
+
+func (m *Multipart) ContentType() string {
+	return "multipart/byteranges; boundary=" + m.boundary
+}
+
+// Length compute exact body size, so Content-Length may be sent before body
+func (m *Multipart) Length() int64 {
+	var counter countingWriter
+	mw := multipart.NewWriter(&counter)
+	mw.SetBoundary(m.boundary)
+	for _, r := range m.ranges {
+		mw.CreatePart(m.partHeader(r))
+		counter.n += r.Length
+	}
+	mw.Close()
+	return counter.n
+}
+
+// WriteTo copy every range from src as separate part
+func (m *Multipart) WriteTo(w io.Writer, src io.ReaderAt) (int64, error) {
+	counter := countingWriter{w: w}
+	mw := multipart.NewWriter(&counter)
+	if err := mw.SetBoundary(m.boundary); err != nil {
+		return counter.n, fmt.Errorf("set multipart boundary: %w", err)
+	}
+	for _, r := range m.ranges {
+		part, err := mw.CreatePart(m.partHeader(r))
+		if err != nil {
+			return counter.n, fmt.Errorf("create range part: %w", err)
+		}
+		if _, err := io.CopyN(part, io.NewSectionReader(src, r.Start, r.Length), r.Length); err != nil {
+			return counter.n, fmt.Errorf("copy range part: %w", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return counter.n, fmt.Errorf("close multipart body: %w", err)
+	}
+	return counter.n, nil
+}
+
+func (m *Multipart) partHeader(r Range) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		"Content-Type":  {m.contentType},
+		"Content-Range": {r.ContentRange(m.size)},
+	}
+}
+
+// countingWriter count written bytes, nil w discard them
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.w == nil {
+		cw.n += int64(len(p))
+		return len(p), nil
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,130 @@
+package httprange
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalid     = errors.New("invalid range header")
+	ErrUnsatisfied = errors.New("range not satisfiable")
+)
+
+// More ranges in one request is rather abuse than real player behavior
+const MaxRanges = 32
+
+// Range is satisfiable byte range of representation
+type Range struct {
+	Start  int64
+	Length int64
+	// Client omitted last-byte-pos ("bytes=START-"), server may send shorter part
+	OpenEnded bool
+}
+
+func (r Range) End() int64 {
+	return r.Start + r.Length - 1
+}
+
+func (r Range) ContentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.Start, r.End(), size)
+}
+
+// Parse Range header value by RFC 7233 for representation of given size.
+// Unsatisfiable ranges are dropped, ErrUnsatisfied is returned when nothing left.
+// Nil ranges without error mean header should be ignored and whole representation served.
+func Parse(header string, size int64) ([]Range, error) {
+	const unit = "bytes="
+	if len(header) < len(unit) || !strings.EqualFold(header[:len(unit)], unit) {
+		return nil, ErrInvalid
+	}
+
+	var (
+		ranges     []Range
+		specCount  int
+		sumLengths int64
+	)
+	for spec := range strings.SplitSeq(header[len(unit):], ",") {
+		spec = strings.Trim(spec, " \t")
+		if spec == "" {
+			continue
+		}
+		specCount++
+		if specCount > MaxRanges {
+			return nil, ErrInvalid
+		}
+
+		startStr, endStr, ok := strings.Cut(spec, "-")
+		if !ok {
+			return nil, ErrInvalid
+		}
+
+		var r Range
+		if startStr == "" {
+			// Suffix range: last N bytes
+			suffix, err := parsePosition(endStr)
+			if err != nil {
+				return nil, err
+			}
+			if suffix == 0 || size == 0 {
+				continue
+			}
+			r.Length = min(suffix, size)
+			r.Start = size - r.Length
+		} else {
+			start, err := parsePosition(startStr)
+			if err != nil {
+				return nil, err
+			}
+			end := size - 1
+			if endStr == "" {
+				r.OpenEnded = true
+			} else {
+				end, err = parsePosition(endStr)
+				if err != nil {
+					return nil, err
+				}
+				if end < start {
+					return nil, ErrInvalid
+				}
+			}
+			if start >= size {
+				continue
+			}
+			r.Start = start
+			r.Length = min(end, size-1) - start + 1
+		}
+		ranges = append(ranges, r)
+		sumLengths += r.Length
+	}
+
+	if specCount == 0 {
+		return nil, ErrInvalid
+	}
+	if len(ranges) == 0 {
+		return nil, ErrUnsatisfied
+	}
+	// Overlapping ranges requesting more than whole file, cheaper to send it once
+	if sumLengths > size {
+		return nil, nil
+	}
+	return ranges, nil
+}
+
+// parsePosition accept only digits, without signs and spaces allowed by ParseInt
+func parsePosition(s string) (int64, error) {
+	if s == "" {
+		return 0, ErrInvalid
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return 0, ErrInvalid
+		}
+	}
+	pos, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, ErrInvalid
+	}
+	return pos, nil
+}
@@ -0,0 +1,107 @@
+package httprange
+
+import (
+	"errors"
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	seeds := []struct {
+		header string
+		size   int64
+	}{
+		{"bytes=0-499", 10000},
+		{"bytes=500-999", 10000},
+		{"bytes=-500", 10000},
+		{"bytes=9500-", 10000},
+		{"bytes=0-0,-1", 10000},
+		{"bytes=500-600,601-999", 10000},
+		{"bytes=500-700,601-999", 10000},
+		{"BYTES=0-", 1},
+		{"bytes=0-", 0},
+		{"bytes=-0", 100},
+		{"bytes=100-50", 1000},
+		{"bytes=, ,0-1", 10},
+		{"bytes=1-2-3", 10},
+		{"bytes=+1-2", 10},
+		{"bytes=99999999999999999999-", 10},
+		{"items=0-1", 10},
+		{"bytes=", 10},
+	}
+	for _, seed := range seeds {
+		f.Add(seed.header, seed.size)
+	}
+
+	f.Fuzz(func(t *testing.T, header string, size int64) {
+		if size < 0 {
+			t.Skip()
+		}
+		ranges, err := Parse(header, size)
+		if err != nil {
+			if !errors.Is(err, ErrInvalid) && !errors.Is(err, ErrUnsatisfied) {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if ranges != nil {
+				t.Fatalf("ranges %v returned with error %v", ranges, err)
+			}
+			return
+		}
+		if len(ranges) > MaxRanges {
+			t.Fatalf("%d ranges exceed limit", len(ranges))
+		}
+
+		var sum int64
+		for _, r := range ranges {
+			if r.Start < 0 || r.Length <= 0 || r.End() >= size {
+				t.Fatalf("range %+v is out of representation size %d", r, size)
+			}
+			sum += r.Length
+		}
+		if sum > size {
+			t.Fatalf("ranges %v request %d bytes of %d", ranges, sum, size)
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		header string
+		size   int64
+		want   []Range
+		err    error
+	}{
+		{"bytes=0-499", 10000, []Range{{Start: 0, Length: 500}}, nil},
+		{"bytes=-500", 10000, []Range{{Start: 9500, Length: 500}}, nil},
+		{"bytes=-500", 100, []Range{{Start: 0, Length: 100}}, nil},
+		{"bytes=9500-", 10000, []Range{{Start: 9500, Length: 500, OpenEnded: true}}, nil},
+		{"bytes=9500-20000", 10000, []Range{{Start: 9500, Length: 500}}, nil},
+		{"bytes=0-0, -1", 10000, []Range{{Start: 0, Length: 1}, {Start: 9999, Length: 1}}, nil},
+		{"bytes=20000-, 0-1", 10000, []Range{{Start: 0, Length: 2}}, nil},
+		{"bytes=0-9999,0-9999", 10000, nil, nil},
+		{"bytes=10000-", 10000, nil, ErrUnsatisfied},
+		{"bytes=-0", 10000, nil, ErrUnsatisfied},
+		{"bytes=500-100", 10000, nil, ErrInvalid},
+		{"bytes=a-b", 10000, nil, ErrInvalid},
+		{"bytes= -", 10000, nil, ErrInvalid},
+		{"items=0-1", 10000, nil, ErrInvalid},
+		{"bytes 0-1", 10000, nil, ErrInvalid},
+		{"bytes=0-1-2", 10000, nil, ErrInvalid},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.header, tt.size)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q, %d) error = %v, want %v", tt.header, tt.size, err, tt.err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("Parse(%q, %d) = %v, want %v", tt.header, tt.size, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Parse(%q, %d) = %v, want %v", tt.header, tt.size, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package mp4probe
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const boxHeaderSize = 8
+
+// box is ISO BMFF box located by offset in file or inside parent payload
+type box struct {
+	Type       string
+	Offset     int64
+	HeaderSize int64
+	Size       int64 // whole box size including header
+}
+
+func (b box) payloadOffset() int64 {
+	return b.Offset + b.HeaderSize
+}
+
+func (b box) payloadSize() int64 {
+	return b.Size - b.HeaderSize
+}
+
+// readBoxHeader read header at current position. fileEnd is used for boxes with size 0 (till end of file)
+func readBoxHeader(r io.ReadSeeker, offset, fileEnd int64) (box, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return box{}, fmt.Errorf("seek box header: %w", err)
+	}
+	var header [boxHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return box{}, fmt.Errorf("read box header: %w", err)
+	}
+
+	b := box{Type: string(header[4:8]), Offset: offset, HeaderSize: boxHeaderSize}
+	size := int64(binary.BigEndian.Uint32(header[0:4]))
+	switch size {
+	case 0:
+		size = fileEnd - offset
+	case 1:
+		var largeSize [8]byte
+		if _, err := io.ReadFull(r, largeSize[:]); err != nil {
+			return box{}, fmt.Errorf("read box large size: %w", err)
+		}
+		b.HeaderSize += 8
+		size = int64(binary.BigEndian.Uint64(largeSize[:]))
+	}
+	if size < b.HeaderSize || size > fileEnd-offset {
+		return box{}, fmt.Errorf("%w: box %q has size %d", ErrMalformed, b.Type, size)
+	}
+	b.Size = size
+	return b, nil
+}
+
+// childBoxes split in-memory payload of container box into children
+func childBoxes(payload []byte) ([]box, error) {
+	var children []box
+	for offset := int64(0); offset < int64(len(payload)); {
+		if int64(len(payload))-offset < boxHeaderSize {
+			return nil, fmt.Errorf("%w: truncated child box header", ErrMalformed)
+		}
+		b := box{
+			Type:       string(payload[offset+4 : offset+8]),
+			Offset:     offset,
+			HeaderSize: boxHeaderSize,
+			Size:       int64(binary.BigEndian.Uint32(payload[offset : offset+4])),
+		}
+		switch b.Size {
+		case 0:
+			b.Size = int64(len(payload)) - offset
+		case 1:
+			if int64(len(payload))-offset < boxHeaderSize+8 {
+				return nil, fmt.Errorf("%w: truncated child box large size", ErrMalformed)
+			}
+			b.HeaderSize += 8
+			b.Size = int64(binary.BigEndian.Uint64(payload[offset+8 : offset+16]))
+		}
+		if b.Size < b.HeaderSize || b.Size > int64(len(payload))-offset {
+			return nil, fmt.Errorf("%w: child box %q has size %d", ErrMalformed, b.Type, b.Size)
+		}
+		children = append(children, b)
+		offset += b.Size
+	}
+	return children, nil
+}
+
+// findChild return payload of first child with given type
+func findChild(payload []byte, boxType string) ([]byte, bool, error) {
+	children, err := childBoxes(payload)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, child := range children {
+		if child.Type == boxType {
+			return payload[child.payloadOffset() : child.Offset+child.Size], true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// findPath walk nested containers like "mdia/minf/stbl"
+func findPath(payload []byte, path ...string) ([]byte, bool, error) {
+	for _, boxType := range path {
+		child, ok, err := findChild(payload, boxType)
+		if err != nil || !ok {
+			return nil, ok, err
+		}
+		payload = child
+	}
+	return payload, true, nil
+}
@@ -0,0 +1,327 @@
+package mp4probe
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+var (
+	ErrNotMP4    = errors.New("file is not mp4: ftyp box wasn't found")
+	ErrNoMoov    = errors.New("moov box wasn't found")
+	ErrMalformed = errors.New("malformed mp4 box")
+)
+
+// moov is read into memory, real files keep it in few megabytes
+const maxMoovSize = 64 * 1024 * 1024
+
+type Info struct {
+	Duration   time.Duration
+	Width      int
+	Height     int
+	VideoCodec string
+	AudioCodec string
+	// Bits per second of media data
+	Bitrate int64
+	// moov box placed before media data, so playback may start before whole file is loaded
+	FastStart bool
+}
+
+func ProbeFile(path string) (Info, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Info{}, fmt.Errorf("open mp4 file: %w", err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return Info{}, fmt.Errorf("stat mp4 file: %w", err)
+	}
+	return Probe(file, stat.Size())
+}
+
+// Probe parse top level boxes layout and moov box metadata
+func Probe(r io.ReadSeeker, size int64) (Info, error) {
+	var (
+		moov       box
+		foundFtyp  bool
+		foundMoov  bool
+		mdatOffset int64 = -1
+		mdatSize   int64
+	)
+	for offset := int64(0); offset < size; {
+		b, err := readBoxHeader(r, offset, size)
+		if err != nil {
+			if !foundFtyp {
+				return Info{}, ErrNotMP4
+			}
+			return Info{}, err
+		}
+		switch b.Type {
+		case "ftyp":
+			foundFtyp = true
+		case "moov":
+			if !foundMoov {
+				moov = b
+				foundMoov = true
+			}
+		case "mdat":
+			if mdatOffset < 0 {
+				mdatOffset = b.Offset
+			}
+			mdatSize += b.payloadSize()
+		}
+		if !foundFtyp && b.Type != "free" && b.Type != "skip" && b.Type != "wide" {
+			return Info{}, ErrNotMP4
+		}
+		offset += b.Size
+	}
+	if !foundFtyp {
+		return Info{}, ErrNotMP4
+	}
+	if !foundMoov {
+		return Info{}, ErrNoMoov
+	}
+	if moov.payloadSize() > maxMoovSize {
+		return Info{}, fmt.Errorf("%w: moov box is too large", ErrMalformed)
+	}
+
+	payload := make([]byte, moov.payloadSize())
+	if _, err := r.Seek(moov.payloadOffset(), io.SeekStart); err != nil {
+		return Info{}, fmt.Errorf("seek moov box: %w", err)
+	}
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return Info{}, fmt.Errorf("read moov box: %w", err)
+	}
+
+	info, err := parseMoov(payload)
+	if err != nil {
+		return Info{}, err
+	}
+	info.FastStart = mdatOffset < 0 || moov.Offset < mdatOffset
+	if info.Duration > 0 {
+		info.Bitrate = int64(float64(mdatSize*8) / info.Duration.Seconds())
+	}
+	return info, nil
+}
+
+func parseMoov(payload []byte) (Info, error) {
+	var info Info
+
+	mvhd, ok, err := findChild(payload, "mvhd")
+	if err != nil {
+		return Info{}, err
+	}
+	if !ok {
+		return Info{}, fmt.Errorf("%w: mvhd box wasn't found", ErrMalformed)
+	}
+	info.Duration, err = parseMvhdDuration(mvhd)
+	if err != nil {
+		return Info{}, err
+	}
+
+	children, err := childBoxes(payload)
+	if err != nil {
+		return Info{}, err
+	}
+	for _, child := range children {
+		if child.Type != "trak" {
+			continue
+		}
+		trak := payload[child.payloadOffset() : child.Offset+child.Size]
+		if err := parseTrak(trak, &info); err != nil {
+			return Info{}, err
+		}
+	}
+	return info, nil
+}
+
+func parseMvhdDuration(mvhd []byte) (time.Duration, error) {
+	var timescale, duration uint64
+	switch {
+	case len(mvhd) >= 32 && mvhd[0] == 1:
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[20:24]))
+		duration = binary.BigEndian.Uint64(mvhd[24:32])
+	case len(mvhd) >= 20 && mvhd[0] == 0:
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(mvhd[16:20]))
+	default:
+		return 0, fmt.Errorf("%w: unsupported mvhd box", ErrMalformed)
+	}
+	if timescale == 0 {
+		return 0, nil
+	}
+	seconds := float64(duration) / float64(timescale)
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// parseTrak fill codec and resolution of the first video and audio tracks
+func parseTrak(trak []byte, info *Info) error {
+	hdlr, ok, err := findPath(trak, "mdia", "hdlr")
+	if err != nil || !ok || len(hdlr) < 12 {
+		return err
+	}
+	handlerType := string(hdlr[8:12])
+	if handlerType != "vide" && handlerType != "soun" {
+		return nil
+	}
+
+	stsd, ok, err := findPath(trak, "mdia", "minf", "stbl", "stsd")
+	if err != nil || !ok || len(stsd) < 8 {
+		return err
+	}
+	entries, err := childBoxes(stsd[8:])
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+	entry := entries[0]
+	entryPayload := stsd[8+entry.payloadOffset() : 8+entry.Offset+entry.Size]
+
+	switch handlerType {
+	case "vide":
+		if info.VideoCodec != "" {
+			return nil
+		}
+		info.VideoCodec = videoCodec(entry.Type, entryPayload)
+		info.Width, info.Height = trackResolution(trak, entryPayload)
+	case "soun":
+		if info.AudioCodec != "" {
+			return nil
+		}
+		info.AudioCodec = audioCodec(entry.Type, entryPayload)
+	}
+	return nil
+}
+
+// trackResolution prefer tkhd presentation size, fallback to coded size of sample entry
+func trackResolution(trak, entryPayload []byte) (int, int) {
+	tkhd, ok, err := findChild(trak, "tkhd")
+	if err == nil && ok {
+		sizeOffset := 76
+		if len(tkhd) > 0 && tkhd[0] == 1 {
+			sizeOffset = 88
+		}
+		if len(tkhd) >= sizeOffset+8 {
+			width := int(binary.BigEndian.Uint32(tkhd[sizeOffset:sizeOffset+4]) >> 16)
+			height := int(binary.BigEndian.Uint32(tkhd[sizeOffset+4:sizeOffset+8]) >> 16)
+			if width > 0 && height > 0 {
+				return width, height
+			}
+		}
+	}
+	if len(entryPayload) >= 28 {
+		return int(binary.BigEndian.Uint16(entryPayload[24:26])), int(binary.BigEndian.Uint16(entryPayload[26:28]))
+	}
+	return 0, 0
+}
+
+// Size of VisualSampleEntry fields before child boxes
+const visualSampleEntrySize = 78
+
+// videoCodec build RFC 6381 codec string when configuration is known
+func videoCodec(fourcc string, entryPayload []byte) string {
+	if (fourcc != "avc1" && fourcc != "avc3") || len(entryPayload) < visualSampleEntrySize {
+		return fourcc
+	}
+	avcC, ok, err := findChild(entryPayload[visualSampleEntrySize:], "avcC")
+	if err != nil || !ok || len(avcC) < 4 {
+		return fourcc
+	}
+	return fmt.Sprintf("%s.%02x%02x%02x", fourcc, avcC[1], avcC[2], avcC[3])
+}
+
+// audioCodec build RFC 6381 codec string for AAC, other codecs keep sample entry type
+func audioCodec(fourcc string, entryPayload []byte) string {
+	if fourcc != "mp4a" || len(entryPayload) < 28 {
+		return fourcc
+	}
+	// QuickTime sound description versions extend AudioSampleEntry
+	entrySize := 28
+	switch binary.BigEndian.Uint16(entryPayload[8:10]) {
+	case 1:
+		entrySize += 16
+	case 2:
+		entrySize += 36
+	}
+	if len(entryPayload) < entrySize {
+		return fourcc
+	}
+	esds, ok, err := findChild(entryPayload[entrySize:], "esds")
+	if err != nil || !ok || len(esds) < 4 {
+		return fourcc
+	}
+	objectType, audioObjectType, ok := parseEsds(esds[4:])
+	if !ok {
+		return fourcc
+	}
+	if audioObjectType == 0 {
+		return fmt.Sprintf("mp4a.%x", objectType)
+	}
+	return fmt.Sprintf("mp4a.%x.%d", objectType, audioObjectType)
+}
+
+// parseEsds extract objectTypeIndication and AudioSpecificConfig object type from ES descriptor
+func parseEsds(data []byte) (byte, byte, bool) {
+	tag, body, ok := readDescriptor(data)
+	if !ok || tag != 0x03 || len(body) < 3 {
+		return 0, 0, false
+	}
+	flags := body[2]
+	pos := 3
+	if flags&0x80 != 0 {
+		pos += 2
+	}
+	if flags&0x40 != 0 {
+		if pos >= len(body) {
+			return 0, 0, false
+		}
+		pos += int(body[pos]) + 1
+	}
+	if flags&0x20 != 0 {
+		pos += 2
+	}
+	if pos >= len(body) {
+		return 0, 0, false
+	}
+
+	tag, decoderConfig, ok := readDescriptor(body[pos:])
+	if !ok || tag != 0x04 || len(decoderConfig) < 13 {
+		return 0, 0, false
+	}
+	objectType := decoderConfig[0]
+
+	tag, specificInfo, ok := readDescriptor(decoderConfig[13:])
+	if !ok || tag != 0x05 || len(specificInfo) == 0 {
+		return objectType, 0, true
+	}
+	return objectType, specificInfo[0] >> 3, true
+}
+
+// readDescriptor read MPEG-4 descriptor with variable-length size. Return tag and body
+func readDescriptor(data []byte) (byte, []byte, bool) {
+	if len(data) < 2 {
+		return 0, nil, false
+	}
+	tag := data[0]
+	size := 0
+	pos := 1
+	for i := 0; i < 4; i++ {
+		if pos >= len(data) {
+			return 0, nil, false
+		}
+		b := data[pos]
+		pos++
+		size = size<<7 | int(b&0x7f)
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	if size > len(data)-pos {
+		return 0, nil, false
+	}
+	return tag, data[pos : pos+size], true
+}
@@ -0,0 +1,179 @@
+package mp4probe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+func mkBox(boxType string, payload ...[]byte) []byte {
+	data := bytes.Join(payload, nil)
+	header := make([]byte, boxHeaderSize, boxHeaderSize+len(data))
+	binary.BigEndian.PutUint32(header[0:4], uint32(boxHeaderSize+len(data)))
+	copy(header[4:8], boxType)
+	return append(header, data...)
+}
+
+func be32(values ...uint32) []byte {
+	data := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.BigEndian.PutUint32(data[4*i:], v)
+	}
+	return data
+}
+
+func mkMvhd(timescale, duration uint32) []byte {
+	return mkBox("mvhd", be32(0, 0, 0, timescale, duration), make([]byte, 80))
+}
+
+func mkTrak(handlerType string, tkhd, entry []byte) []byte {
+	hdlr := mkBox("hdlr", be32(0, 0), []byte(handlerType), make([]byte, 12))
+	stsd := mkBox("stsd", be32(0, 1), entry)
+	stbl := mkBox("stbl", stsd)
+	minf := mkBox("minf", stbl)
+	mdia := mkBox("mdia", hdlr, minf)
+	if tkhd == nil {
+		return mkBox("trak", mdia)
+	}
+	return mkBox("trak", tkhd, mdia)
+}
+
+// videoTrak has avc1 High@3.1 entry coded as 640x360 and presented as 1280x720
+func videoTrak() []byte {
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:80], 1280<<16)
+	binary.BigEndian.PutUint32(tkhd[80:84], 720<<16)
+
+	entry := make([]byte, visualSampleEntrySize)
+	binary.BigEndian.PutUint16(entry[24:26], 640)
+	binary.BigEndian.PutUint16(entry[26:28], 360)
+	avcC := mkBox("avcC", []byte{1, 0x64, 0x00, 0x1f})
+	return mkTrak("vide", mkBox("tkhd", tkhd), mkBox("avc1", entry, avcC))
+}
+
+// audioTrak has AAC LC entry
+func audioTrak() []byte {
+	decoderSpecific := []byte{0x05, 2, 0x12, 0x10}
+	decoderConfig := append([]byte{0x04, byte(13 + len(decoderSpecific)), 0x40}, make([]byte, 12)...)
+	decoderConfig = append(decoderConfig, decoderSpecific...)
+	esDescriptor := append([]byte{0x03, byte(3 + len(decoderConfig)), 0, 1, 0}, decoderConfig...)
+	esds := mkBox("esds", be32(0), esDescriptor)
+	return mkTrak("soun", nil, mkBox("mp4a", make([]byte, 28), esds))
+}
+
+var (
+	ftyp = mkBox("ftyp", []byte("isom"), be32(0x200), []byte("isomavc1"))
+	moov = mkBox("moov", mkMvhd(1000, 10000), videoTrak(), audioTrak())
+	mdat = mkBox("mdat", make([]byte, 1000))
+	// Fragmented movie keeps samples in moof/mdat pairs, moov has no duration
+	fragmentedMoov = mkBox("moov", mkMvhd(1000, 0), videoTrak(), mkBox("mvex", mkBox("trex", make([]byte, 24))))
+	moof           = mkBox("moof", mkBox("mfhd", be32(0, 1)))
+)
+
+func join(boxes ...[]byte) []byte {
+	return bytes.Join(boxes, nil)
+}
+
+// boundedReader fail test when Probe seeks outside of given size
+type boundedReader struct {
+	*bytes.Reader
+	t    *testing.T
+	size int64
+}
+
+func (br boundedReader) Seek(offset int64, whence int) (int64, error) {
+	pos, err := br.Reader.Seek(offset, whence)
+	if err == nil && (pos < 0 || pos > br.size) {
+		br.t.Fatalf("seek to %d is out of size %d", pos, br.size)
+	}
+	return pos, err
+}
+
+func FuzzProbe(f *testing.F) {
+	seeds := [][]byte{
+		join(ftyp, moov, mdat),
+		join(ftyp, mdat, moov),
+		join(ftyp, fragmentedMoov, moof, mdat, moof, mdat),
+		join(ftyp, moov[:len(moov)-10]),
+		join(ftyp, mkBox("moov", mkMvhd(1000, 10000), mkBox("trak", be32(0xffff)))),
+		join(ftyp, []byte{0, 0, 0, 1, 'm', 'd', 'a', 't', 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}),
+		{},
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		size := int64(len(data))
+		r := boundedReader{Reader: bytes.NewReader(data), t: t, size: size}
+		info, err := Probe(r, size)
+		if err != nil {
+			return
+		}
+		if info.Width < 0 || info.Height < 0 || info.Bitrate < 0 {
+			t.Fatalf("probe returned negative values %+v", info)
+		}
+	})
+}
+
+func TestProbe(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want Info
+		err  error
+	}{
+		{
+			name: "faststart",
+			data: join(ftyp, moov, mdat),
+			want: Info{Duration: 10 * time.Second, Width: 1280, Height: 720, VideoCodec: "avc1.64001f", AudioCodec: "mp4a.40.2", Bitrate: 800, FastStart: true},
+		},
+		{
+			name: "moov at end",
+			data: join(ftyp, mdat, moov),
+			want: Info{Duration: 10 * time.Second, Width: 1280, Height: 720, VideoCodec: "avc1.64001f", AudioCodec: "mp4a.40.2", Bitrate: 800},
+		},
+		{
+			name: "fragmented",
+			data: join(ftyp, fragmentedMoov, moof, mdat, moof, mdat),
+			want: Info{Width: 1280, Height: 720, VideoCodec: "avc1.64001f", FastStart: true},
+		},
+		{
+			name: "truncated top level box",
+			data: join(ftyp, moov[:len(moov)-10]),
+			err:  ErrMalformed,
+		},
+		{
+			name: "truncated child box",
+			data: join(ftyp, mkBox("moov", mkMvhd(1000, 10000), mkBox("trak", be32(0xffff)))),
+			err:  ErrMalformed,
+		},
+		{
+			name: "large size beyond file",
+			data: join(ftyp, []byte{0, 0, 0, 1, 'm', 'd', 'a', 't', 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}),
+			err:  ErrMalformed,
+		},
+		{
+			name: "no ftyp",
+			data: join(moov, mdat),
+			err:  ErrNotMP4,
+		},
+		{
+			name: "no moov",
+			data: join(ftyp, mdat),
+			err:  ErrNoMoov,
+		},
+	}
+	for _, tt := range tests {
+		got, err := Probe(bytes.NewReader(tt.data), int64(len(tt.data)))
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: Probe() error = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Probe() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package spoiler parse inline spoiler markup of comments. Spoiler is text between
+// double bars: "the killer is ||the butler||". Unclosed bars are kept as plain text
+package spoiler
+
+import "strings"
+
+const (
+	Delimiter = "||"
+
+	SegmentText    = "text"
+	SegmentSpoiler = "spoiler"
+	// Placeholder hidden spoilers are replaced with in plain text
+	Placeholder = "[spoiler]"
+)
+
+type Segment struct {
+	// text or spoiler
+	Type string `json:"type"`
+	Text string `json:"text"`
+	// Set when spoiler text was removed from response
+	Hidden bool `json:"hidden,omitempty"`
+}
+
+// Parse split text into plain and spoiler segments, empty spoilers are dropped
+func Parse(text string) []Segment {
+	segments := []Segment{}
+	for text != "" {
+		start := strings.Index(text, Delimiter)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(text[start+len(Delimiter):], Delimiter)
+		if end < 0 {
+			break
+		}
+		end += start + len(Delimiter)
+
+		if start > 0 {
+			segments = append(segments, Segment{Type: SegmentText, Text: text[:start]})
+		}
+		if hidden := text[start+len(Delimiter) : end]; strings.TrimSpace(hidden) != "" {
+			segments = append(segments, Segment{Type: SegmentSpoiler, Text: hidden})
+		}
+		text = text[end+len(Delimiter):]
+	}
+	if text != "" {
+		segments = append(segments, Segment{Type: SegmentText, Text: text})
+	}
+	return segments
+}
+
+// Contains report whether text has at least one spoiler
+func Contains(text string) bool {
+	for _, segment := range Parse(text) {
+		if segment.Type == SegmentSpoiler {
+			return true
+		}
+	}
+	return false
+}
+
+// Hide remove text of spoiler segments and return plain text with spoilers replaced by placeholder
+func Hide(segments []Segment) ([]Segment, string) {
+	var plain strings.Builder
+	hidden := make([]Segment, len(segments))
+	for i, segment := range segments {
+		if segment.Type == SegmentSpoiler {
+			segment = Segment{Type: SegmentSpoiler, Hidden: true}
+			plain.WriteString(Placeholder)
+		} else {
+			plain.WriteString(segment.Text)
+		}
+		hidden[i] = segment
+	}
+	return hidden, plain.String()
+}
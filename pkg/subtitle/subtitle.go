@@ -0,0 +1,303 @@
+package subtitle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownFormat    = errors.New("subtitle isn't SRT or WebVTT")
+	ErrInvalidTimestamp = errors.New("invalid cue timestamp")
+	ErrInvalidTiming    = errors.New("invalid cue timing")
+	ErrEmpty            = errors.New("subtitle has no cues")
+)
+
+const (
+	FormatSRT    = "srt"
+	FormatWebVTT = "vtt"
+)
+
+var languageRegexp = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// ValidLanguage check BCP 47 language tag shape, like "en" or "pt-BR"
+func ValidLanguage(tag string) bool {
+	return languageRegexp.MatchString(tag)
+}
+
+type Cue struct {
+	ID    string
+	Start time.Duration
+	End   time.Duration
+	// WebVTT cue settings like "align:start line:0", SRT has none
+	Settings string
+	Text     string
+}
+
+// Track is parsed subtitle file
+type Track struct {
+	// WebVTT STYLE and REGION blocks kept as is
+	Blocks []string
+	Cues   []Cue
+}
+
+// Duration return end of the last cue
+func (t Track) Duration() time.Duration {
+	var end time.Duration
+	for _, cue := range t.Cues {
+		end = max(end, cue.End)
+	}
+	return end
+}
+
+// Parse detect SRT or WebVTT format and parse cues. Cue timing is validated
+func Parse(data []byte) (Track, string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	var (
+		track  Track
+		format string
+		err    error
+	)
+	if isWebVTT(text) {
+		format = FormatWebVTT
+		track, err = parseWebVTT(text)
+	} else {
+		format = FormatSRT
+		track, err = parseSRT(text)
+	}
+	if err != nil {
+		return Track{}, "", err
+	}
+	if err := Validate(track.Cues); err != nil {
+		return Track{}, "", err
+	}
+	return track, format, nil
+}
+
+// Validate check cue end is after start and cues are ordered by start time as WebVTT requires
+func Validate(cues []Cue) error {
+	if len(cues) == 0 {
+		return ErrEmpty
+	}
+	for i, cue := range cues {
+		if cue.End <= cue.Start {
+			return fmt.Errorf("%w: cue %d ends at %s before it starts at %s", ErrInvalidTiming, i+1, formatTimestamp(cue.End, '.'), formatTimestamp(cue.Start, '.'))
+		}
+		if i > 0 && cue.Start < cues[i-1].Start {
+			return fmt.Errorf("%w: cue %d starts before previous cue", ErrInvalidTiming, i+1)
+		}
+	}
+	return nil
+}
+
+// WebVTT render track as WebVTT file
+func (t Track) WebVTT() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("WEBVTT\n")
+	for _, block := range t.Blocks {
+		buf.WriteString("\n" + block + "\n")
+	}
+	for _, cue := range t.Cues {
+		buf.WriteString("\n")
+		if cue.ID != "" {
+			buf.WriteString(cue.ID + "\n")
+		}
+		buf.WriteString(formatTimestamp(cue.Start, '.') + " --> " + formatTimestamp(cue.End, '.'))
+		if cue.Settings != "" {
+			buf.WriteString(" " + cue.Settings)
+		}
+		buf.WriteString("\n" + cue.Text + "\n")
+	}
+	return buf.Bytes()
+}
+
+func isWebVTT(text string) bool {
+	if !strings.HasPrefix(text, "WEBVTT") {
+		return false
+	}
+	// Signature is followed by space, tab or newline only
+	rest := text[len("WEBVTT"):]
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\n'
+}
+
+// splitBlocks split text into blank line separated blocks. Return blocks with first line numbers
+func splitBlocks(text string) ([][]string, []int) {
+	var (
+		blocks    [][]string
+		lineNums  []int
+		current   []string
+		startLine int
+	)
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if current != nil {
+				blocks = append(blocks, current)
+				lineNums = append(lineNums, startLine)
+				current = nil
+			}
+			continue
+		}
+		if current == nil {
+			startLine = i + 1
+		}
+		current = append(current, line)
+	}
+	if current != nil {
+		blocks = append(blocks, current)
+		lineNums = append(lineNums, startLine)
+	}
+	return blocks, lineNums
+}
+
+func parseSRT(text string) (Track, error) {
+	var track Track
+	blocks, lineNums := splitBlocks(text)
+	for i, block := range blocks {
+		// Counter line is optional in the wild
+		timingIdx := 0
+		if !strings.Contains(block[0], "-->") {
+			timingIdx = 1
+		}
+		if timingIdx >= len(block) || !strings.Contains(block[timingIdx], "-->") {
+			if i == 0 {
+				return Track{}, ErrUnknownFormat
+			}
+			return Track{}, fmt.Errorf("line %d: %w: cue timing line wasn't found", lineNums[i], ErrInvalidTimestamp)
+		}
+
+		// SRT may append X1:.. Y2:.. coordinates, WebVTT has no equivalent
+		cue, err := parseTiming(block[timingIdx], ',', false)
+		if err != nil {
+			return Track{}, fmt.Errorf("line %d: %w", lineNums[i]+timingIdx, err)
+		}
+		cue.Text = strings.Join(block[timingIdx+1:], "\n")
+		track.Cues = append(track.Cues, cue)
+	}
+	if len(track.Cues) == 0 {
+		return Track{}, ErrEmpty
+	}
+	return track, nil
+}
+
+func parseWebVTT(text string) (Track, error) {
+	var track Track
+	blocks, lineNums := splitBlocks(text)
+	// First block is header with signature
+	for i, block := range blocks[1:] {
+		lineNum := lineNums[i+1]
+		switch {
+		case strings.HasPrefix(block[0], "NOTE"):
+			continue
+		case block[0] == "STYLE" || block[0] == "REGION":
+			if len(track.Cues) > 0 {
+				return Track{}, fmt.Errorf("line %d: %s block after cues", lineNum, block[0])
+			}
+			track.Blocks = append(track.Blocks, strings.Join(block, "\n"))
+			continue
+		}
+
+		timingIdx := 0
+		if !strings.Contains(block[0], "-->") {
+			timingIdx = 1
+		}
+		if timingIdx >= len(block) || !strings.Contains(block[timingIdx], "-->") {
+			return Track{}, fmt.Errorf("line %d: %w: cue timing line wasn't found", lineNum, ErrInvalidTimestamp)
+		}
+
+		cue, err := parseTiming(block[timingIdx], '.', true)
+		if err != nil {
+			return Track{}, fmt.Errorf("line %d: %w", lineNum+timingIdx, err)
+		}
+		if timingIdx == 1 {
+			cue.ID = block[0]
+		}
+		cue.Text = strings.Join(block[timingIdx+1:], "\n")
+		track.Cues = append(track.Cues, cue)
+	}
+	if len(track.Cues) == 0 {
+		return Track{}, ErrEmpty
+	}
+	return track, nil
+}
+
+// parseTiming parse "START --> END [settings]" line
+func parseTiming(line string, fractionSep byte, keepSettings bool) (Cue, error) {
+	startStr, rest, _ := strings.Cut(line, "-->")
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return Cue{}, fmt.Errorf("%w: cue end is missing", ErrInvalidTimestamp)
+	}
+
+	var (
+		cue Cue
+		err error
+	)
+	if cue.Start, err = parseTimestamp(strings.TrimSpace(startStr), fractionSep); err != nil {
+		return Cue{}, err
+	}
+	if cue.End, err = parseTimestamp(fields[0], fractionSep); err != nil {
+		return Cue{}, err
+	}
+	if keepSettings {
+		cue.Settings = strings.Join(fields[1:], " ")
+	}
+	return cue, nil
+}
+
+// parseTimestamp parse "hh:mm:ss,ttt" (SRT) or "[hh:]mm:ss.ttt" (WebVTT)
+func parseTimestamp(s string, fractionSep byte) (time.Duration, error) {
+	clock, fraction, ok := strings.Cut(s, string(fractionSep))
+	if !ok || len(fraction) != 3 || !isDigits(fraction) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidTimestamp, s)
+	}
+	parts := strings.Split(clock, ":")
+	if len(parts) < 2 || len(parts) > 3 || (fractionSep == ',' && len(parts) != 3) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidTimestamp, s)
+	}
+
+	var hours int64
+	if len(parts) == 3 {
+		if len(parts[0]) < 2 || !isDigits(parts[0]) {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidTimestamp, s)
+		}
+		hours, _ = strconv.ParseInt(parts[0], 10, 32)
+		parts = parts[1:]
+	}
+	for _, part := range parts {
+		if len(part) != 2 || !isDigits(part) {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidTimestamp, s)
+		}
+	}
+	minutes, _ := strconv.ParseInt(parts[0], 10, 64)
+	seconds, _ := strconv.ParseInt(parts[1], 10, 64)
+	millis, _ := strconv.ParseInt(fraction, 10, 64)
+	if minutes > 59 || seconds > 59 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidTimestamp, s)
+	}
+
+	return time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second +
+		time.Duration(millis)*time.Millisecond, nil
+}
+
+func formatTimestamp(d time.Duration, fractionSep byte) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, fractionSep, ms%1000)
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,147 @@
+// Package throttle shape byte rate of writers with token buckets
+package throttle
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Writes are split into chunks so a single large write doesn't take the whole burst
+const chunkSize = 32 * 1024
+
+// Bucket is token bucket refilled with rate bytes per second, burst is one second worth of bytes.
+// Tokens may go negative, waiting time of next reservation pays the debt
+type Bucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// NewBucket create bucket for bytesPerSec rate. Nil bucket is returned for non positive rate, it never limits
+func NewBucket(bytesPerSec int64) *Bucket {
+	if bytesPerSec <= 0 {
+		return nil
+	}
+	return &Bucket{rate: float64(bytesPerSec), tokens: float64(bytesPerSec), last: time.Now()}
+}
+
+// reserve take n tokens and return how long caller should wait before sending them
+func (b *Bucket) reserve(n int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*b.rate, b.rate)
+	b.last = now
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Stats is snapshot of limiter counters
+type Stats struct {
+	ActiveConnections int64 `json:"active_connections"`
+	BytesSent         int64 `json:"bytes_sent"`
+	BytesThrottled    int64 `json:"bytes_throttled"`
+	ThrottledWaitMs   int64 `json:"throttled_wait_ms"`
+	GlobalRate        int64 `json:"global_rate"`
+}
+
+// Limiter share global bucket between connections and count throttled bytes
+type Limiter struct {
+	global     *Bucket
+	globalRate int64
+
+	active    atomic.Int64
+	sent      atomic.Int64
+	throttled atomic.Int64
+	waitNs    atomic.Int64
+}
+
+// NewLimiter create limiter with global rate in bytes per second, non positive rate disables global limit
+func NewLimiter(globalRate int64) *Limiter {
+	return &Limiter{global: NewBucket(globalRate), globalRate: max(globalRate, 0)}
+}
+
+func (l *Limiter) Stats() Stats {
+	return Stats{
+		ActiveConnections: l.active.Load(),
+		BytesSent:         l.sent.Load(),
+		BytesThrottled:    l.throttled.Load(),
+		ThrottledWaitMs:   time.Duration(l.waitNs.Load()).Milliseconds(),
+		GlobalRate:        l.globalRate,
+	}
+}
+
+// Writer wrap w with connection bucket of connRate bytes per second and limiter global bucket.
+// Close should be called when connection is done to keep active connections counter correct
+func (l *Limiter) Writer(ctx context.Context, w io.Writer, connRate int64) *Writer {
+	l.active.Add(1)
+	return &Writer{ctx: ctx, w: w, limiter: l, conn: NewBucket(connRate)}
+}
+
+// Writer is rate limited io.Writer. Waiting is interrupted when context is done
+type Writer struct {
+	ctx     context.Context
+	w       io.Writer
+	limiter *Limiter
+	conn    *Bucket
+	closed  bool
+}
+
+func (tw *Writer) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p[:min(len(p), chunkSize)]
+		if err := tw.wait(len(chunk)); err != nil {
+			return written, err
+		}
+		n, err := tw.w.Write(chunk)
+		written += n
+		tw.limiter.sent.Add(int64(n))
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// wait reserve n bytes in both buckets and sleep for the longest of delays
+func (tw *Writer) wait(n int) error {
+	var delay time.Duration
+	if tw.conn != nil {
+		delay = tw.conn.reserve(n)
+	}
+	if tw.limiter.global != nil {
+		delay = max(delay, tw.limiter.global.reserve(n))
+	}
+	if delay <= 0 {
+		return nil
+	}
+
+	tw.limiter.throttled.Add(int64(n))
+	tw.limiter.waitNs.Add(int64(delay))
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-tw.ctx.Done():
+		return tw.ctx.Err()
+	}
+}
+
+func (tw *Writer) Close() error {
+	if !tw.closed {
+		tw.closed = true
+		tw.limiter.active.Add(-1)
+	}
+	return nil
+}